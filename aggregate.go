@@ -0,0 +1,152 @@
+package gegography
+
+import "fmt"
+
+// AggregateFunc is a function summarising a property over several features
+type AggregateFunc int
+
+const (
+	// AggregateCount counts the features, or those having a value for the property if one is given
+	AggregateCount AggregateFunc = iota
+	// AggregateSum adds the numeric values of the property
+	AggregateSum
+	// AggregateMean averages the numeric values of the property
+	AggregateMean
+	// AggregateFirst takes the first value of the property found
+	AggregateFirst
+	// AggregateList collects the values of the property into a []any
+	AggregateList
+)
+
+func (af AggregateFunc) String() string {
+	switch af {
+	case AggregateCount:
+		return "count"
+	case AggregateSum:
+		return "sum"
+	case AggregateMean:
+		return "mean"
+	case AggregateFirst:
+		return "first"
+	case AggregateList:
+		return "list"
+	}
+
+	return "unknown"
+}
+
+// PropertyAggregate summarises a property over a group of features. Every function but AggregateCount needs a
+// property, and operations given an aggregate without one, or with an unknown function, return an error.
+type PropertyAggregate struct {
+	// Property is the name of the property to summarise
+	Property string
+	Func     AggregateFunc
+	// Name is the name of the output property, by default the (prefixed) property name followed by an underscore
+	// and the name of the function, such as "population_sum", or "count" when counting without a property
+	Name string
+}
+
+// name returns the output property name of the aggregate
+func (pa PropertyAggregate) name(prefix string) string {
+	switch {
+	case pa.Name != "":
+		return pa.Name
+	case pa.Property == "":
+		return prefix + pa.Func.String()
+	}
+
+	return prefix + pa.Property + "_" + pa.Func.String()
+}
+
+// validateAggregates checks that every aggregate has a known function and a property for functions needing one
+func validateAggregates(aggregates []PropertyAggregate) error {
+	for _, a := range aggregates {
+		switch a.Func {
+		case AggregateCount:
+		case AggregateSum, AggregateMean, AggregateFirst, AggregateList:
+			if a.Property == "" {
+				return GeoFormatError{Msg: fmt.Sprintf("the %s aggregate needs a property", a.Func)}
+			}
+		default:
+			return GeoFormatError{Msg: fmt.Sprintf("unsupported aggregate function %d", a.Func)}
+		}
+	}
+
+	return nil
+}
+
+// numericValue returns a property value as a number, if it is one
+func numericValue(v any) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	}
+
+	return 0, false
+}
+
+// aggregate computes the aggregate over the properties of the features. Missing and nil values are skipped, as
+// are values which are not numbers when summing or averaging. The mean and first value of nothing are nil, as are
+// aggregates rejected by validateAggregates.
+func (pa PropertyAggregate) aggregate(features []*Feature) any {
+	if pa.Property == "" && pa.Func != AggregateCount {
+		return nil
+	}
+
+	count, sum := 0, 0.0
+	values := make([]any, 0)
+
+	for _, f := range features {
+		if pa.Property == "" {
+			count++
+			continue
+		}
+
+		v, ok := f.Properties[pa.Property]
+		if !ok || v == nil {
+			continue
+		}
+
+		switch pa.Func {
+		case AggregateSum, AggregateMean:
+			if n, ok := numericValue(v); ok {
+				count++
+				sum += n
+			}
+		default:
+			count++
+			values = append(values, v)
+		}
+	}
+
+	switch pa.Func {
+	case AggregateCount:
+		return float64(count)
+	case AggregateSum:
+		return sum
+	case AggregateFirst:
+		if len(values) == 0 {
+			return nil
+		}
+
+		return values[0]
+	case AggregateMean:
+		if count == 0 {
+			return nil
+		}
+
+		return sum / float64(count)
+	case AggregateList:
+		return values
+	}
+
+	return nil
+}
@@ -0,0 +1,464 @@
+package gegography
+
+import (
+	"math"
+	"sort"
+)
+
+// unwrapLongitudes returns a copy of a sequence of longitude/latitude points where every longitude differs from
+// the previous one by at most 180 degrees, by adding or subtracting multiples of 360
+func unwrapLongitudes(pts []Point) MultiPoint {
+	out := make(MultiPoint, 0, len(pts))
+
+	for x := range pts {
+		p := pts[x]
+
+		if x > 0 {
+			prev := out[x-1]
+			p.X += 360 * math.Round((prev.X-p.X)/360)
+		}
+
+		out = append(out, p)
+	}
+
+	return out
+}
+
+// wrapShift returns the multiple of 360 that brings longitude x into [-180, 180]
+func wrapShift(x float64) float64 {
+	if x >= -180 && x <= 180 {
+		return 0
+	}
+
+	return -360 * math.Floor((x+180)/360)
+}
+
+func shiftX(pts MultiPoint, dx float64) MultiPoint {
+	out := make(MultiPoint, len(pts))
+
+	for x := range pts {
+		out[x] = Point{X: pts[x].X + dx, Y: pts[x].Y}
+	}
+
+	return out
+}
+
+// splitLineAtAntimeridian splits a longitude/latitude line into parts which do not cross the antimeridian
+func splitLineAtAntimeridian(line MultiPoint) Polygon {
+	if len(line) < 2 {
+		return Polygon{line}
+	}
+
+	u := unwrapLongitudes(line)
+	parts := make(Polygon, 0)
+	current := MultiPoint{u[0]}
+
+	for x := 1; x < len(u); x++ {
+		a := u[x-1]
+		b := u[x]
+
+		for _, c := range antimeridianCrossings(a.X, b.X) {
+			t := (c - a.X) / (b.X - a.X)
+			cp := Point{X: c, Y: a.Y + t*(b.Y-a.Y)}
+
+			current = append(current, cp)
+			parts = append(parts, current)
+			current = MultiPoint{cp}
+		}
+
+		if !b.equals(current[len(current)-1]) {
+			current = append(current, b)
+		}
+	}
+
+	parts = append(parts, current)
+
+	out := make(Polygon, 0, len(parts))
+	for x := range parts {
+		if len(parts[x]) < 2 {
+			continue
+		}
+
+		out = append(out, shiftX(parts[x], wrapShift(parts[x].Bounds().Center().X)))
+	}
+
+	return out
+}
+
+// antimeridianCrossings returns the odd multiples of 180 strictly between two unwrapped longitudes, in travel order
+func antimeridianCrossings(a, b float64) []float64 {
+	lo, hi := math.Min(a, b), math.Max(a, b)
+	out := make([]float64, 0)
+
+	for c := 360*math.Floor((lo-180)/360) + 180; c < hi; c += 360 {
+		if c > lo {
+			out = append(out, c)
+		}
+	}
+
+	if a > b {
+		for i, j := 0, len(out)-1; i < j; i, j = i+1, j-1 {
+			out[i], out[j] = out[j], out[i]
+		}
+	}
+
+	return out
+}
+
+// unwrapPolygon unwraps the longitudes of every ring in a polygon so that the polygon is continuous, closing
+// rings which encircle a pole along the pole. Degenerate holes are dropped, and a polygon with a degenerate shell
+// gives an empty polygon.
+func unwrapPolygon(p Polygon) Polygon {
+	out := make(Polygon, 0, len(p))
+	var center float64
+
+	for x := range p {
+		ring := ringVertices(p[x])
+		if len(ring) < 3 {
+			if x == 0 {
+				return Polygon{}
+			}
+			continue
+		}
+
+		u := unwrapLongitudes(append(ring, ring[0]))
+		last := u[len(u)-1]
+		u = u[:len(u)-1]
+
+		if last.X != ring[0].X {
+			u = closeAroundPole(u, last)
+		}
+
+		if x == 0 {
+			center = u.Bounds().Center().X
+		} else {
+			u = shiftX(u, 360*math.Round((center-u.Bounds().Center().X)/360))
+		}
+
+		out = append(out, closeRing(u))
+	}
+
+	return out
+}
+
+// closeAroundPole closes an unwrapped ring which travels a full turn around a pole by following the pole
+// from its last longitude back to its first. The pole on the same side as the average latitude is used.
+func closeAroundPole(u MultiPoint, last Point) MultiPoint {
+	var sum float64
+	for x := range u {
+		sum += u[x].Y
+	}
+
+	pole := 90.0
+	if sum < 0 {
+		pole = -90
+	}
+
+	return append(u, last, Point{X: last.X, Y: pole}, Point{X: u[0].X, Y: pole})
+}
+
+// splitPolygonAtAntimeridian splits a longitude/latitude polygon into parts which do not cross the antimeridian
+func splitPolygonAtAntimeridian(p Polygon) MultiPolygon {
+	u := unwrapPolygon(p)
+	if len(u) == 0 {
+		return MultiPolygon{}
+	}
+
+	b := u.Bounds()
+	if b.MinX >= -180 && b.MaxX <= 180 {
+		return MultiPolygon{p}
+	}
+
+	out := make(MultiPolygon, 0)
+	rest := MultiPolygon{u}
+
+	for _, c := range antimeridianCrossings(b.MinX, b.MaxX) {
+		next := make(MultiPolygon, 0)
+
+		for x := range rest {
+			left, right := cutPolygonAtX(rest[x], c)
+			out = append(out, left...)
+			next = append(next, right...)
+		}
+
+		rest = next
+	}
+
+	out = append(out, rest...)
+
+	for x := range out {
+		dx := wrapShift(out[x].Bounds().Center().X)
+
+		for y := range out[x] {
+			out[x][y] = shiftX(out[x][y], dx)
+		}
+	}
+
+	return out
+}
+
+// splitMultiPolygonAtAntimeridian splits every polygon in a longitude/latitude multipolygon at the antimeridian
+func splitMultiPolygonAtAntimeridian(mp MultiPolygon) MultiPolygon {
+	out := make(MultiPolygon, 0, len(mp))
+
+	for x := range mp {
+		out = append(out, splitPolygonAtAntimeridian(mp[x])...)
+	}
+
+	return out
+}
+
+// NormalizeLongitude returns a longitude wrapped into [-180, 180]
+func NormalizeLongitude(lon float64) float64 {
+	return lon + wrapShift(lon)
+}
+
+// NormalizeLongitude returns the point with its longitude wrapped into [-180, 180]
+func (p Point) NormalizeLongitude() Point {
+	return Point{X: NormalizeLongitude(p.X), Y: p.Y}
+}
+
+// NormalizeLongitudes returns a copy of the points with their longitudes wrapped into [-180, 180]
+func (mp MultiPoint) NormalizeLongitudes() MultiPoint {
+	out := make(MultiPoint, len(mp))
+
+	for x := range mp {
+		out[x] = mp[x].NormalizeLongitude()
+	}
+
+	return out
+}
+
+// SplitAntimeridian splits a longitude/latitude line where it crosses the antimeridian, returning the parts as the
+// coordinates of a MultiLineString. Edges are assumed to take the shortest way around the world, so an edge from
+// 170 to -170 crosses the antimeridian. Longitudes are normalised into [-180, 180].
+func (ls LineString) SplitAntimeridian() Polygon {
+	return splitLineAtAntimeridian(MultiPoint(ls))
+}
+
+// SplitAntimeridian splits a longitude/latitude polygon where it crosses the antimeridian. Edges are assumed to take
+// the shortest way around the world, and rings which travel all the way around the world are closed along the pole
+// they encircle. Longitudes are normalised into [-180, 180].
+func (p Polygon) SplitAntimeridian() MultiPolygon {
+	return splitPolygonAtAntimeridian(p)
+}
+
+// SplitAntimeridian splits every polygon of a longitude/latitude multipolygon where it crosses the antimeridian
+func (mp MultiPolygon) SplitAntimeridian() MultiPolygon {
+	return splitMultiPolygonAtAntimeridian(mp)
+}
+
+// SplitAntimeridian splits a longitude/latitude feature in place where it crosses the antimeridian and normalises
+// its longitudes into [-180, 180]. A LineString or Polygon which is split becomes a MultiLineString or MultiPolygon.
+func (f *Feature) SplitAntimeridian() error {
+	switch f.Type {
+	case "Point":
+		f.Coordinates = f.Coordinates.(Point).NormalizeLongitude()
+	case "MultiPoint":
+		f.Coordinates = f.Coordinates.(MultiPoint).NormalizeLongitudes()
+	case "LineString":
+		lines := splitLineAtAntimeridian(f.Coordinates.(MultiPoint))
+		if len(lines) == 1 {
+			f.Coordinates = lines[0]
+		} else {
+			f.Type = "MultiLineString"
+			f.Coordinates = lines
+		}
+	case "MultiLineString":
+		lines := make(Polygon, 0)
+		for _, l := range f.Coordinates.(Polygon) {
+			lines = append(lines, splitLineAtAntimeridian(l)...)
+		}
+		f.Coordinates = lines
+	case "Polygon":
+		mp := splitPolygonAtAntimeridian(f.Coordinates.(Polygon))
+		if len(mp) == 1 {
+			f.Coordinates = mp[0]
+		} else {
+			f.Type = "MultiPolygon"
+			f.Coordinates = mp
+		}
+	case "MultiPolygon":
+		f.Coordinates = splitMultiPolygonAtAntimeridian(f.Coordinates.(MultiPolygon))
+	default:
+		return GeoTypeError{Type: f.Type}
+	}
+
+	return nil
+}
+
+// SplitAntimeridian splits every feature in the collection in place where it crosses the antimeridian
+func (fc *FeatureCollection) SplitAntimeridian() error {
+	for x := range fc.Features {
+		if err := fc.Features[x].SplitAntimeridian(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// geographicBounds combines the bounding boxes of unwrapped longitude/latitude parts into the smallest bounding box
+// on the globe. When the result crosses the antimeridian MinX is greater than MaxX, as in RFC 7946.
+func geographicBounds(parts []BBox) BBox {
+	out := EmptyBBox()
+	intervals := make([][2]float64, 0, len(parts))
+
+	for _, b := range parts {
+		if b.IsEmpty() {
+			continue
+		}
+
+		out.MinY = math.Min(out.MinY, b.MinY)
+		out.MaxY = math.Max(out.MaxY, b.MaxY)
+
+		if b.Width() >= 360 {
+			intervals = append(intervals, [2]float64{-180, 180})
+			continue
+		}
+
+		lo := b.MinX + wrapShift(b.MinX)
+		if lo == 180 {
+			lo = -180
+		}
+
+		hi := lo + b.Width()
+		if hi > 180 {
+			intervals = append(intervals, [2]float64{lo, 180}, [2]float64{-180, hi - 360})
+		} else {
+			intervals = append(intervals, [2]float64{lo, hi})
+		}
+	}
+
+	if len(intervals) == 0 {
+		return EmptyBBox()
+	}
+
+	sort.Slice(intervals, func(i, j int) bool { return intervals[i][0] < intervals[j][0] })
+
+	merged := [][2]float64{intervals[0]}
+	for _, iv := range intervals[1:] {
+		last := &merged[len(merged)-1]
+		if iv[0] <= last[1] {
+			last[1] = math.Max(last[1], iv[1])
+		} else {
+			merged = append(merged, iv)
+		}
+	}
+
+	// the bounding box is the complement of the largest gap between the covered longitudes, which is the gap
+	// across the antimeridian unless another one is larger
+	first, last := merged[0], merged[len(merged)-1]
+	out.MinX, out.MaxX = first[0], last[1]
+	gap := first[0] + 360 - last[1]
+
+	for x := 1; x < len(merged); x++ {
+		if g := merged[x][0] - merged[x-1][1]; g > gap {
+			gap = g
+			out.MinX, out.MaxX = merged[x][0], merged[x-1][1]
+		}
+	}
+
+	return out
+}
+
+func (ls LineString) unwrappedBounds() []BBox {
+	if len(ls) == 0 {
+		return nil
+	}
+
+	return []BBox{unwrapLongitudes(ls).Bounds()}
+}
+
+func (p Polygon) unwrappedBounds() []BBox {
+	if len(p) == 0 {
+		return nil
+	}
+
+	u := unwrapPolygon(p)
+	if len(u) == 0 {
+		// degenerate rings are bounded by their points
+		return LineString(p[0]).unwrappedBounds()
+	}
+
+	return []BBox{u[0].Bounds()}
+}
+
+// unwrappedBounds returns the bounding boxes of the parts of the feature, with unwrapped longitudes
+func (f *Feature) unwrappedBounds() []BBox {
+	parts := make([]BBox, 0)
+
+	switch f.Type {
+	case "Point":
+		parts = append(parts, f.Coordinates.(Point).Bounds())
+	case "MultiPoint":
+		for _, p := range f.Coordinates.(MultiPoint) {
+			parts = append(parts, p.Bounds())
+		}
+	case "LineString":
+		parts = LineString(f.Coordinates.(MultiPoint)).unwrappedBounds()
+	case "MultiLineString":
+		for _, l := range f.Coordinates.(Polygon) {
+			parts = append(parts, LineString(l).unwrappedBounds()...)
+		}
+	case "Polygon":
+		parts = f.Coordinates.(Polygon).unwrappedBounds()
+	case "MultiPolygon":
+		for _, p := range f.Coordinates.(MultiPolygon) {
+			parts = append(parts, p.unwrappedBounds()...)
+		}
+	}
+
+	return parts
+}
+
+// GeographicBounds returns the bounding box of a longitude/latitude line, which crosses the antimeridian (with
+// MinX greater than MaxX, as in RFC 7946) when the line does
+func (ls LineString) GeographicBounds() BBox {
+	return geographicBounds(ls.unwrappedBounds())
+}
+
+// GeographicBounds returns the bounding box of longitude/latitude points, taking the smallest extent around the
+// globe. When it crosses the antimeridian MinX is greater than MaxX, as in RFC 7946.
+func (mp MultiPoint) GeographicBounds() BBox {
+	parts := make([]BBox, 0, len(mp))
+	for _, p := range mp {
+		parts = append(parts, p.Bounds())
+	}
+
+	return geographicBounds(parts)
+}
+
+// GeographicBounds returns the bounding box of a longitude/latitude polygon, which crosses the antimeridian (with
+// MinX greater than MaxX, as in RFC 7946) when the polygon does. Polygons encircling a pole extend to it.
+func (p Polygon) GeographicBounds() BBox {
+	return geographicBounds(p.unwrappedBounds())
+}
+
+// GeographicBounds returns the bounding box of longitude/latitude polygons, taking the smallest extent around the
+// globe. When it crosses the antimeridian MinX is greater than MaxX, as in RFC 7946.
+func (mp MultiPolygon) GeographicBounds() BBox {
+	parts := make([]BBox, 0, len(mp))
+	for _, p := range mp {
+		parts = append(parts, p.unwrappedBounds()...)
+	}
+
+	return geographicBounds(parts)
+}
+
+// GeographicBounds returns the bounding box of a longitude/latitude feature, taking the smallest extent around the
+// globe. When it crosses the antimeridian MinX is greater than MaxX, as in RFC 7946.
+func (f *Feature) GeographicBounds() BBox {
+	return geographicBounds(f.unwrappedBounds())
+}
+
+// GeographicBounds returns the bounding box of every feature in a longitude/latitude collection, taking the
+// smallest extent around the globe. When it crosses the antimeridian MinX is greater than MaxX, as in RFC 7946.
+func (fc *FeatureCollection) GeographicBounds() BBox {
+	parts := make([]BBox, 0, len(fc.Features))
+	for x := range fc.Features {
+		parts = append(parts, fc.Features[x].unwrappedBounds()...)
+	}
+
+	return geographicBounds(parts)
+}
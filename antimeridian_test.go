@@ -0,0 +1,103 @@
+package gegography
+
+import "testing"
+
+func TestNormalizeLongitude(t *testing.T) {
+	tests := []struct {
+		in, want float64
+	}{
+		{0, 0}, {180, 180}, {-180, -180}, {190, -170}, {-190, 170}, {360, 0}, {725, 5},
+	}
+
+	for _, test := range tests {
+		if got := NormalizeLongitude(test.in); got != test.want {
+			t.Errorf("NormalizeLongitude(%v), want %v got %v", test.in, test.want, got)
+		}
+	}
+}
+
+func TestSplitAntimeridian(t *testing.T) {
+	// Fiji given with longitudes beyond 180
+	f, err := ParseWKT("POLYGON ((177 -16, 177 -19, 182 -19, 182 -16, 177 -16))")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = f.SplitAntimeridian(); err != nil {
+		t.Fatal(err)
+	}
+
+	mp, ok := f.Coordinates.(MultiPolygon)
+	if f.Type != "MultiPolygon" || !ok || len(mp) != 2 {
+		t.Fatalf("Feature.SplitAntimeridian, want two polygons got %v", f.Coordinates)
+	}
+
+	if b := mp.Bounds(); b != (BBox{MinX: -180, MinY: -19, MaxX: 180, MaxY: -16}) {
+		t.Errorf("Feature.SplitAntimeridian, want parts touching the antimeridian got bounds %v", b)
+	}
+
+	if area := mp.Area(); area != 15 {
+		t.Errorf("Feature.SplitAntimeridian, want the parts to cover 15 square degrees got %v", area)
+	}
+
+	lines := LineString{{X: 170, Y: 10}, {X: -170, Y: 20}, {X: 170, Y: 30}}.SplitAntimeridian()
+	if len(lines) != 3 || lines[1][0] != (Point{X: -180, Y: 15}) || lines[2][0] != (Point{X: 180, Y: 25}) {
+		t.Errorf("LineString.SplitAntimeridian, got %v", lines)
+	}
+
+	// a polygon which does not cross the antimeridian is only normalised
+	p := Polygon{MultiPoint{{X: 190, Y: 0}, {X: 200, Y: 0}, {X: 200, Y: 10}, {X: 190, Y: 0}}}.SplitAntimeridian()
+	if len(p) != 1 || p.Bounds() != (BBox{MinX: -170, MinY: 0, MaxX: -160, MaxY: 10}) {
+		t.Errorf("Polygon.SplitAntimeridian, got %v", p)
+	}
+}
+
+func TestUnwrapPolygonDegenerateShell(t *testing.T) {
+	// the hole must not become the shell when the shell collapses to a line
+	p := Polygon{
+		MultiPoint{{X: 170, Y: 0}, {X: 190, Y: 0}, {X: 170, Y: 0}},
+		MultiPoint{{X: 175, Y: 2}, {X: 185, Y: 2}, {X: 185, Y: 8}, {X: 175, Y: 8}, {X: 175, Y: 2}},
+	}
+
+	if u := unwrapPolygon(p); len(u) != 0 {
+		t.Errorf("unwrapPolygon(%v), want an empty polygon got %v", p, u)
+	}
+
+	if mp := p.SplitAntimeridian(); len(mp) != 0 {
+		t.Errorf("Polygon.SplitAntimeridian, want no parts for a degenerate shell got %v", mp)
+	}
+
+	// a degenerate hole is dropped
+	p = Polygon{p[1], MultiPoint{{X: 178, Y: 4}, {X: 182, Y: 4}, {X: 178, Y: 4}}}
+	if u := unwrapPolygon(p); len(u) != 1 || u.Area() != 60 {
+		t.Errorf("unwrapPolygon(%v), want the shell alone got %v", p, u)
+	}
+}
+
+func TestGeographicBounds(t *testing.T) {
+	tests := []struct {
+		wkt  string
+		want BBox
+	}{
+		{"POLYGON ((177 -16, 177 -19, -179 -19, -179 -16, 177 -16))", BBox{MinX: 177, MinY: -19, MaxX: -179, MaxY: -16}},
+		{"POLYGON ((10 10, 20 10, 20 20, 10 10))", BBox{MinX: 10, MinY: 10, MaxX: 20, MaxY: 20}},
+		{"LINESTRING (170 10, -170 20)", BBox{MinX: 170, MinY: 10, MaxX: -170, MaxY: 20}},
+		{"MULTIPOINT (179 1, -179 2, 178 3)", BBox{MinX: 178, MinY: 1, MaxX: -179, MaxY: 3}},
+		{"MULTIPOINT (-100 1, 100 2)", BBox{MinX: 100, MinY: 1, MaxX: -100, MaxY: 2}},
+		{"MULTIPOINT (-80 1, 80 2)", BBox{MinX: -80, MinY: 1, MaxX: 80, MaxY: 2}},
+		// a ring around the north pole covers every longitude up to the pole
+		{"POLYGON ((0 80, 90 80, 180 80, -90 80, 0 80))", BBox{MinX: -180, MinY: 80, MaxX: 180, MaxY: 90}},
+		{"MULTIPOLYGON (((177 -16, 177 -19, 180 -19, 180 -16, 177 -16)), ((-180 -16, -180 -19, -179 -19, -179 -16, -180 -16)))", BBox{MinX: 177, MinY: -19, MaxX: -179, MaxY: -16}},
+	}
+
+	for _, test := range tests {
+		f, err := ParseWKT(test.wkt)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if got := f.GeographicBounds(); got != test.want {
+			t.Errorf("GeographicBounds(%s), want %v got %v", test.wkt, test.want, got)
+		}
+	}
+}
@@ -0,0 +1,206 @@
+package gegography
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// AxisOrder describes the order in which a coordinate reference system lists its horizontal axes
+type AxisOrder int
+
+const (
+	// EastNorth is the order of the X/Y coordinates of Point: longitude before latitude, easting before northing
+	EastNorth AxisOrder = iota
+	// NorthEast lists latitude before longitude and northing before easting, as EPSG does for EPSG:4326
+	NorthEast
+)
+
+func (a AxisOrder) String() string {
+	if a == NorthEast {
+		return "north/east"
+	}
+
+	return "east/north"
+}
+
+// AxisOrder returns the order of the axes of the coordinate reference system, which is east/north when no axes
+// are defined
+func (d CRSDefinition) AxisOrder() AxisOrder {
+	if len(d.Axes) > 0 {
+		switch strings.ToLower(d.Axes[0].Direction) {
+		case "north", "south":
+			return NorthEast
+		}
+	}
+
+	return EastNorth
+}
+
+// AxisOrder returns the axis order in which coordinates named by a GeoJSON crs member are given. URN and URL forms
+// such as "urn:ogc:def:crs:EPSG::4326" follow the axis order of the authority, while the "EPSG:4326" and CRS84
+// forms always mean east/north, as is common practice in WFS and GIS software.
+func (c CRS) AxisOrder() (AxisOrder, error) {
+	d, err := c.Definition()
+	if err != nil {
+		return EastNorth, err
+	}
+
+	n := strings.ToUpper(strings.TrimSpace(c.Properties.Name))
+	if strings.HasPrefix(n, "EPSG:") || strings.HasSuffix(n, "CRS84") {
+		return EastNorth, nil
+	}
+
+	return d.AxisOrder(), nil
+}
+
+// SwapXY returns the point with its X and Y coordinates swapped
+func (p Point) SwapXY() Point {
+	return Point{X: p.Y, Y: p.X}
+}
+
+func swapXY(p Point) (Point, error) {
+	return p.SwapXY(), nil
+}
+
+// SwapXY returns a copy of the points with their X and Y coordinates swapped
+func (mp MultiPoint) SwapXY() MultiPoint {
+	out, _ := mp.transform(swapXY)
+
+	return out
+}
+
+// SwapXY returns a copy of the polygon with the X and Y coordinates of every ring swapped. Swapping the axes
+// mirrors the rings, so their orientation is restored afterwards.
+func (p Polygon) SwapXY() Polygon {
+	out, _ := p.transform(swapXY)
+
+	for x := range out {
+		out[x] = reverseRing(out[x])
+	}
+
+	return out
+}
+
+// SwapXY returns a copy of the polygons with their X and Y coordinates swapped
+func (mp MultiPolygon) SwapXY() MultiPolygon {
+	out := make(MultiPolygon, 0, len(mp))
+
+	for x := range mp {
+		out = append(out, mp[x].SwapXY())
+	}
+
+	return out
+}
+
+// SwapXY swaps the X and Y coordinates of the feature in place
+func (f *Feature) SwapXY() error {
+	switch f.Type {
+	case "Point":
+		f.Coordinates = f.Coordinates.(Point).SwapXY()
+	case "MultiPoint", "LineString":
+		f.Coordinates = f.Coordinates.(MultiPoint).SwapXY()
+	case "Polygon":
+		f.Coordinates = f.Coordinates.(Polygon).SwapXY()
+	case "MultiLineString":
+		// lines have no orientation to restore
+		f.Coordinates, _ = f.Coordinates.(Polygon).transform(swapXY)
+	case "MultiPolygon":
+		f.Coordinates = f.Coordinates.(MultiPolygon).SwapXY()
+	default:
+		return GeoTypeError{Type: f.Type}
+	}
+
+	return nil
+}
+
+// SwapXY swaps the X and Y coordinates of every feature in the collection in place
+func (fc *FeatureCollection) SwapXY() error {
+	for x := range fc.Features {
+		if err := fc.Features[x].SwapXY(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// AxisSwapCheck is the result of FeatureCollection.DetectSwappedAxes
+type AxisSwapCheck struct {
+	// Likely is true when the coordinates are likely given in swapped order
+	Likely bool
+	// Reason explains the decision
+	Reason string
+	// Points is the number of coordinates examined
+	Points int
+	// Inside and InsideSwapped are the number of coordinates which are valid and within the area of use of the
+	// coordinate reference system, as given and when swapped
+	Inside        int
+	InsideSwapped int
+}
+
+// DetectSwappedAxes uses heuristics to detect a collection whose X and Y coordinates are likely swapped relative to
+// a coordinate reference system, such as latitude/longitude data declared as longitude/latitude. The coordinates
+// are checked as given and swapped, for latitudes outside ±90 and longitudes outside ±180 and for positions outside
+// the area of use of the coordinate reference system. Coordinates are only considered swapped when swapping them
+// is clearly better; a collection whose coordinates are valid either way, for example near the equator in
+// EPSG:4326, is not flagged.
+func (fc *FeatureCollection) DetectSwappedAxes(d CRSDefinition) AxisSwapCheck {
+	var check AxisSwapCheck
+	invalid, invalidSwapped := 0, 0
+
+	inside := func(p Point) (bool, bool) {
+		g, err := d.ToGeographic(p)
+		if err != nil || !g.isFinite() || math.Abs(g.Y) > 90 || math.Abs(g.X-d.PrimeMeridian) > 180 {
+			return false, false
+		}
+
+		return true, d.AreaOfUse == nil || d.AreaOfUse.Contains(g)
+	}
+
+	for x := range fc.Features {
+		fc.Features[x].eachPoint(func(p Point) {
+			check.Points++
+
+			valid, in := inside(p)
+			if !valid {
+				invalid++
+			}
+			if in {
+				check.Inside++
+			}
+
+			valid, in = inside(p.SwapXY())
+			if !valid {
+				invalidSwapped++
+			}
+			if in {
+				check.InsideSwapped++
+			}
+		})
+	}
+
+	switch {
+	case check.Points == 0:
+		check.Reason = "the collection has no coordinates"
+	case invalid > 0 && invalidSwapped == 0:
+		check.Likely = true
+		check.Reason = fmt.Sprintf("%d of %d coordinates are out of range, but none are when swapped", invalid, check.Points)
+	case check.Inside*2 < check.Points && check.InsideSwapped*2 > check.Points && check.InsideSwapped > check.Inside:
+		check.Likely = true
+		check.Reason = fmt.Sprintf("%d of %d coordinates are within the area of use of %s, but %d are when swapped",
+			check.Inside, check.Points, d.Name, check.InsideSwapped)
+	default:
+		check.Reason = "the coordinates are not better explained by swapping them"
+	}
+
+	return check
+}
+
+// eachPoint calls fn for every coordinate of the feature
+func (f *Feature) eachPoint(fn func(Point)) {
+	_, _ = f.transformed(func(p Point) (Point, error) {
+		fn(p)
+		return p, nil
+	})
+}
@@ -0,0 +1,100 @@
+package gegography
+
+import "testing"
+
+func TestSwapXY(t *testing.T) {
+	p := Polygon{
+		MultiPoint{{X: 0, Y: 0}, {X: 4, Y: 0}, {X: 4, Y: 2}, {X: 0, Y: 2}, {X: 0, Y: 0}},
+		MultiPoint{{X: 1, Y: 1}, {X: 1, Y: 1.5}, {X: 2, Y: 1.5}, {X: 2, Y: 1}, {X: 1, Y: 1}},
+	}
+
+	s := p.SwapXY()
+	if !isCCW(s[0]) || isCCW(s[1]) {
+		t.Errorf("Polygon.SwapXY, ring orientation was not preserved: %v", s)
+	}
+
+	if b := s.Bounds(); b != (BBox{MinX: 0, MinY: 0, MaxX: 2, MaxY: 4}) {
+		t.Errorf("Polygon.SwapXY, want bounds 0 0 2 4 got %v", b)
+	}
+
+	f := Feature{Type: "LineString", Coordinates: MultiPoint{{X: 1, Y: 2}, {X: 3, Y: 4}}}
+	if err := f.SwapXY(); err != nil {
+		t.Fatal(err)
+	}
+
+	if ls := f.Coordinates.(MultiPoint); ls[0] != (Point{X: 2, Y: 1}) || ls[1] != (Point{X: 4, Y: 3}) {
+		t.Errorf("Feature.SwapXY, got %v", ls)
+	}
+
+	f = Feature{Type: "GeometryCollection"}
+	if err := f.SwapXY(); err == nil {
+		t.Errorf("Feature.SwapXY, expected an error for an unsupported type")
+	}
+}
+
+func TestAxisOrder(t *testing.T) {
+	tests := []struct {
+		name string
+		want AxisOrder
+	}{
+		{"urn:ogc:def:crs:EPSG::4326", NorthEast},
+		{"http://www.opengis.net/def/crs/EPSG/0/4326", NorthEast},
+		{"EPSG:4326", EastNorth},
+		{"urn:ogc:def:crs:OGC:1.3:CRS84", EastNorth},
+		{"urn:ogc:def:crs:EPSG::3006", NorthEast},
+		{"urn:ogc:def:crs:EPSG::3857", EastNorth},
+		{"urn:ogc:def:crs:EPSG::32633", EastNorth},
+	}
+
+	for _, test := range tests {
+		crs := CRS{Type: "name", Properties: CRSProperties{Name: test.name}}
+
+		got, err := crs.AxisOrder()
+		if err != nil || got != test.want {
+			t.Errorf("AxisOrder(%s), want %v got %v (%v)", test.name, test.want, got, err)
+		}
+	}
+
+	d, err := ParseCRSWKT(`GEOGCS["WGS 84",DATUM["WGS_1984",SPHEROID["WGS 84",6378137,298.257223563]],PRIMEM["Greenwich",0],UNIT["degree",0.0174532925199433],AXIS["Latitude",NORTH],AXIS["Longitude",EAST]]`)
+	if err != nil || d.AxisOrder() != NorthEast {
+		t.Errorf("ParseCRSWKT, want north/east axes got %v (%v)", d.Axes, err)
+	}
+}
+
+func TestDetectSwappedAxes(t *testing.T) {
+	points := func(pts ...Point) *FeatureCollection {
+		fc := &FeatureCollection{}
+		for _, p := range pts {
+			fc.Features = append(fc.Features, Feature{Type: "Point", Coordinates: p})
+		}
+
+		return fc
+	}
+
+	wgs84, _ := LookupEPSG(4326)
+	sweref99, _ := LookupEPSG(4619)
+	sweref99TM, _ := LookupEPSG(3006)
+
+	tests := []struct {
+		name string
+		fc   *FeatureCollection
+		crs  CRSDefinition
+		want bool
+	}{
+		{"lat/lon in North America", points(Point{X: 45.5, Y: -122.6}, Point{X: 47.6, Y: -122.3}), wgs84, true},
+		{"lon/lat in North America", points(Point{X: -122.6, Y: 45.5}, Point{X: -122.3, Y: 47.6}), wgs84, false},
+		{"lat/lon in Sweden, world wide CRS", points(Point{X: 59.3, Y: 18.1}, Point{X: 57.7, Y: 12}), wgs84, false},
+		{"lat/lon in Sweden", points(Point{X: 59.3, Y: 18.1}, Point{X: 57.7, Y: 12}), sweref99, true},
+		{"lon/lat in Sweden", points(Point{X: 18.1, Y: 59.3}, Point{X: 12, Y: 57.7}), sweref99, false},
+		{"northing/easting in Sweden", points(Point{X: 6580822, Y: 674032}, Point{X: 6397000, Y: 319000}), sweref99TM, true},
+		{"easting/northing in Sweden", points(Point{X: 674032, Y: 6580822}, Point{X: 319000, Y: 6397000}), sweref99TM, false},
+		{"empty", points(), wgs84, false},
+	}
+
+	for _, test := range tests {
+		check := test.fc.DetectSwappedAxes(test.crs)
+		if check.Likely != test.want {
+			t.Errorf("%s: DetectSwappedAxes, want %v got %+v", test.name, test.want, check)
+		}
+	}
+}
@@ -0,0 +1,223 @@
+package gegography
+
+import "math"
+
+// BBox describes an axis-aligned bounding box. An empty bounding box has its minimum values set to +Inf and its
+// maximum values set to -Inf, so that extending it with any point or box yields that point or box.
+type BBox struct {
+	MinX float64
+	MinY float64
+	MaxX float64
+	MaxY float64
+}
+
+// EmptyBBox returns a bounding box containing nothing
+func EmptyBBox() BBox {
+	return BBox{MinX: math.Inf(1), MinY: math.Inf(1), MaxX: math.Inf(-1), MaxY: math.Inf(-1)}
+}
+
+// IsEmpty reports whether the bounding box contains nothing
+func (b BBox) IsEmpty() bool {
+	return b.MinX > b.MaxX || b.MinY > b.MaxY
+}
+
+// Width returns the extent of the bounding box along the x-axis
+func (b BBox) Width() float64 {
+	if b.IsEmpty() {
+		return 0
+	}
+
+	return b.MaxX - b.MinX
+}
+
+// Height returns the extent of the bounding box along the y-axis
+func (b BBox) Height() float64 {
+	if b.IsEmpty() {
+		return 0
+	}
+
+	return b.MaxY - b.MinY
+}
+
+// Center returns the center point of the bounding box
+func (b BBox) Center() Point {
+	return Point{X: (b.MinX + b.MaxX) / 2, Y: (b.MinY + b.MaxY) / 2}
+}
+
+// ExtendPoint returns the bounding box grown to include a point
+func (b BBox) ExtendPoint(p Point) BBox {
+	return BBox{
+		MinX: math.Min(b.MinX, p.X),
+		MinY: math.Min(b.MinY, p.Y),
+		MaxX: math.Max(b.MaxX, p.X),
+		MaxY: math.Max(b.MaxY, p.Y),
+	}
+}
+
+// Extend returns the smallest bounding box containing both bounding boxes
+func (b BBox) Extend(o BBox) BBox {
+	if o.IsEmpty() {
+		return b
+	}
+
+	if b.IsEmpty() {
+		return o
+	}
+
+	return BBox{
+		MinX: math.Min(b.MinX, o.MinX),
+		MinY: math.Min(b.MinY, o.MinY),
+		MaxX: math.Max(b.MaxX, o.MaxX),
+		MaxY: math.Max(b.MaxY, o.MaxY),
+	}
+}
+
+// Intersection returns the bounding box covered by both bounding boxes, which is empty if they do not intersect
+func (b BBox) Intersection(o BBox) BBox {
+	i := BBox{
+		MinX: math.Max(b.MinX, o.MinX),
+		MinY: math.Max(b.MinY, o.MinY),
+		MaxX: math.Min(b.MaxX, o.MaxX),
+		MaxY: math.Min(b.MaxY, o.MaxY),
+	}
+
+	if i.IsEmpty() {
+		return EmptyBBox()
+	}
+
+	return i
+}
+
+// Intersects reports whether the bounding boxes share at least one point
+func (b BBox) Intersects(o BBox) bool {
+	return !b.IsEmpty() && !o.IsEmpty() &&
+		b.MinX <= o.MaxX && o.MinX <= b.MaxX &&
+		b.MinY <= o.MaxY && o.MinY <= b.MaxY
+}
+
+// Contains reports whether a point lies inside or on the edge of the bounding box
+func (b BBox) Contains(p Point) bool {
+	return p.X >= b.MinX && p.X <= b.MaxX && p.Y >= b.MinY && p.Y <= b.MaxY
+}
+
+// ContainsBBox reports whether another bounding box lies entirely inside the bounding box
+func (b BBox) ContainsBBox(o BBox) bool {
+	return !o.IsEmpty() && o.MinX >= b.MinX && o.MaxX <= b.MaxX && o.MinY >= b.MinY && o.MaxY <= b.MaxY
+}
+
+// Distance returns the distance from a point to the nearest point of the bounding box, which is 0 for points inside
+// it and +Inf for an empty box
+func (b BBox) Distance(p Point) float64 {
+	if b.IsEmpty() {
+		return math.Inf(1)
+	}
+
+	dx := math.Max(0, math.Max(b.MinX-p.X, p.X-b.MaxX))
+	dy := math.Max(0, math.Max(b.MinY-p.Y, p.Y-b.MaxY))
+
+	return math.Hypot(dx, dy)
+}
+
+// Buffer returns the bounding box grown by d in every direction, or shrunk if d is negative
+func (b BBox) Buffer(d float64) BBox {
+	if b.IsEmpty() {
+		return b
+	}
+
+	out := BBox{MinX: b.MinX - d, MinY: b.MinY - d, MaxX: b.MaxX + d, MaxY: b.MaxY + d}
+	if out.IsEmpty() {
+		return EmptyBBox()
+	}
+
+	return out
+}
+
+// ToPolygon returns the bounding box as a counter-clockwise polygon
+func (b BBox) ToPolygon() Polygon {
+	return Polygon{MultiPoint{
+		{X: b.MinX, Y: b.MinY},
+		{X: b.MaxX, Y: b.MinY},
+		{X: b.MaxX, Y: b.MaxY},
+		{X: b.MinX, Y: b.MaxY},
+		{X: b.MinX, Y: b.MinY},
+	}}
+}
+
+// toGeoJSON returns the bounding box as a GeoJSON bbox member, or nil if it is empty
+func (b BBox) toGeoJSON() []float64 {
+	if b.IsEmpty() {
+		return nil
+	}
+
+	return []float64{b.MinX, b.MinY, b.MaxX, b.MaxY}
+}
+
+// Bounds returns the bounding box of the point
+func (p Point) Bounds() BBox {
+	return BBox{MinX: p.X, MinY: p.Y, MaxX: p.X, MaxY: p.Y}
+}
+
+// Bounds returns the bounding box of all points
+func (mp MultiPoint) Bounds() BBox {
+	b := EmptyBBox()
+
+	for x := range mp {
+		b = b.ExtendPoint(mp[x])
+	}
+
+	return b
+}
+
+// Bounds returns the bounding box of the line
+func (ls LineString) Bounds() BBox {
+	return MultiPoint(ls).Bounds()
+}
+
+// Bounds returns the bounding box of the polygon
+func (p Polygon) Bounds() BBox {
+	b := EmptyBBox()
+
+	for x := range p {
+		b = b.Extend(p[x].Bounds())
+	}
+
+	return b
+}
+
+// Bounds returns the bounding box of all polygons
+func (mp MultiPolygon) Bounds() BBox {
+	b := EmptyBBox()
+
+	for x := range mp {
+		b = b.Extend(mp[x].Bounds())
+	}
+
+	return b
+}
+
+// Bounds returns the bounding box of the feature geometry, which is empty for unsupported geometry types
+func (f *Feature) Bounds() BBox {
+	switch f.Type {
+	case "Point":
+		return f.Coordinates.(Point).Bounds()
+	case "MultiPoint", "LineString":
+		return f.Coordinates.(MultiPoint).Bounds()
+	case "Polygon", "MultiLineString":
+		return f.Coordinates.(Polygon).Bounds()
+	case "MultiPolygon":
+		return f.Coordinates.(MultiPolygon).Bounds()
+	}
+
+	return EmptyBBox()
+}
+
+// Bounds returns the bounding box of all features in the collection
+func (fc *FeatureCollection) Bounds() BBox {
+	b := EmptyBBox()
+
+	for x := range fc.Features {
+		b = b.Extend(fc.Features[x].Bounds())
+	}
+
+	return b
+}
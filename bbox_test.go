@@ -0,0 +1,256 @@
+package gegography
+
+import (
+	"math"
+	"testing"
+)
+
+func TestEmptyBBox(t *testing.T) {
+	e := EmptyBBox()
+	if !e.IsEmpty() {
+		t.Errorf("EmptyBBox().IsEmpty(), want true got false")
+	}
+
+	if e.Width() != 0 || e.Height() != 0 {
+		t.Errorf("EmptyBBox() size, want 0x0 got %vx%v", e.Width(), e.Height())
+	}
+
+	if e.Contains(Point{}) {
+		t.Errorf("EmptyBBox().Contains(Point{}), want false got true")
+	}
+
+	if p := (Point{X: 1, Y: 2}).Bounds(); p.IsEmpty() {
+		t.Errorf("Point.Bounds().IsEmpty(), want false for a single point got true")
+	}
+
+	if b := (BBox{MinX: 1, MinY: 0, MaxX: 0, MaxY: 1}); !b.IsEmpty() {
+		t.Errorf("IsEmpty() of an inverted box, want true got false")
+	}
+}
+
+func TestBBoxExtend(t *testing.T) {
+	b := BBox{MinX: 0, MinY: 1, MaxX: 2, MaxY: 3}
+
+	if got := EmptyBBox().Extend(b); got != b {
+		t.Errorf("EmptyBBox().Extend(%v), want %v got %v", b, b, got)
+	}
+
+	if got := b.Extend(EmptyBBox()); got != b {
+		t.Errorf("Extend(EmptyBBox()), want %v got %v", b, got)
+	}
+
+	if got := EmptyBBox().Extend(EmptyBBox()); !got.IsEmpty() {
+		t.Errorf("EmptyBBox().Extend(EmptyBBox()), want an empty box got %v", got)
+	}
+
+	p := Point{X: 5, Y: -1}
+	if got, want := EmptyBBox().ExtendPoint(p), (BBox{MinX: 5, MinY: -1, MaxX: 5, MaxY: -1}); got != want {
+		t.Errorf("EmptyBBox().ExtendPoint(%v), want %v got %v", p, want, got)
+	}
+
+	if got, want := b.ExtendPoint(p), (BBox{MinX: 0, MinY: -1, MaxX: 5, MaxY: 3}); got != want {
+		t.Errorf("ExtendPoint(%v), want %v got %v", p, want, got)
+	}
+
+	if got, want := b.Extend(BBox{MinX: -1, MinY: 2, MaxX: 1, MaxY: 4}), (BBox{MinX: -1, MinY: 1, MaxX: 2, MaxY: 4}); got != want {
+		t.Errorf("Extend(), want %v got %v", want, got)
+	}
+}
+
+func TestBBoxIntersection(t *testing.T) {
+	a := BBox{MinX: 0, MinY: 0, MaxX: 10, MaxY: 10}
+
+	if got, want := a.Intersection(BBox{MinX: 5, MinY: -5, MaxX: 15, MaxY: 5}), (BBox{MinX: 5, MinY: 0, MaxX: 10, MaxY: 5}); got != want {
+		t.Errorf("Intersection() of overlapping boxes, want %v got %v", want, got)
+	}
+
+	if got := a.Intersection(BBox{MinX: 20, MinY: 0, MaxX: 30, MaxY: 10}); got != EmptyBBox() {
+		t.Errorf("Intersection() of disjoint boxes, want EmptyBBox() got %v", got)
+	}
+
+	if got := a.Intersection(EmptyBBox()); !got.IsEmpty() {
+		t.Errorf("Intersection(EmptyBBox()), want an empty box got %v", got)
+	}
+
+	// boxes sharing an edge intersect in a box without area
+	if got, want := a.Intersection(BBox{MinX: 10, MinY: 2, MaxX: 20, MaxY: 8}), (BBox{MinX: 10, MinY: 2, MaxX: 10, MaxY: 8}); got != want {
+		t.Errorf("Intersection() of boxes sharing an edge, want %v got %v", want, got)
+	}
+}
+
+func TestBBoxPredicates(t *testing.T) {
+	b := BBox{MinX: 0, MinY: 0, MaxX: 10, MaxY: 10}
+
+	tests := []struct {
+		o          BBox
+		intersects bool
+		contains   bool
+	}{
+		{BBox{MinX: 2, MinY: 2, MaxX: 8, MaxY: 8}, true, true},
+		{b, true, true},
+		{BBox{MinX: 0, MinY: 0, MaxX: 10, MaxY: 5}, true, true},
+		{BBox{MinX: 10, MinY: 0, MaxX: 20, MaxY: 10}, true, false},
+		{BBox{MinX: 10, MinY: 10, MaxX: 20, MaxY: 20}, true, false},
+		{BBox{MinX: 5, MinY: 5, MaxX: 15, MaxY: 15}, true, false},
+		{BBox{MinX: 10.5, MinY: 0, MaxX: 20, MaxY: 10}, false, false},
+		{EmptyBBox(), false, false},
+	}
+
+	for _, tt := range tests {
+		if got := b.Intersects(tt.o); got != tt.intersects {
+			t.Errorf("Intersects(%v), want %v got %v", tt.o, tt.intersects, got)
+		}
+
+		if got := tt.o.Intersects(b); got != tt.intersects {
+			t.Errorf("%v.Intersects(%v), want %v got %v", tt.o, b, tt.intersects, got)
+		}
+
+		if got := b.ContainsBBox(tt.o); got != tt.contains {
+			t.Errorf("ContainsBBox(%v), want %v got %v", tt.o, tt.contains, got)
+		}
+	}
+
+	points := []struct {
+		p    Point
+		want bool
+	}{
+		{Point{X: 5, Y: 5}, true},
+		{Point{X: 0, Y: 5}, true},
+		{Point{X: 10, Y: 10}, true},
+		{Point{X: 10.000001, Y: 5}, false},
+		{Point{X: 5, Y: -1}, false},
+	}
+
+	for _, tt := range points {
+		if got := b.Contains(tt.p); got != tt.want {
+			t.Errorf("Contains(%v), want %v got %v", tt.p, tt.want, got)
+		}
+	}
+}
+
+func TestBBoxDistance(t *testing.T) {
+	b := BBox{MinX: 0, MinY: 0, MaxX: 10, MaxY: 5}
+
+	tests := []struct {
+		p    Point
+		want float64
+	}{
+		{Point{X: 5, Y: 2}, 0},
+		{Point{X: 10, Y: 2}, 0},
+		{Point{X: -3, Y: 2}, 3},
+		{Point{X: 5, Y: 9}, 4},
+		{Point{X: 13, Y: 9}, 5},
+	}
+
+	for _, tt := range tests {
+		if got := b.Distance(tt.p); math.Abs(got-tt.want) > 1e-12 {
+			t.Errorf("Distance(%v), want %v got %v", tt.p, tt.want, got)
+		}
+	}
+
+	if d := EmptyBBox().Distance(Point{}); !math.IsInf(d, 1) {
+		t.Errorf("EmptyBBox().Distance(), want +Inf got %v", d)
+	}
+}
+
+func TestBBoxBuffer(t *testing.T) {
+	b := BBox{MinX: 0, MinY: 0, MaxX: 10, MaxY: 4}
+
+	if got, want := b.Buffer(1), (BBox{MinX: -1, MinY: -1, MaxX: 11, MaxY: 5}); got != want {
+		t.Errorf("Buffer(1), want %v got %v", want, got)
+	}
+
+	if got, want := b.Buffer(-1), (BBox{MinX: 1, MinY: 1, MaxX: 9, MaxY: 3}); got != want {
+		t.Errorf("Buffer(-1), want %v got %v", want, got)
+	}
+
+	// shrinking by more than half the height empties the box, even though it is still wide enough
+	if got := b.Buffer(-3); got != EmptyBBox() {
+		t.Errorf("Buffer(-3), want EmptyBBox() got %v", got)
+	}
+
+	if got := b.Buffer(-2); got.IsEmpty() || got.Height() != 0 || got.Width() != 6 {
+		t.Errorf("Buffer(-2), want a 6x0 box got %v", got)
+	}
+
+	if got := EmptyBBox().Buffer(5); !got.IsEmpty() {
+		t.Errorf("EmptyBBox().Buffer(5), want an empty box got %v", got)
+	}
+}
+
+func TestBBoxToPolygon(t *testing.T) {
+	b := BBox{MinX: 1, MinY: 2, MaxX: 4, MaxY: 6}
+	p := b.ToPolygon()
+
+	if len(p) != 1 || len(p[0]) != 5 || !p[0][0].equals(p[0][4]) {
+		t.Fatalf("ToPolygon(), want one closed ring of 5 points got %v", p)
+	}
+
+	if !isCCW(p[0]) {
+		t.Errorf("ToPolygon(), want a counter-clockwise ring got %v", p[0])
+	}
+
+	if a := p.Area(); a != 12 {
+		t.Errorf("ToPolygon().Area(), want 12 got %v", a)
+	}
+
+	if got := p.Bounds(); got != b {
+		t.Errorf("ToPolygon().Bounds(), want %v got %v", b, got)
+	}
+
+	if err := p.Validate(); err != nil {
+		t.Errorf("ToPolygon().Validate(), want nil got %v", err)
+	}
+}
+
+func TestBounds(t *testing.T) {
+	tests := []struct {
+		wkt  string
+		want BBox
+	}{
+		{"POINT (1 2)", BBox{MinX: 1, MinY: 2, MaxX: 1, MaxY: 2}},
+		{"MULTIPOINT ((1 2), (-3 5), (0 0))", BBox{MinX: -3, MinY: 0, MaxX: 1, MaxY: 5}},
+		{"LINESTRING (0 0, 4 -2, 3 7)", BBox{MinX: 0, MinY: -2, MaxX: 4, MaxY: 7}},
+		{"MULTILINESTRING ((0 0, 1 1), (5 -5, 6 -4))", BBox{MinX: 0, MinY: -5, MaxX: 6, MaxY: 1}},
+		{"POLYGON ((0 0, 10 0, 10 10, 0 10, 0 0), (2 2, 4 2, 4 4, 2 2))", BBox{MinX: 0, MinY: 0, MaxX: 10, MaxY: 10}},
+		{"MULTIPOLYGON (((0 0, 1 0, 1 1, 0 0)), ((20 -5, 21 -5, 21 -4, 20 -5)))", BBox{MinX: 0, MinY: -5, MaxX: 21, MaxY: 1}},
+	}
+
+	fc := NewFeatureCollection()
+	want := EmptyBBox()
+
+	for _, tt := range tests {
+		f := mustParseWKT(t, tt.wkt)
+		if got := f.Bounds(); got != tt.want {
+			t.Errorf("Feature.Bounds() of %s, want %v got %v", tt.wkt, tt.want, got)
+		}
+
+		fc.AddFeature(f)
+		want = want.Extend(tt.want)
+	}
+
+	if got := fc.Bounds(); got != want {
+		t.Errorf("FeatureCollection.Bounds(), want %v got %v", want, got)
+	}
+
+	if got := (LineString{{X: 1, Y: 1}, {X: -1, Y: 3}}).Bounds(); got != (BBox{MinX: -1, MinY: 1, MaxX: 1, MaxY: 3}) {
+		t.Errorf("LineString.Bounds(), want -1 1 1 3 got %v", got)
+	}
+
+	empty := []Feature{
+		{Type: "MultiPoint", Coordinates: MultiPoint{}},
+		{Type: "Polygon", Coordinates: Polygon{}},
+		{Type: "MultiPolygon", Coordinates: MultiPolygon{}},
+		{Type: "GeometryCollection"},
+	}
+
+	for _, f := range empty {
+		if got := f.Bounds(); !got.IsEmpty() {
+			t.Errorf("Feature.Bounds() of an empty %s, want an empty box got %v", f.Type, got)
+		}
+	}
+
+	if got := (&FeatureCollection{}).Bounds(); !got.IsEmpty() {
+		t.Errorf("FeatureCollection.Bounds() without features, want an empty box got %v", got)
+	}
+}
@@ -0,0 +1,356 @@
+package gegography
+
+import "math"
+
+// BufferJoin is the shape of a buffer around the outside of a corner
+type BufferJoin int
+
+const (
+	// JoinRound rounds corners with a circular arc
+	JoinRound BufferJoin = iota
+	// JoinMitre extends the edges of the buffer until they meet in a sharp corner
+	JoinMitre
+	// JoinBevel cuts corners off with a straight line
+	JoinBevel
+)
+
+// BufferCap is the shape of a buffer around the ends of lines
+type BufferCap int
+
+const (
+	// CapRound ends lines with a half circle
+	CapRound BufferCap = iota
+	// CapFlat ends lines with a straight line through the end point
+	CapFlat
+	// CapSquare ends lines with a half square, extending the line by the buffer distance
+	CapSquare
+)
+
+// BufferOptions configures Buffer. The zero value gives round joins and caps.
+type BufferOptions struct {
+	Join BufferJoin
+	Cap  BufferCap
+	// MitreLimit is the largest distance, as a multiple of the buffer distance, a mitred corner may extend from
+	// its vertex. Sharper corners are bevelled. Defaults to 5.
+	MitreLimit float64
+	// Segments is the number of segments used to approximate a quarter circle. Defaults to 8.
+	Segments int
+	// Geographic buffers Feature coordinates given as WGS84 longitude/latitude by a distance in metres, by
+	// projecting them to a local Transverse Mercator projection centred on the feature
+	Geographic bool
+}
+
+func (o BufferOptions) mitreLimit() float64 {
+	if o.MitreLimit <= 0 {
+		return 5
+	}
+
+	return o.MitreLimit
+}
+
+func (o BufferOptions) segments() int {
+	if o.Segments <= 0 {
+		return 8
+	}
+
+	return o.Segments
+}
+
+// offsetPoint returns p moved by d along the unit vector n
+func offsetPoint(p, n Point, d float64) Point {
+	return Point{X: p.X + d*n.X, Y: p.Y + d*n.Y}
+}
+
+// rotate rotates the vector v counter-clockwise by a radians
+func rotate(v Point, a float64) Point {
+	sin, cos := math.Sincos(a)
+
+	return Point{X: v.X*cos - v.Y*sin, Y: v.X*sin + v.Y*cos}
+}
+
+// bufferArc returns a fan from c along the arc starting at c + d*n and turning by sweep radians
+func bufferArc(c, n Point, d, sweep float64, o BufferOptions) MultiPoint {
+	steps := max(1, int(math.Ceil(math.Abs(sweep)/(math.Pi/2)*float64(o.segments()))))
+	fan := MultiPoint{c, offsetPoint(c, n, d)}
+
+	for x := 1; x < steps; x++ {
+		fan = append(fan, offsetPoint(c, rotate(n, sweep*float64(x)/float64(steps)), d))
+	}
+
+	return append(fan, offsetPoint(c, rotate(n, sweep), d), c)
+}
+
+// bufferCircle returns a circle approximated by a polygon whose vertices lie on the circle
+func bufferCircle(c Point, d float64, o BufferOptions) Polygon {
+	n := 4 * o.segments()
+	ring := make(MultiPoint, 0, n+1)
+
+	for x := range n {
+		ring = append(ring, offsetPoint(c, rotate(Point{X: 1}, 2*math.Pi*float64(x)/float64(n)), d))
+	}
+
+	return Polygon{closeRing(ring)}
+}
+
+// bufferPoint returns the buffer of a single point, which depends on the end cap like that of a zero length line
+func bufferPoint(p Point, d float64, o BufferOptions) MultiPolygon {
+	switch o.Cap {
+	case CapRound:
+		return MultiPolygon{bufferCircle(p, d, o)}
+	case CapSquare:
+		return MultiPolygon{BBox{MinX: p.X - d, MinY: p.Y - d, MaxX: p.X + d, MaxY: p.Y + d}.ToPolygon()}
+	}
+
+	return MultiPolygon{}
+}
+
+// bufferPath returns polygons whose union is the buffer of a line by d: a rectangle around every segment, a join at
+// every corner and a cap at each end. A closed path is buffered as a ring, with a join instead of caps where it
+// closes. Corners are only joined on their outside, as the rectangles overlap on the inside.
+func bufferPath(pts []Point, closed bool, d float64, o BufferOptions) []MultiPolygon {
+	if closed {
+		pts = ringVertices(pts)
+	} else {
+		pts = dedupePoints(pts)
+	}
+
+	switch len(pts) {
+	case 0:
+		return nil
+	case 1:
+		return []MultiPolygon{bufferPoint(pts[0], d, o)}
+	}
+
+	n := len(pts) - 1
+	if closed {
+		n = len(pts)
+	}
+
+	// the direction and left normal of every segment
+	dirs := make([]Point, n)
+	normals := make([]Point, n)
+	pieces := make([]MultiPolygon, 0, 2*n+2)
+
+	for x := range n {
+		a, b := pts[x], pts[(x+1)%len(pts)]
+		l := distance(a, b)
+		dirs[x] = Point{X: (b.X - a.X) / l, Y: (b.Y - a.Y) / l}
+		normals[x] = Point{X: -dirs[x].Y, Y: dirs[x].X}
+
+		pieces = append(pieces, MultiPolygon{Polygon{{
+			offsetPoint(a, normals[x], -d), offsetPoint(b, normals[x], -d),
+			offsetPoint(b, normals[x], d), offsetPoint(a, normals[x], d),
+			offsetPoint(a, normals[x], -d),
+		}}})
+	}
+
+	for x := range n {
+		if !closed && x == 0 {
+			continue
+		}
+
+		prev := (x + n - 1) % n
+		v := pts[x]
+
+		turn := math.Atan2(dirs[prev].X*dirs[x].Y-dirs[prev].Y*dirs[x].X, dirs[prev].X*dirs[x].X+dirs[prev].Y*dirs[x].Y)
+		if math.Abs(turn) < 1e-12 {
+			continue
+		}
+
+		// the outside of a left turn is on the right of the path
+		s := d
+		if turn > 0 {
+			s = -d
+		}
+
+		p1, p2 := offsetPoint(v, normals[prev], s), offsetPoint(v, normals[x], s)
+
+		switch o.Join {
+		case JoinRound:
+			pieces = append(pieces, MultiPolygon{Polygon{bufferArc(v, normals[prev], s, turn, o)}})
+		case JoinMitre:
+			// the mitre point lies along the bisector of the normals, 1/cos(turn/2) times the distance away
+			k := s / (1 + normals[prev].X*normals[x].X + normals[prev].Y*normals[x].Y)
+			m := Point{X: v.X + k*(normals[prev].X+normals[x].X), Y: v.Y + k*(normals[prev].Y+normals[x].Y)}
+
+			if math.Abs(1/math.Cos(turn/2)) <= o.mitreLimit() {
+				pieces = append(pieces, MultiPolygon{Polygon{{v, p1, m, p2, v}}})
+				continue
+			}
+
+			fallthrough
+		default:
+			pieces = append(pieces, MultiPolygon{Polygon{{v, p1, p2, v}}})
+		}
+	}
+
+	if !closed {
+		last := n - 1
+		pieces = append(pieces, bufferCap(pts[0], Point{X: -dirs[0].X, Y: -dirs[0].Y}, d, o))
+		pieces = append(pieces, bufferCap(pts[len(pts)-1], dirs[last], d, o))
+	}
+
+	return pieces
+}
+
+// dedupePoints returns a copy of the points without consecutive duplicates
+func dedupePoints(pts []Point) MultiPoint {
+	out := make(MultiPoint, 0, len(pts))
+
+	for _, p := range pts {
+		if len(out) == 0 || !out[len(out)-1].equals(p) {
+			out = append(out, p)
+		}
+	}
+
+	return out
+}
+
+// bufferCap returns the cap of the buffer of a line ending at c, with dir pointing away from the line
+func bufferCap(c, dir Point, d float64, o BufferOptions) MultiPolygon {
+	left := Point{X: -dir.Y, Y: dir.X}
+
+	switch o.Cap {
+	case CapRound:
+		return MultiPolygon{Polygon{bufferArc(c, left, d, -math.Pi, o)}}
+	case CapSquare:
+		return MultiPolygon{Polygon{{
+			offsetPoint(c, left, d), offsetPoint(offsetPoint(c, left, d), dir, d),
+			offsetPoint(offsetPoint(c, left, -d), dir, d), offsetPoint(c, left, -d),
+			offsetPoint(c, left, d),
+		}}}
+	}
+
+	return MultiPolygon{}
+}
+
+// bufferRings returns the union of the buffers of the rings of polygons, a band of width 2d along their boundaries
+func bufferRings(mp MultiPolygon, d float64, o BufferOptions) MultiPolygon {
+	pieces := make([]MultiPolygon, 0)
+
+	for _, p := range mp {
+		for _, r := range p {
+			pieces = append(pieces, bufferPath(r, true, d, o)...)
+		}
+	}
+
+	return unionAll(pieces)
+}
+
+// Buffer returns the area within distance of the point. With flat caps, or a distance which is not positive, the
+// result is empty.
+func (p Point) Buffer(distance float64, opts BufferOptions) MultiPolygon {
+	if distance <= 0 {
+		return MultiPolygon{}
+	}
+
+	return bufferPoint(p, distance, opts)
+}
+
+// Buffer returns the area within distance of any of the points
+func (mp MultiPoint) Buffer(distance float64, opts BufferOptions) MultiPolygon {
+	if distance <= 0 {
+		return MultiPolygon{}
+	}
+
+	pieces := make([]MultiPolygon, 0, len(mp))
+	for _, p := range mp {
+		pieces = append(pieces, bufferPoint(p, distance, opts))
+	}
+
+	return unionAll(pieces)
+}
+
+// Buffer returns the area within distance of the line, shaped at its corners and ends according to the options.
+// A line whose first and last points are equal is buffered as a ring, without caps. The result is empty when the
+// distance is not positive.
+func (ls LineString) Buffer(distance float64, opts BufferOptions) MultiPolygon {
+	if distance <= 0 || len(ls) == 0 {
+		return MultiPolygon{}
+	}
+
+	closed := len(ls) > 2 && isClosed(ls)
+
+	return unionAll(bufferPath(ls, closed, distance, opts))
+}
+
+// Buffer returns the polygon grown by distance, or shrunk by it when the distance is negative. Shrinking may split
+// the polygon into several parts, or make it disappear entirely.
+func (p Polygon) Buffer(distance float64, opts BufferOptions) MultiPolygon {
+	return MultiPolygon{p}.Buffer(distance, opts)
+}
+
+// Buffer returns the polygons grown by distance, or shrunk by it when the distance is negative. Grown polygons
+// which come to overlap are merged.
+func (mp MultiPolygon) Buffer(distance float64, opts BufferOptions) MultiPolygon {
+	mp = mp.rewind()
+
+	switch {
+	case distance == 0 || len(mp) == 0:
+		return mp
+	case distance > 0:
+		return unionAll(append([]MultiPolygon{mp}, bufferRings(mp, distance, opts)))
+	}
+
+	out := make(MultiPolygon, 0, len(mp))
+	for _, p := range mp {
+		out = append(out, overlay(MultiPolygon{p}, bufferRings(MultiPolygon{p}, -distance, opts), overlayDifference)...)
+	}
+
+	return out
+}
+
+func (f *Feature) buffer(distance float64, opts BufferOptions) (MultiPolygon, error) {
+	switch f.Type {
+	case "Point":
+		return f.Coordinates.(Point).Buffer(distance, opts), nil
+	case "MultiPoint":
+		return f.Coordinates.(MultiPoint).Buffer(distance, opts), nil
+	case "LineString":
+		return LineString(f.Coordinates.(MultiPoint)).Buffer(distance, opts), nil
+	case "MultiLineString":
+		pieces := make([]MultiPolygon, 0)
+		for _, l := range f.Coordinates.(Polygon) {
+			pieces = append(pieces, LineString(l).Buffer(distance, opts))
+		}
+
+		return unionAll(pieces), nil
+	case "Polygon":
+		return f.Coordinates.(Polygon).Buffer(distance, opts), nil
+	case "MultiPolygon":
+		return f.Coordinates.(MultiPolygon).Buffer(distance, opts), nil
+	}
+
+	return nil, GeoTypeError{Type: f.Type}
+}
+
+// Buffer returns a Polygon or MultiPolygon feature covering the area within distance of the feature, with the
+// properties of f. Negative distances shrink polygons. With opts.Geographic, the coordinates are taken to be WGS84
+// longitude/latitude and the distance to be in metres.
+func (f *Feature) Buffer(distance float64, opts BufferOptions) (Feature, error) {
+	if !opts.Geographic {
+		mp, err := f.buffer(distance, opts)
+		if err != nil {
+			return Feature{}, err
+		}
+
+		return polygonalFeature(mp, f.Properties), nil
+	}
+
+	c := f.Bounds().Center()
+	tm := TransverseMercator{Ellipsoid: WGS84Ellipsoid, LatitudeOfOrigin: c.Y, CentralMeridian: c.X, ScaleFactor: 1}
+
+	projected, err := f.transformed(tm.Forward)
+	if err != nil {
+		return Feature{}, err
+	}
+
+	mp, err := projected.buffer(distance, opts)
+	if err != nil {
+		return Feature{}, err
+	}
+
+	out := polygonalFeature(mp, f.Properties)
+
+	return out.transformed(tm.Inverse)
+}
@@ -0,0 +1,109 @@
+package gegography
+
+import (
+	"math"
+	"testing"
+)
+
+// polygonArea is the area of a regular polygon with n vertices on a circle of radius r
+func polygonArea(n int, r float64) float64 {
+	return float64(n) / 2 * r * r * math.Sin(2*math.Pi/float64(n))
+}
+
+func TestBuffer(t *testing.T) {
+	square := "POLYGON ((0 0, 10 0, 10 10, 0 10, 0 0))"
+	circle := polygonArea(32, 1)
+
+	tests := []struct {
+		name     string
+		wkt      string
+		distance float64
+		opts     BufferOptions
+		area     float64
+		parts    int
+	}{
+		{"point", "POINT (5 5)", 1, BufferOptions{}, circle, 1},
+		{"square point", "POINT (5 5)", 1, BufferOptions{Cap: CapSquare}, 4, 1},
+		{"flat point", "POINT (5 5)", 1, BufferOptions{Cap: CapFlat}, 0, 0},
+		{"overlapping points", "MULTIPOINT ((0 0), (1 0))", 1, BufferOptions{Cap: CapSquare}, 6, 1},
+		{"separate points", "MULTIPOINT ((0 0), (5 0))", 1, BufferOptions{}, 2 * circle, 2},
+		{"flat line", "LINESTRING (0 0, 10 0)", 1, BufferOptions{Cap: CapFlat}, 20, 1},
+		{"square line", "LINESTRING (0 0, 10 0)", 1, BufferOptions{Cap: CapSquare}, 24, 1},
+		{"round line", "LINESTRING (0 0, 10 0)", 1, BufferOptions{}, 20 + circle, 1},
+		{"mitred corner", "LINESTRING (0 0, 10 0, 10 10)", 1, BufferOptions{Cap: CapFlat, Join: JoinMitre}, 40, 1},
+		{"bevelled corner", "LINESTRING (0 0, 10 0, 10 10)", 1, BufferOptions{Cap: CapFlat, Join: JoinBevel}, 39.5, 1},
+		{"round corner", "LINESTRING (0 0, 10 0, 10 10)", 1, BufferOptions{Cap: CapFlat}, 39 + circle/4, 1},
+		{"mitre limit", "LINESTRING (0 0, 10 0, 0 1)", 1, BufferOptions{Cap: CapFlat, Join: JoinMitre, MitreLimit: 2}, 0, 1},
+		{"closed line", "LINESTRING (0 0, 10 0, 10 10, 0 10, 0 0)", 1, BufferOptions{Cap: CapFlat, Join: JoinMitre}, 144 - 64, 1},
+		{"grown polygon", square, 1, BufferOptions{Join: JoinMitre}, 144, 1},
+		{"bevelled polygon", square, 1, BufferOptions{Join: JoinBevel}, 142, 1},
+		{"rounded polygon", square, 1, BufferOptions{}, 140 + circle, 1},
+		{"shrunk polygon", square, -1, BufferOptions{}, 64, 1},
+		{"vanished polygon", square, -6, BufferOptions{}, 0, 0},
+		{"zero distance", square, 0, BufferOptions{}, 100, 1},
+		{"hole filled", "POLYGON ((0 0, 10 0, 10 10, 0 10, 0 0), (4 4, 6 4, 6 6, 4 6, 4 4))", 1.5, BufferOptions{Join: JoinMitre}, 169, 1},
+		{"split by shrinking", "POLYGON ((0 0, 4 0, 4 1.5, 6 1.5, 6 0, 10 0, 10 4, 6 4, 6 2.5, 4 2.5, 4 4, 0 4, 0 0))", -0.6, BufferOptions{Join: JoinMitre}, 2 * 2.8 * 2.8, 2},
+		{"merged polygons", "MULTIPOLYGON (((0 0, 1 0, 1 1, 0 1, 0 0)), ((2 0, 3 0, 3 1, 2 1, 2 0)))", 0.5, BufferOptions{Join: JoinMitre}, 8, 1},
+	}
+
+	for _, tt := range tests {
+		f := mustParseWKT(t, tt.wkt)
+
+		got, err := f.Buffer(tt.distance, tt.opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		mp, _ := got.polygons()
+
+		if tt.area > 0 && math.Abs(got.Area()-tt.area) > 1e-9*tt.area {
+			t.Errorf("Buffer(%v) %s, want area %v got %v", tt.distance, tt.name, tt.area, got.Area())
+		}
+
+		if len(mp) != tt.parts {
+			t.Errorf("Buffer(%v) %s, want %d parts got %d: %v", tt.distance, tt.name, tt.parts, len(mp), mp)
+		}
+
+		if err := mp.Validate(); err != nil {
+			t.Errorf("Buffer(%v) %s, want a valid result got %v", tt.distance, tt.name, err)
+		}
+	}
+
+	line := mustParseWKT(t, "LINESTRING (0 0, 10 0, 0 1)")
+	mitred, _ := line.Buffer(1, BufferOptions{Cap: CapFlat, Join: JoinMitre, MitreLimit: 50})
+	limited, _ := line.Buffer(1, BufferOptions{Cap: CapFlat, Join: JoinMitre, MitreLimit: 2})
+
+	if mitred.Bounds().MaxX < 19 || limited.Bounds().MaxX > 11 {
+		t.Errorf("Buffer(1) with mitre limits 50 and 2, want the limit applied got %v and %v", mitred.Bounds(), limited.Bounds())
+	}
+}
+
+func TestGeographicBuffer(t *testing.T) {
+	f := Feature{Type: "Point", Coordinates: Point{X: 18.07, Y: 59.33}}
+
+	got, err := f.Buffer(50, BufferOptions{Geographic: true, Segments: 32})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := polygonArea(128, 50); math.Abs(got.GeodesicArea()-want) > 1e-3*want {
+		t.Errorf("Buffer(50) geographic of a point, want area %v m² got %v", want, got.GeodesicArea())
+	}
+
+	ring := got.Coordinates.(Polygon)[0]
+	for _, p := range ring[:len(ring)-1] {
+		if d := GeodesicDistance(Point{X: 18.07, Y: 59.33}, p); math.Abs(d-50) > 0.01 {
+			t.Fatalf("Buffer(50) geographic, want vertex %v 50 m from the centre got %v", p, d)
+		}
+	}
+
+	watercourse := Feature{Type: "LineString", Coordinates: MultiPoint{{X: 18.07, Y: 59.33}, {X: 18.08, Y: 59.33}}}
+	if got, err = watercourse.Buffer(50, BufferOptions{Geographic: true, Cap: CapFlat}); err != nil {
+		t.Fatal(err)
+	}
+
+	want := 100 * GeodesicDistance(Point{X: 18.07, Y: 59.33}, Point{X: 18.08, Y: 59.33})
+	if math.Abs(got.GeodesicArea()-want) > 1e-3*want {
+		t.Errorf("Buffer(50) geographic of a line, want area %v m² got %v", want, got.GeodesicArea())
+	}
+}
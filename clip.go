@@ -0,0 +1,504 @@
+package gegography
+
+import (
+	"slices"
+	"sort"
+)
+
+// ClipOptions controls clipping
+type ClipOptions struct {
+	// DemoteCollapsed keeps geometries which collapse to a lower dimension when clipped, such as a polygon meeting
+	// the clip area only along an edge or a line touching it at a point, as lines or points. By default they are
+	// dropped.
+	DemoteCollapsed bool
+}
+
+// clipper clips geometries to an area
+type clipper interface {
+	containsPoint(p Point) bool
+	// clipSegment returns the parts of the segment a-b inside the area, in order from a to b
+	clipSegment(a, b Point) [][2]Point
+	clipPolygons(mp MultiPolygon) MultiPolygon
+}
+
+// bboxClipper clips geometries to a bounding box, including its edges
+type bboxClipper struct {
+	box BBox
+}
+
+func (c bboxClipper) containsPoint(p Point) bool {
+	return c.box.Contains(p)
+}
+
+// clipSegment clips a segment with the Liang-Barsky algorithm
+func (c bboxClipper) clipSegment(a, b Point) [][2]Point {
+	if c.box.IsEmpty() {
+		return nil
+	}
+
+	dx, dy := b.X-a.X, b.Y-a.Y
+	t0, t1 := 0.0, 1.0
+
+	for _, e := range [4][2]float64{
+		{-dx, a.X - c.box.MinX},
+		{dx, c.box.MaxX - a.X},
+		{-dy, a.Y - c.box.MinY},
+		{dy, c.box.MaxY - a.Y},
+	} {
+		p, q := e[0], e[1]
+
+		switch {
+		case p == 0:
+			if q < 0 {
+				return nil
+			}
+		case p < 0:
+			if r := q / p; r > t1 {
+				return nil
+			} else if r > t0 {
+				t0 = r
+			}
+		default:
+			if r := q / p; r < t0 {
+				return nil
+			} else if r < t1 {
+				t1 = r
+			}
+		}
+	}
+
+	pa, pb := a, b
+	if t0 > 0 {
+		pa = Point{X: a.X + t0*dx, Y: a.Y + t0*dy}
+	}
+	if t1 < 1 {
+		pb = Point{X: a.X + t1*dx, Y: a.Y + t1*dy}
+	}
+
+	return [][2]Point{{pa, pb}}
+}
+
+// clipRing clips a ring to the box with the Sutherland-Hodgman algorithm. Where the ring leaves the box and comes
+// back, the result runs along the side of the box in between, so parts of the ring may be joined by edges of zero
+// width lying on the sides.
+func (c bboxClipper) clipRing(r MultiPoint) MultiPoint {
+	sides := []struct {
+		inside func(p Point) bool
+		cross  func(a, b Point) Point
+	}{
+		{func(p Point) bool { return p.X >= c.box.MinX }, func(a, b Point) Point { return crossAtX(a, b, c.box.MinX) }},
+		{func(p Point) bool { return p.X <= c.box.MaxX }, func(a, b Point) Point { return crossAtX(a, b, c.box.MaxX) }},
+		{func(p Point) bool { return p.Y >= c.box.MinY }, func(a, b Point) Point { return crossAtY(a, b, c.box.MinY) }},
+		{func(p Point) bool { return p.Y <= c.box.MaxY }, func(a, b Point) Point { return crossAtY(a, b, c.box.MaxY) }},
+	}
+
+	pts := ringVertices(r)
+
+	for _, s := range sides {
+		if len(pts) == 0 {
+			break
+		}
+
+		out := make(MultiPoint, 0, len(pts))
+		prev := pts[len(pts)-1]
+
+		for _, p := range pts {
+			switch {
+			case s.inside(p):
+				if !s.inside(prev) {
+					out = append(out, s.cross(prev, p))
+				}
+				out = append(out, p)
+			case s.inside(prev):
+				out = append(out, s.cross(prev, p))
+			}
+
+			prev = p
+		}
+
+		pts = out
+	}
+
+	return pts
+}
+
+// crossAtX returns the point where the segment a-b crosses the vertical line x = c
+func crossAtX(a, b Point, c float64) Point {
+	return Point{X: c, Y: a.Y + (c-a.X)/(b.X-a.X)*(b.Y-a.Y)}
+}
+
+// crossAtY returns the point where the segment a-b crosses the horizontal line y = c
+func crossAtY(a, b Point, c float64) Point {
+	return Point{X: a.X + (c-a.Y)/(b.Y-a.Y)*(b.X-a.X), Y: c}
+}
+
+// clipPolygons clips the rings of the polygons with the Sutherland-Hodgman algorithm and then removes the edges of
+// zero width it leaves on the sides of the box: the edges along each side are split at every vertex on it, and
+// pieces covered in both directions cancel out. The remaining edges, which have the interior of the result on their
+// left, are linked into rings as in overlay.
+func (c bboxClipper) clipPolygons(mp MultiPolygon) MultiPolygon {
+	b := mp.Bounds()
+
+	switch {
+	case !b.Intersects(c.box):
+		return MultiPolygon{}
+	case c.box.ContainsBBox(b):
+		return mp
+	}
+
+	lines := [4]float64{c.box.MinX, c.box.MaxX, c.box.MinY, c.box.MaxY}
+
+	// side returns the position of a point along a side of the box, if it lies on it
+	side := func(s int, p Point) (float64, bool) {
+		if s < 2 {
+			return p.Y, p.X == lines[s]
+		}
+
+		return p.X, p.Y == lines[s]
+	}
+
+	onSide := func(s int, along float64) Point {
+		if s < 2 {
+			return Point{X: lines[s], Y: along}
+		}
+
+		return Point{X: along, Y: lines[s]}
+	}
+
+	type sideEdge struct {
+		from, to float64
+	}
+
+	edges := make([]*overlayEdge, 0)
+	nodes := [4][]float64{}
+	sideEdges := [4][]sideEdge{}
+
+	for _, p := range mp.rewind() {
+		for _, r := range p {
+			pts := c.clipRing(r)
+
+			for x := range pts {
+				a, b := pts[x], pts[(x+1)%len(pts)]
+
+				for s := range 4 {
+					if along, ok := side(s, a); ok {
+						nodes[s] = append(nodes[s], along)
+					}
+				}
+
+				if a.equals(b) {
+					continue
+				}
+
+				lies := false
+				for s := range 4 {
+					from, okA := side(s, a)
+					to, okB := side(s, b)
+
+					if okA && okB {
+						sideEdges[s] = append(sideEdges[s], sideEdge{from: from, to: to})
+						lies = true
+
+						break
+					}
+				}
+
+				if !lies {
+					edges = append(edges, &overlayEdge{from: a, to: b})
+				}
+			}
+		}
+	}
+
+	for s := range 4 {
+		sort.Float64s(nodes[s])
+		nodes[s] = slices.Compact(nodes[s])
+
+		// net coverage of each interval between nodes, counting edges running towards greater positions as 1 and
+		// the others as -1
+		net := make([]int, len(nodes[s]))
+		for _, e := range sideEdges[s] {
+			i, _ := slices.BinarySearch(nodes[s], min(e.from, e.to))
+			j, _ := slices.BinarySearch(nodes[s], max(e.from, e.to))
+
+			d := 1
+			if e.to < e.from {
+				d = -1
+			}
+
+			net[i] += d
+			net[j] -= d
+		}
+
+		count := 0
+		for x := 0; x+1 < len(nodes[s]); x++ {
+			count += net[x]
+
+			a, b := onSide(s, nodes[s][x]), onSide(s, nodes[s][x+1])
+
+			switch {
+			case count > 0:
+				edges = append(edges, &overlayEdge{from: a, to: b})
+			case count < 0:
+				edges = append(edges, &overlayEdge{from: b, to: a})
+			}
+		}
+	}
+
+	rings := make([]MultiPoint, 0)
+	for _, r := range linkOverlayEdges(edges) {
+		rings = append(rings, splitRingAtTouches(r)...)
+	}
+
+	for x := range rings {
+		rings[x] = removeCollinear(rings[x])
+	}
+
+	return assembleRings(rings)
+}
+
+// areaClipper clips geometries to polygons, including their boundaries
+type areaClipper struct {
+	area MultiPolygon
+	fi   *facetIndex
+}
+
+func newAreaClipper(area MultiPolygon) areaClipper {
+	return areaClipper{area: area, fi: newFacetIndex(areaGeometry(area))}
+}
+
+func (c areaClipper) containsPoint(p Point) bool {
+	return c.fi.geom.locator.locate(p) != locExterior
+}
+
+// clipSegment splits the segment where it meets the boundary of the area and keeps the pieces whose midpoint is
+// not outside it
+func (c areaClipper) clipSegment(a, b Point) [][2]Point {
+	if a.equals(b) {
+		if c.containsPoint(a) {
+			return [][2]Point{{a, a}}
+		}
+
+		return nil
+	}
+
+	type cut struct {
+		t float64
+		p Point
+	}
+
+	dx, dy := b.X-a.X, b.Y-a.Y
+	l2 := dx*dx + dy*dy
+	cuts := []cut{{0, a}, {1, b}}
+
+	for _, id := range c.fi.si.Search(EmptyBBox().ExtendPoint(a).ExtendPoint(b)) {
+		f := c.fi.facets[id]
+
+		for _, p := range intersectSegments(a, b, f.a, f.b).Points {
+			if t := ((p.X-a.X)*dx + (p.Y-a.Y)*dy) / l2; t > 0 && t < 1 {
+				cuts = append(cuts, cut{t, p})
+			}
+		}
+	}
+
+	sort.Slice(cuts, func(i, j int) bool { return cuts[i].t < cuts[j].t })
+
+	out := make([][2]Point, 0)
+	for x := 1; x < len(cuts); x++ {
+		p, q := cuts[x-1].p, cuts[x].p
+		if p.equals(q) {
+			continue
+		}
+
+		if c.containsPoint(Point{X: (p.X + q.X) / 2, Y: (p.Y + q.Y) / 2}) {
+			out = append(out, [2]Point{p, q})
+		}
+	}
+
+	if len(out) == 0 {
+		// the segment at most touches the area
+		for _, ct := range cuts {
+			if c.containsPoint(ct.p) {
+				out = append(out, [2]Point{ct.p, ct.p})
+			}
+		}
+	}
+
+	return out
+}
+
+func (c areaClipper) clipPolygons(mp MultiPolygon) MultiPolygon {
+	return overlay(mp, c.area, overlayIntersection)
+}
+
+// clipLines clips lines, returning the parts of positive length and, separately, the points where the lines only
+// touch the area
+func clipLines(lines []MultiPoint, c clipper) ([]MultiPoint, MultiPoint) {
+	parts := make([]MultiPoint, 0)
+	touches := make(MultiPoint, 0)
+
+	for _, l := range lines {
+		var current MultiPoint
+
+		flush := func() {
+			if len(current) > 1 {
+				parts = append(parts, current)
+			} else if len(current) == 1 {
+				touches = append(touches, current[0])
+			}
+
+			current = nil
+		}
+
+		segments := make([][2]Point, 0)
+		if len(l) == 1 {
+			segments = append(segments, [2]Point{l[0], l[0]})
+		}
+		for x := 1; x < len(l); x++ {
+			segments = append(segments, [2]Point{l[x-1], l[x]})
+		}
+
+		for _, s := range segments {
+			for _, piece := range c.clipSegment(s[0], s[1]) {
+				if len(current) == 0 || !current[len(current)-1].equals(piece[0]) {
+					flush()
+					current = MultiPoint{piece[0]}
+				}
+
+				if !piece[1].equals(current[len(current)-1]) {
+					current = append(current, piece[1])
+				}
+			}
+		}
+
+		flush()
+	}
+
+	return parts, uniquePoints(touches)
+}
+
+// uniquePoints returns the points with duplicates removed, keeping their order
+func uniquePoints(pts MultiPoint) MultiPoint {
+	seen := make(map[Point]bool)
+	out := make(MultiPoint, 0, len(pts))
+
+	for _, p := range pts {
+		if !seen[p] {
+			seen[p] = true
+			out = append(out, p)
+		}
+	}
+
+	return out
+}
+
+// lineFeature returns a feature holding lines, as a LineString when there is a single one
+func lineFeature(lines []MultiPoint, properties map[string]any) Feature {
+	if len(lines) == 1 {
+		return Feature{Type: "LineString", Properties: properties, Coordinates: lines[0]}
+	}
+
+	return Feature{Type: "MultiLineString", Properties: properties, Coordinates: Polygon(lines)}
+}
+
+// pointFeature returns a feature holding points, as a Point when there is a single one
+func pointFeature(pts MultiPoint, properties map[string]any) Feature {
+	if len(pts) == 1 {
+		return Feature{Type: "Point", Properties: properties, Coordinates: pts[0]}
+	}
+
+	return Feature{Type: "MultiPoint", Properties: properties, Coordinates: pts}
+}
+
+// clipLineFeature returns a feature holding clipped lines, or the points where they touch the area if they
+// collapsed and collapsed geometries are kept. It reports false if nothing is left.
+func clipLineFeature(lines []MultiPoint, c clipper, properties map[string]any, opts ClipOptions) (Feature, bool) {
+	parts, touches := clipLines(lines, c)
+
+	switch {
+	case len(parts) > 0:
+		return lineFeature(parts, properties), true
+	case opts.DemoteCollapsed && len(touches) > 0:
+		return pointFeature(touches, properties), true
+	}
+
+	return Feature{}, false
+}
+
+// clipped returns the part of a feature inside the area of the clipper, sharing the properties of f. It reports
+// false if nothing is left.
+func (f *Feature) clipped(c clipper, opts ClipOptions) (Feature, bool, error) {
+	switch f.Type {
+	case "Point":
+		return *f, c.containsPoint(f.Coordinates.(Point)), nil
+	case "MultiPoint":
+		pts := make(MultiPoint, 0)
+		for _, p := range f.Coordinates.(MultiPoint) {
+			if c.containsPoint(p) {
+				pts = append(pts, p)
+			}
+		}
+
+		return Feature{Type: f.Type, Properties: f.Properties, Coordinates: pts}, len(pts) > 0, nil
+	case "LineString":
+		out, ok := clipLineFeature([]MultiPoint{f.Coordinates.(MultiPoint)}, c, f.Properties, opts)
+		return out, ok, nil
+	case "MultiLineString":
+		out, ok := clipLineFeature(f.Coordinates.(Polygon), c, f.Properties, opts)
+		return out, ok, nil
+	case "Polygon", "MultiPolygon":
+		mp, _ := f.polygons()
+
+		if clipped := c.clipPolygons(mp); len(clipped) > 0 {
+			return polygonalFeature(clipped, f.Properties), true, nil
+		}
+
+		if !opts.DemoteCollapsed {
+			return Feature{}, false, nil
+		}
+
+		rings := make([]MultiPoint, 0)
+		for _, p := range mp {
+			for _, r := range p {
+				rings = append(rings, closeRing(r))
+			}
+		}
+
+		out, ok := clipLineFeature(rings, c, f.Properties, opts)
+		return out, ok, nil
+	}
+
+	return Feature{}, false, GeoTypeError{Type: f.Type}
+}
+
+// clipCollection clips every feature of the collection, dropping those left empty
+func (fc *FeatureCollection) clipCollection(c clipper, opts ClipOptions) (FeatureCollection, error) {
+	out := fc.emptyCopy()
+
+	for x := range fc.Features {
+		f, ok, err := fc.Features[x].clipped(c, opts)
+		if err != nil {
+			return FeatureCollection{}, err
+		}
+
+		if ok {
+			out.AddFeature(f)
+		}
+	}
+
+	return out, nil
+}
+
+// Clip returns the parts of the features inside a bounding box, including its edges, keeping their properties.
+// Features left empty are dropped, and so are features which collapse to a lower dimension unless
+// opts.DemoteCollapsed is set. Features lying entirely inside the box are returned unchanged.
+func (fc *FeatureCollection) Clip(b BBox, opts ClipOptions) (FeatureCollection, error) {
+	return fc.clipCollection(bboxClipper{box: b}, opts)
+}
+
+// ClipToPolygon returns the parts of the features inside a multipolygon, including its boundary, keeping their
+// properties. Features left empty are dropped, and so are features which collapse to a lower dimension unless
+// opts.DemoteCollapsed is set.
+func (fc *FeatureCollection) ClipToPolygon(area MultiPolygon, opts ClipOptions) (FeatureCollection, error) {
+	return fc.clipCollection(newAreaClipper(area), opts)
+}
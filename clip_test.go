@@ -0,0 +1,164 @@
+package gegography
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// clipTest is a feature clipped by clipTests, which is either dropped or clipped into a feature of the given type,
+// length and area
+type clipTest struct {
+	name    string
+	wkt     string
+	opts    ClipOptions
+	want    string
+	length  float64
+	area    float64
+	dropped bool
+}
+
+// clipTests clips a collection holding the feature of each test with clip, which is named fn in messages, and
+// checks that the results lie within bounds
+func clipTests(t *testing.T, fn string, clip func(*FeatureCollection, ClipOptions) (FeatureCollection, error), bounds BBox, tests []clipTest) {
+	t.Helper()
+
+	for _, tt := range tests {
+		f := mustParseWKT(t, tt.wkt)
+		f.Properties["name"] = tt.name
+
+		fc := FeatureCollection{Name: "test", Features: []Feature{f}}
+
+		got, err := clip(&fc, tt.opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if got.Name != "test" {
+			t.Errorf("%s(%s), want the collection name test got %q", fn, tt.wkt, got.Name)
+		}
+
+		if tt.dropped {
+			if len(got.Features) != 0 {
+				t.Errorf("%s(%s) %s, want the feature dropped got %v", fn, tt.wkt, tt.name, got.Features)
+			}
+			continue
+		}
+
+		if len(got.Features) != 1 {
+			t.Errorf("%s(%s) %s, want 1 feature got %d", fn, tt.wkt, tt.name, len(got.Features))
+			continue
+		}
+
+		g := got.Features[0]
+		if g.Type != tt.want || g.Properties["name"] != tt.name {
+			t.Errorf("%s(%s) %s, want a %s keeping its properties got a %s with %v", fn, tt.wkt, tt.name, tt.want, g.Type, g.Properties)
+		}
+
+		if l := g.Length(); math.Abs(l-tt.length) > 1e-9 {
+			t.Errorf("%s(%s) %s, want length %v got %v", fn, tt.wkt, tt.name, tt.length, l)
+		}
+
+		if a := g.Area(); math.Abs(a-tt.area) > 1e-9 {
+			t.Errorf("%s(%s) %s, want area %v got %v", fn, tt.wkt, tt.name, tt.area, a)
+		}
+
+		if !bounds.ContainsBBox(g.Bounds()) {
+			t.Errorf("%s(%s) %s, want a result within %v got %v", fn, tt.wkt, tt.name, bounds, g.Coordinates)
+		}
+	}
+}
+
+func TestClip(t *testing.T) {
+	box := BBox{MinX: 0, MinY: 0, MaxX: 10, MaxY: 10}
+
+	clipTests(t, "Clip", func(fc *FeatureCollection, opts ClipOptions) (FeatureCollection, error) {
+		return fc.Clip(box, opts)
+	}, box, []clipTest{
+		{name: "point inside", wkt: "POINT (5 5)", want: "Point"},
+		{name: "point on the edge", wkt: "POINT (10 5)", want: "Point"},
+		{name: "point outside", wkt: "POINT (11 5)", dropped: true},
+		{name: "multipoint", wkt: "MULTIPOINT ((1 1), (20 20))", want: "MultiPoint"},
+		{name: "line crossing", wkt: "LINESTRING (-5 5, 15 5)", want: "LineString", length: 10},
+		{name: "line leaving and returning", wkt: "LINESTRING (2 2, 15 2, 15 8, 2 8)", want: "MultiLineString", length: 16},
+		{name: "line along the edge", wkt: "LINESTRING (-5 0, 5 0)", want: "LineString", length: 5},
+		{name: "line touching a corner", wkt: "LINESTRING (-5 5, 0 10, -5 15)", dropped: true},
+		{name: "line touching a corner demoted", wkt: "LINESTRING (-5 5, 0 10, -5 15)", opts: ClipOptions{DemoteCollapsed: true}, want: "Point"},
+		{name: "polygon overlapping", wkt: "POLYGON ((5 5, 15 5, 15 15, 5 15, 5 5))", want: "Polygon", area: 25},
+		{name: "polygon inside", wkt: "POLYGON ((2 2, 8 2, 8 8, 2 8, 2 2))", want: "Polygon", area: 36},
+		{
+			name: "polygon split in two",
+			wkt:  "POLYGON ((2 2, 15 2, 15 8, 2 8, 2 6, 12 6, 12 4, 2 4, 2 2))",
+			want: "MultiPolygon", area: 32,
+		},
+		{
+			name: "polygon with a hole",
+			wkt:  "POLYGON ((-5 -5, 15 -5, 15 15, -5 15, -5 -5), (2 2, 8 2, 8 8, 2 8, 2 2))",
+			want: "Polygon", area: 64,
+		},
+		{name: "polygon sharing an edge", wkt: "POLYGON ((10 0, 20 0, 20 10, 10 10, 10 0))", dropped: true},
+		{
+			name: "polygon sharing an edge demoted", wkt: "POLYGON ((10 0, 20 0, 20 10, 10 10, 10 0))",
+			opts: ClipOptions{DemoteCollapsed: true}, want: "LineString", length: 10,
+		},
+		{name: "polygon outside", wkt: "POLYGON ((20 20, 30 20, 30 30, 20 30, 20 20))", opts: ClipOptions{DemoteCollapsed: true}, dropped: true},
+	})
+}
+
+func TestClipAgainstOverlay(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+
+	for x := range 300 {
+		p := randomStar(r, 5+r.Intn(30))
+		if x%2 == 0 {
+			// vertices on whole numbers often lie on the sides of the box
+			for y := range p[0] {
+				p[0][y] = Point{X: math.Round(p[0][y].X), Y: math.Round(p[0][y].Y)}
+			}
+			p[0] = closeRing(ringVertices(p[0]))
+			if isDegenerateRing(ringVertices(p[0])) || p.Validate() != nil {
+				continue
+			}
+		}
+
+		box := BBox{MinX: float64(r.Intn(8)), MinY: float64(r.Intn(8))}
+		box.MaxX, box.MaxY = box.MinX+float64(1+r.Intn(8)), box.MinY+float64(1+r.Intn(8))
+
+		got := bboxClipper{box: box}.clipPolygons(MultiPolygon{p})
+		want := overlay(MultiPolygon{p}, MultiPolygon{box.ToPolygon()}, overlayIntersection)
+
+		if math.Abs(got.Area()-want.Area()) > 1e-9 {
+			t.Errorf("%d: clipPolygons(%v) to %v, want area %v got %v", x, p, box, want.Area(), got.Area())
+		}
+
+		if err := got.Validate(); err != nil {
+			t.Errorf("%d: clipPolygons(%v) to %v, want a valid result got %v", x, p, box, err)
+		}
+	}
+}
+
+func TestClipToPolygon(t *testing.T) {
+	area := MultiPolygon{mustParseWKT(t, "POLYGON ((0 0, 10 0, 10 10, 5 5, 0 10, 0 0))").Coordinates.(Polygon)}
+
+	clipTests(t, "ClipToPolygon", func(fc *FeatureCollection, opts ClipOptions) (FeatureCollection, error) {
+		return fc.ClipToPolygon(area, opts)
+	}, area.Bounds(), []clipTest{
+		{name: "point inside", wkt: "POINT (5 2)", want: "Point"},
+		{name: "point in the notch", wkt: "POINT (5 8)", dropped: true},
+		{name: "line through the notch", wkt: "LINESTRING (0 7, 10 7)", want: "MultiLineString", length: 6},
+		{name: "line along the boundary", wkt: "LINESTRING (-5 0, 5 0)", want: "LineString", length: 5},
+		{name: "line touching the tip", wkt: "LINESTRING (3 8, 5 5, 7 8)", dropped: true},
+		{name: "line touching the tip demoted", wkt: "LINESTRING (3 8, 5 5, 7 8)", opts: ClipOptions{DemoteCollapsed: true}, want: "Point"},
+		{name: "polygon", wkt: "POLYGON ((-5 -5, 15 -5, 15 5, -5 5, -5 -5))", want: "Polygon", area: 50},
+		{name: "polygon split by the notch", wkt: "POLYGON ((0 6, 10 6, 10 8, 0 8, 0 6))", want: "MultiPolygon", area: 12},
+		{
+			name: "polygon sharing an edge demoted", wkt: "POLYGON ((0 0, 10 0, 10 -5, 0 -5, 0 0))",
+			opts: ClipOptions{DemoteCollapsed: true}, want: "LineString", length: 10,
+		},
+	})
+
+	bad := FeatureCollection{Features: []Feature{{Type: "Circle"}}}
+	if _, err := bad.ClipToPolygon(area, ClipOptions{}); err == nil {
+		t.Error("ClipToPolygon(Circle), want an error for an unsupported geometry type")
+	}
+}
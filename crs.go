@@ -0,0 +1,621 @@
+package gegography
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// CRSError describes an error involving an unknown or unsupported coordinate reference system
+type CRSError struct {
+	CRS string
+}
+
+func (c CRSError) Error() string {
+	return fmt.Sprintf("%s: unknown or unsupported coordinate reference system.", c.CRS)
+}
+
+// Datum describes a geodetic datum. ToWGS84 holds the Helmert parameters converting geocentric coordinates on
+// the datum to WGS84, and GridShift an NTv2 grid converting longitude/latitude on the datum to a datum
+// coinciding with WGS84 (such as NAD83 or ETRS89). A grid shift takes precedence over Helmert parameters, and
+// datums with neither are assumed to coincide with WGS84.
+type Datum struct {
+	Name      string
+	Ellipsoid Ellipsoid
+	ToWGS84   *Helmert
+	GridShift *NTv2Grid
+}
+
+// Common geodetic datums
+var (
+	WGS84Datum    = Datum{Name: "WGS 84", Ellipsoid: WGS84Ellipsoid}
+	ETRS89Datum   = Datum{Name: "ETRS89", Ellipsoid: GRS80Ellipsoid}
+	SWEREF99Datum = Datum{Name: "SWEREF99", Ellipsoid: GRS80Ellipsoid}
+	NAD83Datum    = Datum{Name: "NAD83", Ellipsoid: GRS80Ellipsoid}
+	GDA94Datum    = Datum{Name: "GDA94", Ellipsoid: GRS80Ellipsoid}
+	RGF93Datum    = Datum{Name: "RGF93", Ellipsoid: GRS80Ellipsoid}
+)
+
+// CRSUnit is a unit of measure of a coordinate reference system, with the factor converting it to metres (for
+// linear units) or radians (for angular units)
+type CRSUnit struct {
+	Name   string
+	Factor float64
+}
+
+// Common units of measure
+var (
+	MetreUnit        = CRSUnit{Name: "metre", Factor: 1}
+	DegreeUnit       = CRSUnit{Name: "degree", Factor: 0.0174532925199433}
+	USSurveyFootUnit = CRSUnit{Name: "US survey foot", Factor: 0.304800609601219}
+	FootUnit         = CRSUnit{Name: "foot", Factor: 0.3048}
+)
+
+// CRSAxis describes an axis of a coordinate reference system, such as "Easting" pointing "east"
+type CRSAxis struct {
+	Name      string
+	Direction string
+}
+
+// CRSParameter is a named parameter of a map projection
+type CRSParameter struct {
+	Name  string
+	Value float64
+}
+
+// CRSDefinition describes a coordinate reference system. Geographic coordinate reference systems have no
+// projection and use longitude/latitude, with X being the longitude.
+//
+// PrimeMeridian is the longitude of the prime meridian east of Greenwich, in degrees. Unit is the unit of the
+// coordinates, with the zero value meaning degrees for geographic and metres for projected systems. Projection
+// parameters, such as the central meridian, are relative to the prime meridian. ProjectionMethod and
+// ProjectionParameters hold the projection as it was named in a parsed WKT definition; when the method is not
+// supported Projection is nil and the coordinates cannot be converted. Axes lists the axes in the order defined
+// by the authority of the system, which may differ from the X/Y order of the coordinates (see AxisOrder).
+// AreaOfUse is the longitude/latitude extent in which the system is meant to be used, or nil if unknown.
+type CRSDefinition struct {
+	Name                 string
+	EPSG                 int
+	Datum                Datum
+	Projection           Projection
+	PrimeMeridian        float64
+	Unit                 CRSUnit
+	Axes                 []CRSAxis
+	ProjectionMethod     string
+	ProjectionParameters []CRSParameter
+	AreaOfUse            *BBox
+}
+
+// IsGeographic reports whether the coordinate reference system uses longitude/latitude coordinates
+func (d CRSDefinition) IsGeographic() bool {
+	return d.Projection == nil && d.ProjectionMethod == ""
+}
+
+// unit returns the unit of the coordinates, applying the default when none is set
+func (d CRSDefinition) unit() CRSUnit {
+	switch {
+	case d.Unit.Factor != 0:
+		return d.Unit
+	case d.IsGeographic():
+		return DegreeUnit
+	default:
+		return MetreUnit
+	}
+}
+
+// ToGeographic converts a point in the coordinate reference system to longitude/latitude in degrees on its datum,
+// with longitudes relative to Greenwich
+func (d CRSDefinition) ToGeographic(p Point) (Point, error) {
+	factor := d.unit().Factor
+
+	switch {
+	case d.IsGeographic():
+		p = Point{X: angleToDegrees(p.X, factor), Y: angleToDegrees(p.Y, factor)}
+	case d.Projection == nil:
+		return Point{}, CRSError{CRS: d.Name}
+	default:
+		var err error
+
+		p, err = d.Projection.Inverse(Point{X: p.X * factor, Y: p.Y * factor})
+		if err != nil {
+			return Point{}, err
+		}
+	}
+
+	p.X += d.PrimeMeridian
+
+	return p, nil
+}
+
+// FromGeographic converts a longitude/latitude point in degrees on the datum to the coordinate reference system
+func (d CRSDefinition) FromGeographic(p Point) (Point, error) {
+	factor := d.unit().Factor
+	p.X -= d.PrimeMeridian
+
+	switch {
+	case d.IsGeographic():
+		return Point{X: degreesToAngle(p.X, factor), Y: degreesToAngle(p.Y, factor)}, nil
+	case d.Projection == nil:
+		return Point{}, CRSError{CRS: d.Name}
+	}
+
+	p, err := d.Projection.Forward(p)
+	if err != nil {
+		return Point{}, err
+	}
+
+	return Point{X: p.X / factor, Y: p.Y / factor}, nil
+}
+
+// angleToDegrees converts an angle in a unit with the given factor to radians into degrees. Degrees are returned
+// as is, as the factor is usually rounded.
+func angleToDegrees(v, factor float64) float64 {
+	if math.Abs(factor-DegreeUnit.Factor) < 1e-15 {
+		return v
+	}
+
+	return toDegrees(v * factor)
+}
+
+// degreesToAngle reverses the conversion done by angleToDegrees
+func degreesToAngle(v, factor float64) float64 {
+	if math.Abs(factor-DegreeUnit.Factor) < 1e-15 {
+		return v
+	}
+
+	return toRadians(v) / factor
+}
+
+// ToCRS returns the GeoJSON crs member naming the coordinate reference system by its EPSG code
+func (d CRSDefinition) ToCRS() CRS {
+	return CRS{Type: "name", Properties: CRSProperties{Name: fmt.Sprintf("urn:ogc:def:crs:EPSG::%d", d.EPSG)}}
+}
+
+func geographicCRS(code int, name string, datum Datum) CRSDefinition {
+	return CRSDefinition{Name: name, EPSG: code, Datum: datum}
+}
+
+func projectedCRS(code int, name string, datum Datum, p Projection) CRSDefinition {
+	return CRSDefinition{Name: name, EPSG: code, Datum: datum, Projection: p}
+}
+
+func sweref99Zone(code int, name string, centralMeridian float64) CRSDefinition {
+	return projectedCRS(code, "SWEREF99 "+name, SWEREF99Datum, TransverseMercator{
+		Ellipsoid:       GRS80Ellipsoid,
+		CentralMeridian: centralMeridian,
+		ScaleFactor:     1,
+		FalseEasting:    150000,
+	})
+}
+
+// epsgTable holds the coordinate reference systems known by their EPSG code, apart from the UTM zones which
+// are generated by LookupEPSG
+var epsgTable = map[int]CRSDefinition{
+	4326: geographicCRS(4326, "WGS 84", WGS84Datum),
+	4258: geographicCRS(4258, "ETRS89", ETRS89Datum),
+	4619: geographicCRS(4619, "SWEREF99", SWEREF99Datum),
+	4269: geographicCRS(4269, "NAD83", NAD83Datum),
+	4283: geographicCRS(4283, "GDA94", GDA94Datum),
+	4171: geographicCRS(4171, "RGF93", RGF93Datum),
+	4124: geographicCRS(4124, "RT90", RT90Datum),
+	4230: geographicCRS(4230, "ED50", ED50Datum),
+	4267: geographicCRS(4267, "NAD27", NAD27Datum),
+	4277: geographicCRS(4277, "OSGB36", OSGB36Datum),
+	4314: geographicCRS(4314, "DHDN", DHDNDatum),
+
+	3857: projectedCRS(3857, "WGS 84 / Pseudo-Mercator", WGS84Datum, WebMercator{Ellipsoid: WGS84Ellipsoid}),
+
+	3006: projectedCRS(3006, "SWEREF99 TM", SWEREF99Datum, TransverseMercator{
+		Ellipsoid:       GRS80Ellipsoid,
+		CentralMeridian: 15,
+		ScaleFactor:     0.9996,
+		FalseEasting:    500000,
+	}),
+	3007: sweref99Zone(3007, "12 00", 12),
+	3008: sweref99Zone(3008, "13 30", 13.5),
+	3009: sweref99Zone(3009, "15 00", 15),
+	3010: sweref99Zone(3010, "16 30", 16.5),
+	3011: sweref99Zone(3011, "18 00", 18),
+	3012: sweref99Zone(3012, "14 15", 14.25),
+	3013: sweref99Zone(3013, "15 45", 15.75),
+	3014: sweref99Zone(3014, "17 15", 17.25),
+	3015: sweref99Zone(3015, "18 45", 18.75),
+	3016: sweref99Zone(3016, "20 15", 20.25),
+	3017: sweref99Zone(3017, "21 45", 21.75),
+	3018: sweref99Zone(3018, "23 15", 23.25),
+
+	3021: projectedCRS(3021, "RT90 2.5 gon V", RT90Datum, TransverseMercator{
+		Ellipsoid:       Bessel1841Ellipsoid,
+		CentralMeridian: 15 + 48.0/60 + 29.8/3600,
+		ScaleFactor:     1,
+		FalseEasting:    1500000,
+	}),
+	27700: projectedCRS(27700, "OSGB36 / British National Grid", OSGB36Datum, TransverseMercator{
+		Ellipsoid:        Airy1830Ellipsoid,
+		LatitudeOfOrigin: 49,
+		CentralMeridian:  -2,
+		ScaleFactor:      0.9996012717,
+		FalseEasting:     400000,
+		FalseNorthing:    -100000,
+	}),
+	31467: projectedCRS(31467, "DHDN / 3-degree Gauss-Kruger zone 3", DHDNDatum, TransverseMercator{
+		Ellipsoid:       Bessel1841Ellipsoid,
+		CentralMeridian: 9,
+		ScaleFactor:     1,
+		FalseEasting:    3500000,
+	}),
+
+	3034: projectedCRS(3034, "ETRS89-extended / LCC Europe", ETRS89Datum, LambertConformalConic{
+		Ellipsoid:         GRS80Ellipsoid,
+		LatitudeOfOrigin:  52,
+		CentralMeridian:   10,
+		StandardParallel1: 35,
+		StandardParallel2: 65,
+		FalseEasting:      4000000,
+		FalseNorthing:     2800000,
+	}),
+	2154: projectedCRS(2154, "RGF93 v1 / Lambert-93", RGF93Datum, LambertConformalConic{
+		Ellipsoid:         GRS80Ellipsoid,
+		LatitudeOfOrigin:  46.5,
+		CentralMeridian:   3,
+		StandardParallel1: 49,
+		StandardParallel2: 44,
+		FalseEasting:      700000,
+		FalseNorthing:     6600000,
+	}),
+	3347: projectedCRS(3347, "NAD83 / Statistics Canada Lambert", NAD83Datum, LambertConformalConic{
+		Ellipsoid:         GRS80Ellipsoid,
+		LatitudeOfOrigin:  63.390675,
+		CentralMeridian:   -91.86666666666666,
+		StandardParallel1: 49,
+		StandardParallel2: 77,
+		FalseEasting:      6200000,
+		FalseNorthing:     3000000,
+	}),
+	5070: projectedCRS(5070, "NAD83 / Conus Albers", NAD83Datum, AlbersEqualArea{
+		Ellipsoid:         GRS80Ellipsoid,
+		LatitudeOfOrigin:  23,
+		CentralMeridian:   -96,
+		StandardParallel1: 29.5,
+		StandardParallel2: 45.5,
+	}),
+	3577: projectedCRS(3577, "GDA94 / Australian Albers", GDA94Datum, AlbersEqualArea{
+		Ellipsoid:         GRS80Ellipsoid,
+		CentralMeridian:   132,
+		StandardParallel1: -18,
+		StandardParallel2: -36,
+	}),
+}
+
+// northEastCRS holds the projected coordinate reference systems of epsgTable whose first axis is the northing
+var northEastCRS = map[int]bool{
+	3006: true, 3007: true, 3008: true, 3009: true, 3010: true, 3011: true, 3012: true, 3013: true, 3014: true,
+	3015: true, 3016: true, 3017: true, 3018: true, 3021: true, 3034: true, 31467: true,
+}
+
+// epsgAreas holds the approximate areas of use of the coordinate reference systems of epsgTable, in degrees
+var epsgAreas = map[int]BBox{
+	4326: {MinX: -180, MinY: -90, MaxX: 180, MaxY: 90},
+	4258: {MinX: -16.1, MinY: 32.88, MaxX: 40.18, MaxY: 84.73},
+	4619: swedenArea,
+	4269: {MinX: -172.54, MinY: 14.92, MaxX: -47.74, MaxY: 86.46},
+	4283: {MinX: 93.41, MinY: -60.56, MaxX: 173.35, MaxY: -8.47},
+	4171: franceArea,
+	4124: swedenArea,
+	4230: {MinX: -16.1, MinY: 25.71, MaxX: 48.61, MaxY: 84.73},
+	4267: {MinX: -172.54, MinY: 7.15, MaxX: -47.74, MaxY: 86.46},
+	4277: {MinX: -8.82, MinY: 49.79, MaxX: 1.92, MaxY: 60.94},
+	4314: {MinX: 5.87, MinY: 47.27, MaxX: 13.84, MaxY: 55.09},
+	3857: {MinX: -180, MinY: -85.06, MaxX: 180, MaxY: 85.06},
+	3006: swedenArea, 3007: swedenArea, 3008: swedenArea, 3009: swedenArea, 3010: swedenArea, 3011: swedenArea,
+	3012: swedenArea, 3013: swedenArea, 3014: swedenArea, 3015: swedenArea, 3016: swedenArea, 3017: swedenArea,
+	3018: swedenArea, 3021: swedenArea,
+	27700: {MinX: -9.01, MinY: 49.75, MaxX: 2.01, MaxY: 61.01},
+	31467: {MinX: 7.5, MinY: 47.27, MaxX: 10.5, MaxY: 55.09},
+	3034:  {MinX: -35.58, MinY: 24.6, MaxX: 44.83, MaxY: 84.73},
+	2154:  franceArea,
+	3347:  {MinX: -141.01, MinY: 40.04, MaxX: -47.74, MaxY: 86.46},
+	5070:  {MinX: -124.79, MinY: 24.41, MaxX: -66.91, MaxY: 49.38},
+	3577:  {MinX: 112.85, MinY: -43.7, MaxX: 153.69, MaxY: -9.86},
+}
+
+var (
+	swedenArea = BBox{MinX: 10.03, MinY: 54.96, MaxX: 24.17, MaxY: 69.07}
+	franceArea = BBox{MinX: -9.86, MinY: 41.15, MaxX: 10.38, MaxY: 51.56}
+)
+
+// LookupEPSG returns the definition of a coordinate reference system by its EPSG code, with the axis order
+// defined by EPSG and its approximate area of use
+func LookupEPSG(code int) (CRSDefinition, error) {
+	d, err := lookupEPSG(code)
+	if err != nil {
+		return d, err
+	}
+
+	d.Datum = withRegisteredGridShift(d.Datum)
+
+	if d.IsGeographic() || northEastCRS[d.EPSG] {
+		d.Axes = []CRSAxis{{Name: "Northing", Direction: "north"}, {Name: "Easting", Direction: "east"}}
+		if d.IsGeographic() {
+			d.Axes = []CRSAxis{{Name: "Latitude", Direction: "north"}, {Name: "Longitude", Direction: "east"}}
+		}
+	}
+
+	if area, ok := epsgAreas[d.EPSG]; ok {
+		d.AreaOfUse = &area
+	} else if tm, ok := d.Projection.(TransverseMercator); ok {
+		// UTM zones
+		area := BBox{MinX: tm.CentralMeridian - 3, MinY: 0, MaxX: tm.CentralMeridian + 3, MaxY: 84}
+		if tm.FalseNorthing != 0 {
+			area.MinY, area.MaxY = -80, 0
+		}
+		d.AreaOfUse = &area
+	}
+
+	return d, nil
+}
+
+func lookupEPSG(code int) (CRSDefinition, error) {
+	if d, ok := epsgTable[code]; ok {
+		return d, nil
+	}
+
+	switch {
+	case code == 900913 || code == 3785 || code == 102100:
+		return epsgTable[3857], nil
+	case code >= 32601 && code <= 32660:
+		zone := code - 32600
+		return projectedCRS(code, fmt.Sprintf("WGS 84 / UTM zone %dN", zone), WGS84Datum, UTM(WGS84Ellipsoid, zone, true)), nil
+	case code >= 32701 && code <= 32760:
+		zone := code - 32700
+		return projectedCRS(code, fmt.Sprintf("WGS 84 / UTM zone %dS", zone), WGS84Datum, UTM(WGS84Ellipsoid, zone, false)), nil
+	case code >= 25828 && code <= 25838:
+		zone := code - 25800
+		return projectedCRS(code, fmt.Sprintf("ETRS89 / UTM zone %dN", zone), ETRS89Datum, UTM(GRS80Ellipsoid, zone, true)), nil
+	case code >= 23028 && code <= 23038:
+		zone := code - 23000
+		return projectedCRS(code, fmt.Sprintf("ED50 / UTM zone %dN", zone), ED50Datum, UTM(International1924Ellipsoid, zone, true)), nil
+	case code >= 26703 && code <= 26722:
+		zone := code - 26700
+		return projectedCRS(code, fmt.Sprintf("NAD27 / UTM zone %dN", zone), NAD27Datum, UTM(Clarke1866Ellipsoid, zone, true)), nil
+	case code >= 26901 && code <= 26923:
+		zone := code - 26900
+		return projectedCRS(code, fmt.Sprintf("NAD83 / UTM zone %dN", zone), NAD83Datum, UTM(GRS80Ellipsoid, zone, true)), nil
+	}
+
+	return CRSDefinition{}, CRSError{CRS: fmt.Sprintf("EPSG:%d", code)}
+}
+
+// epsgCodes returns every EPSG code known to LookupEPSG, in ascending order
+func epsgCodes() []int {
+	codes := make([]int, 0, len(epsgTable)+150)
+	for code := range epsgTable {
+		codes = append(codes, code)
+	}
+
+	for _, r := range [][2]int{{32601, 32660}, {32701, 32760}, {25828, 25838}, {23028, 23038}, {26703, 26722}, {26901, 26923}} {
+		for code := r[0]; code <= r[1]; code++ {
+			codes = append(codes, code)
+		}
+	}
+
+	sort.Ints(codes)
+
+	return codes
+}
+
+// MatchEPSG returns the EPSG code of a coordinate reference system, such as one parsed from a .prj file. A code
+// given in the definition is returned as is. Otherwise the definition is compared with the known coordinate
+// reference systems by ellipsoid, prime meridian, unit and projection, using the datum name to choose between
+// systems which only differ by datum.
+func MatchEPSG(d CRSDefinition) (int, error) {
+	if d.EPSG != 0 {
+		return d.EPSG, nil
+	}
+
+	var matches []int
+	named := 0
+
+	for _, code := range epsgCodes() {
+		c, _ := lookupEPSG(code)
+		if !equivalentCRS(d, c) {
+			continue
+		}
+
+		matches = append(matches, code)
+
+		if named == 0 && sameDatumName(d.Datum.Name, c.Datum.Name) {
+			named = code
+		}
+	}
+
+	switch {
+	case named != 0:
+		return named, nil
+	case len(matches) == 1:
+		return matches[0], nil
+	}
+
+	return 0, CRSError{CRS: d.Name}
+}
+
+// sameDatumName reports whether two datum names refer to the same known datum
+func sameDatumName(a, b string) bool {
+	da, ok := lookupDatum(a)
+	if !ok {
+		return false
+	}
+
+	db, ok := lookupDatum(b)
+
+	return ok && da.Name == db.Name
+}
+
+// equivalentCRS reports whether two coordinate reference systems convert coordinates in the same way, ignoring
+// their names and datum shifts
+func equivalentCRS(a, b CRSDefinition) bool {
+	if a.IsGeographic() != b.IsGeographic() || !sameEllipsoid(a.Datum.Ellipsoid, b.Datum.Ellipsoid) ||
+		math.Abs(a.PrimeMeridian-b.PrimeMeridian) > 1e-9 || math.Abs(a.unit().Factor-b.unit().Factor) > 1e-12*b.unit().Factor {
+		return false
+	}
+
+	if a.IsGeographic() {
+		return true
+	}
+
+	ma, pa, ok := describeProjection(a.Projection)
+	if !ok {
+		return false
+	}
+
+	mb, pb, ok := describeProjection(b.Projection)
+	if !ok || ma != mb || len(pa) != len(pb) {
+		return false
+	}
+
+	for x := range pa {
+		tolerance := 1e-9
+		if pa[x].kind == "length" {
+			tolerance = 1e-3
+		}
+
+		if math.Abs(pa[x].value-pb[x].value) > tolerance {
+			return false
+		}
+	}
+
+	return true
+}
+
+// parseCRSName extracts the EPSG code from the common ways of naming a coordinate reference system, such as
+// "EPSG:3006", "urn:ogc:def:crs:EPSG::3006" or "http://www.opengis.net/def/crs/EPSG/0/3006"
+func parseCRSName(name string) (int, error) {
+	n := strings.ToUpper(strings.TrimSpace(name))
+
+	if strings.HasSuffix(n, "CRS84") {
+		return 4326, nil
+	}
+
+	var code string
+
+	switch {
+	case strings.HasPrefix(n, "EPSG:"):
+		code = n[5:]
+	case strings.HasPrefix(n, "URN:OGC:DEF:CRS:EPSG:"):
+		code = n[strings.LastIndex(n, ":")+1:]
+	case strings.Contains(n, "/DEF/CRS/EPSG/"):
+		code = n[strings.LastIndex(n, "/")+1:]
+	default:
+		return 0, CRSError{CRS: name}
+	}
+
+	c, err := strconv.Atoi(code)
+	if err != nil {
+		return 0, CRSError{CRS: name}
+	}
+
+	return c, nil
+}
+
+// Definition returns the definition of the coordinate reference system named by a GeoJSON crs member
+func (c CRS) Definition() (CRSDefinition, error) {
+	if c.Properties.Name == "" {
+		return CRSDefinition{}, CRSError{CRS: c.Properties.Href}
+	}
+
+	code, err := parseCRSName(c.Properties.Name)
+	if err != nil {
+		return CRSDefinition{}, err
+	}
+
+	return LookupEPSG(code)
+}
+
+// Transformer converts coordinates from one coordinate reference system to another, shifting them between
+// datums via WGS84 when the datums differ
+type Transformer struct {
+	From CRSDefinition
+	To   CRSDefinition
+}
+
+// NewTransformer returns a Transformer converting coordinates from one coordinate reference system to another
+func NewTransformer(from, to CRSDefinition) *Transformer {
+	return &Transformer{From: from, To: to}
+}
+
+// Transform converts a single point
+func (t *Transformer) Transform(p Point) (Point, error) {
+	g, err := t.From.ToGeographic(p)
+	if err != nil {
+		return Point{}, err
+	}
+
+	g, err = shiftDatum(g, t.From.Datum, t.To.Datum)
+	if err != nil {
+		return Point{}, err
+	}
+
+	return t.To.FromGeographic(g)
+}
+
+// TransformFeature converts the coordinates of a feature in place
+func (t *Transformer) TransformFeature(f *Feature) error {
+	out, err := f.transformed(t.Transform)
+	if err != nil {
+		return err
+	}
+
+	f.Coordinates = out.Coordinates
+
+	return nil
+}
+
+// TransformCollection converts the coordinates of every feature in a collection in place. The coordinate
+// reference system of the collection is updated to the target system if it has an EPSG code.
+func (t *Transformer) TransformCollection(fc *FeatureCollection) error {
+	transformed := make([]Feature, len(fc.Features))
+
+	for x := range fc.Features {
+		f, err := fc.Features[x].transformed(t.Transform)
+		if err != nil {
+			return err
+		}
+
+		transformed[x] = f
+	}
+
+	fc.Features = transformed
+
+	if t.To.EPSG != 0 {
+		crs := t.To.ToCRS()
+		fc.CoordinateReferenceSystem = &crs
+	} else {
+		fc.CoordinateReferenceSystem = nil
+	}
+
+	return nil
+}
+
+// Transform converts the coordinates of every feature in the collection in place, from one coordinate reference
+// system to another. The coordinate reference system of the collection is set to the target system.
+func (fc *FeatureCollection) Transform(from, to CRS) error {
+	fd, err := from.Definition()
+	if err != nil {
+		return err
+	}
+
+	td, err := to.Definition()
+	if err != nil {
+		return err
+	}
+
+	if err = NewTransformer(fd, td).TransformCollection(fc); err != nil {
+		return err
+	}
+
+	fc.CoordinateReferenceSystem = &to
+
+	return nil
+}
@@ -0,0 +1,1096 @@
+package gegography
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// WKTFormat selects the flavour of WKT written for a coordinate reference system
+type WKTFormat int
+
+const (
+	// WKT1 is the OGC WKT1 format, as written by GDAL
+	WKT1 WKTFormat = iota
+	// WKT1ESRI is the flavour of WKT1 used in the .prj files of ESRI shapefiles
+	WKT1ESRI
+	// WKT2 is the OGC WKT2:2019 format
+	WKT2
+)
+
+// wktNode is a keyword with its bracketed arguments, such as DATUM["WGS_1984",SPHEROID[...]]. Arguments are
+// either *wktNode, wktString (quoted text) or wktWord (numbers and unquoted enumerations such as EAST).
+type wktNode struct {
+	keyword string
+	args    []any
+}
+
+type wktString string
+
+type wktWord string
+
+// child returns the first argument which is a node with one of the keywords, or nil
+func (n *wktNode) child(keywords ...string) *wktNode {
+	for _, a := range n.args {
+		if c, ok := a.(*wktNode); ok && isWKTKeyword(c.keyword, keywords) {
+			return c
+		}
+	}
+
+	return nil
+}
+
+// children returns all arguments which are nodes with one of the keywords
+func (n *wktNode) children(keywords ...string) []*wktNode {
+	out := make([]*wktNode, 0)
+
+	for _, a := range n.args {
+		if c, ok := a.(*wktNode); ok && isWKTKeyword(c.keyword, keywords) {
+			out = append(out, c)
+		}
+	}
+
+	return out
+}
+
+func isWKTKeyword(keyword string, keywords []string) bool {
+	for _, k := range keywords {
+		if strings.EqualFold(keyword, k) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// text returns the argument at index i as text, whether quoted or not
+func (n *wktNode) text(i int) string {
+	if i >= len(n.args) {
+		return ""
+	}
+
+	switch v := n.args[i].(type) {
+	case wktString:
+		return string(v)
+	case wktWord:
+		return string(v)
+	}
+
+	return ""
+}
+
+// number returns the argument at index i as a number
+func (n *wktNode) number(i int) (float64, error) {
+	if i < len(n.args) {
+		if w, ok := n.args[i].(wktWord); ok {
+			if v, err := strconv.ParseFloat(string(w), 64); err == nil {
+				return v, nil
+			}
+		}
+	}
+
+	return 0, GeoFormatError{Msg: fmt.Sprintf("invalid CRS WKT - %s is missing a number", n.keyword)}
+}
+
+type wktParser struct {
+	src []rune
+	pos int
+}
+
+func (p *wktParser) skipSpace() {
+	for p.pos < len(p.src) && unicode.IsSpace(p.src[p.pos]) {
+		p.pos++
+	}
+}
+
+func (p *wktParser) errorf(format string, args ...any) error {
+	return GeoFormatError{Msg: fmt.Sprintf("invalid CRS WKT - "+format+" at offset %d", append(args, p.pos)...)}
+}
+
+func (p *wktParser) word() string {
+	start := p.pos
+
+	for p.pos < len(p.src) {
+		r := p.src[p.pos]
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_' && r != '.' && r != '+' && r != '-' {
+			break
+		}
+		p.pos++
+	}
+
+	return string(p.src[start:p.pos])
+}
+
+func (p *wktParser) quoted() (string, error) {
+	p.pos++
+	var sb strings.Builder
+
+	for p.pos < len(p.src) {
+		r := p.src[p.pos]
+		p.pos++
+
+		if r == '"' {
+			// a doubled quote is an escaped quote
+			if p.pos < len(p.src) && p.src[p.pos] == '"' {
+				sb.WriteRune('"')
+				p.pos++
+				continue
+			}
+
+			return sb.String(), nil
+		}
+
+		sb.WriteRune(r)
+	}
+
+	return "", p.errorf("unterminated string")
+}
+
+func (p *wktParser) node(keyword string) (*wktNode, error) {
+	n := &wktNode{keyword: keyword}
+
+	open := p.src[p.pos]
+	p.pos++
+
+	for {
+		p.skipSpace()
+		if p.pos >= len(p.src) {
+			return nil, p.errorf("unterminated %s", keyword)
+		}
+
+		if r := p.src[p.pos]; (r == ']' || r == ')') && len(n.args) == 0 {
+			p.pos++
+			return n, nil
+		}
+
+		if p.src[p.pos] == '"' {
+			s, err := p.quoted()
+			if err != nil {
+				return nil, err
+			}
+
+			n.args = append(n.args, wktString(s))
+		} else {
+			w := p.word()
+			if w == "" {
+				return nil, p.errorf("unexpected character '%c'", p.src[p.pos])
+			}
+
+			p.skipSpace()
+
+			if p.pos < len(p.src) && (p.src[p.pos] == '[' || p.src[p.pos] == '(') {
+				c, err := p.node(w)
+				if err != nil {
+					return nil, err
+				}
+
+				n.args = append(n.args, c)
+			} else {
+				n.args = append(n.args, wktWord(w))
+			}
+		}
+
+		p.skipSpace()
+		if p.pos >= len(p.src) {
+			return nil, p.errorf("unterminated %s", keyword)
+		}
+
+		switch r := p.src[p.pos]; {
+		case r == ',':
+			p.pos++
+		case (r == ']' && open == '[') || (r == ')' && open == '('):
+			p.pos++
+			return n, nil
+		default:
+			return nil, p.errorf("unexpected character '%c'", r)
+		}
+	}
+}
+
+func parseWKTNode(wkt string) (*wktNode, error) {
+	p := &wktParser{src: []rune(wkt)}
+	p.skipSpace()
+
+	keyword := p.word()
+	p.skipSpace()
+
+	if keyword == "" || p.pos >= len(p.src) || (p.src[p.pos] != '[' && p.src[p.pos] != '(') {
+		return nil, p.errorf("expected a keyword followed by a bracket")
+	}
+
+	n, err := p.node(keyword)
+	if err != nil {
+		return nil, err
+	}
+
+	if p.skipSpace(); p.pos != len(p.src) {
+		return nil, p.errorf("unexpected trailing text")
+	}
+
+	return n, nil
+}
+
+// ParseCRSWKT parses the definition of a coordinate reference system from OGC WKT1, ESRI flavoured WKT1 (as
+// found in .prj files) or WKT2. Geographic, projected, bound and compound coordinate reference systems are
+// supported; of a compound system only the horizontal part is kept.
+func ParseCRSWKT(wkt string) (CRSDefinition, error) {
+	n, err := parseWKTNode(wkt)
+	if err != nil {
+		return CRSDefinition{}, err
+	}
+
+	return parseWKTCRS(n)
+}
+
+var (
+	wktGeographicKeywords = []string{"GEOGCS", "GEOGCRS", "GEODCRS", "GEOGRAPHICCRS", "GEODETICCRS", "BASEGEOGCRS", "BASEGEODCRS"}
+	wktProjectedKeywords  = []string{"PROJCS", "PROJCRS", "PROJECTEDCRS"}
+)
+
+func parseWKTCRS(n *wktNode) (CRSDefinition, error) {
+	switch {
+	case isWKTKeyword(n.keyword, wktGeographicKeywords):
+		return parseWKTGeographic(n)
+	case isWKTKeyword(n.keyword, wktProjectedKeywords):
+		return parseWKTProjected(n)
+	case isWKTKeyword(n.keyword, []string{"BOUNDCRS"}):
+		return parseWKTBound(n)
+	case isWKTKeyword(n.keyword, []string{"COMPD_CS", "COMPOUNDCRS"}):
+		for _, a := range n.args {
+			if c, ok := a.(*wktNode); ok && (isWKTKeyword(c.keyword, wktGeographicKeywords) || isWKTKeyword(c.keyword, wktProjectedKeywords)) {
+				return parseWKTCRS(c)
+			}
+		}
+	}
+
+	return CRSDefinition{}, GeoFormatError{Msg: fmt.Sprintf("invalid CRS WKT - unsupported coordinate reference system %s", n.keyword)}
+}
+
+// parseWKTUnit returns the unit of a node, or the fallback when it has none
+func parseWKTUnit(n *wktNode, fallback CRSUnit, keywords ...string) (CRSUnit, error) {
+	u := n.child(append(keywords, "UNIT")...)
+	if u == nil {
+		return fallback, nil
+	}
+
+	factor, err := u.number(1)
+	if err != nil {
+		return CRSUnit{}, err
+	}
+
+	return CRSUnit{Name: u.text(0), Factor: factor}, nil
+}
+
+// parseWKTAxes returns the axes of a coordinate reference system and the unit of the first axis, if any
+func parseWKTAxes(n *wktNode) ([]CRSAxis, *wktNode) {
+	var axes []CRSAxis
+	var unit *wktNode
+
+	for _, a := range n.children("AXIS") {
+		axes = append(axes, CRSAxis{Name: a.text(0), Direction: strings.ToLower(a.text(1))})
+
+		if unit == nil {
+			unit = a
+		}
+	}
+
+	return axes, unit
+}
+
+// parseWKTID returns the EPSG code of a node, or 0 if it has none
+func parseWKTID(n *wktNode) int {
+	for _, id := range n.children("AUTHORITY", "ID") {
+		if strings.EqualFold(id.text(0), "EPSG") {
+			if code, err := strconv.Atoi(id.text(1)); err == nil {
+				return code
+			}
+		}
+	}
+
+	return 0
+}
+
+// parseWKTArea returns the area of use of a WKT2 coordinate reference system, or nil if it has none
+func parseWKTArea(n *wktNode) *BBox {
+	bn := n.child("BBOX")
+	if u := n.child("USAGE"); u != nil {
+		bn = u.child("BBOX")
+	}
+
+	if bn == nil {
+		return nil
+	}
+
+	// BBOX lists the south, west, north and east bounds
+	var v [4]float64
+	for x := range v {
+		var err error
+		if v[x], err = bn.number(x); err != nil {
+			return nil
+		}
+	}
+
+	return &BBox{MinX: v[1], MinY: v[0], MaxX: v[3], MaxY: v[2]}
+}
+
+func parseWKTGeographic(n *wktNode) (CRSDefinition, error) {
+	d := CRSDefinition{Name: n.text(0), EPSG: parseWKTID(n), AreaOfUse: parseWKTArea(n)}
+
+	var err error
+	var axisUnit *wktNode
+
+	d.Axes, axisUnit = parseWKTAxes(n)
+
+	if d.Unit, err = parseWKTUnit(n, DegreeUnit, "ANGLEUNIT"); err != nil {
+		return d, err
+	}
+
+	if n.child("UNIT", "ANGLEUNIT") == nil && axisUnit != nil {
+		if d.Unit, err = parseWKTUnit(axisUnit, DegreeUnit, "ANGLEUNIT"); err != nil {
+			return d, err
+		}
+	}
+
+	dn := n.child("DATUM", "GEODETICDATUM", "TRF", "ENSEMBLE")
+	if dn == nil {
+		return d, GeoFormatError{Msg: fmt.Sprintf("invalid CRS WKT - %s has no datum", n.keyword)}
+	}
+
+	if d.Datum, err = parseWKTDatum(dn); err != nil {
+		return d, err
+	}
+
+	if pm := n.child("PRIMEM", "PRIMEMERIDIAN"); pm != nil {
+		v, err := pm.number(1)
+		if err != nil {
+			return d, err
+		}
+
+		u, err := parseWKTUnit(pm, d.Unit, "ANGLEUNIT")
+		if err != nil {
+			return d, err
+		}
+
+		d.PrimeMeridian = angleToDegrees(v, u.Factor)
+	}
+
+	return d, nil
+}
+
+func parseWKTDatum(n *wktNode) (Datum, error) {
+	en := n.child("SPHEROID", "ELLIPSOID")
+	if en == nil {
+		return Datum{}, GeoFormatError{Msg: fmt.Sprintf("invalid CRS WKT - %s has no ellipsoid", n.keyword)}
+	}
+
+	a, err := en.number(1)
+	if err != nil {
+		return Datum{}, err
+	}
+
+	invF, err := en.number(2)
+	if err != nil {
+		return Datum{}, err
+	}
+
+	u, err := parseWKTUnit(en, MetreUnit, "LENGTHUNIT")
+	if err != nil {
+		return Datum{}, err
+	}
+
+	e := Ellipsoid{Name: en.text(0), A: a * u.Factor}
+	if invF != 0 {
+		e.F = 1 / invF
+	}
+
+	var h *Helmert
+
+	if tw := n.child("TOWGS84"); tw != nil {
+		var v [7]float64
+
+		for x := range v {
+			// three parameter TOWGS84 nodes leave the rotations and scale at zero
+			if x < len(tw.args) {
+				if v[x], err = tw.number(x); err != nil {
+					return Datum{}, err
+				}
+			}
+		}
+
+		h = &Helmert{Dx: v[0], Dy: v[1], Dz: v[2], Rx: v[3], Ry: v[4], Rz: v[5], Scale: v[6]}
+	}
+
+	return resolveDatum(n.text(0), e, h), nil
+}
+
+// knownDatums lists the normalised names by which the known datums appear in OGC, ESRI and WKT2 definitions
+var knownDatums = []struct {
+	datum Datum
+	names []string
+}{
+	{WGS84Datum, []string{"wgs84", "wgs1984", "worldgeodeticsystem1984", "worldgeodeticsystem1984ensemble"}},
+	{ETRS89Datum, []string{"etrs89", "etrs1989", "europeanterrestrialreferencesystem1989", "europeanterrestrialreferencesystem1989ensemble"}},
+	{SWEREF99Datum, []string{"sweref99", "swedishreferenceframe1999"}},
+	{NAD83Datum, []string{"nad83", "northamerican1983", "northamericandatum1983"}},
+	{GDA94Datum, []string{"gda94", "gda1994", "geocentricdatumofaustralia1994"}},
+	{RGF93Datum, []string{"rgf93", "rgf1993", "rgf93v1", "reseaugeodesiquefrancais1993", "reseaugeodesiquefrancais1993v1"}},
+	{RT90Datum, []string{"rt90", "rt1990", "riketskoordinatsystem1990"}},
+	{ED50Datum, []string{"ed50", "european1950", "europeandatum1950"}},
+	{NAD27Datum, []string{"nad27", "northamerican1927", "northamericandatum1927"}},
+	{OSGB36Datum, []string{"osgb36", "osgb1936"}},
+	{DHDNDatum, []string{"dhdn", "deutscheshauptdreiecksnetz"}},
+}
+
+// lookupDatum returns the known datum with a name, in any of the forms used in WKT
+func lookupDatum(name string) (Datum, bool) {
+	n := normaliseWKTName(name)
+
+	for _, k := range knownDatums {
+		if slices.Contains(k.names, n) {
+			return k.datum, true
+		}
+	}
+
+	return Datum{}, false
+}
+
+// normaliseWKTName lowercases a name and drops everything but letters and digits, as well as the D_ and GCS_
+// prefixes of ESRI names
+func normaliseWKTName(name string) string {
+	n := strings.ToLower(name)
+	n = strings.TrimPrefix(n, "d_")
+	n = strings.TrimPrefix(n, "gcs_")
+
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return r
+		}
+
+		return -1
+	}, n)
+}
+
+func sameEllipsoid(a, b Ellipsoid) bool {
+	return math.Abs(a.A-b.A) < 1e-3 && math.Abs(a.F-b.F) < 1e-12
+}
+
+// resolveDatum returns the datum named in a definition. Known datums on the expected ellipsoid keep their Helmert
+// parameters to WGS84 when the definition has none.
+func resolveDatum(name string, e Ellipsoid, h *Helmert) Datum {
+	d := Datum{Name: name, Ellipsoid: e, ToWGS84: h}
+
+	if known, ok := lookupDatum(name); ok && sameEllipsoid(known.Ellipsoid, e) {
+		d.Ellipsoid = known.Ellipsoid
+		d.GridShift = known.GridShift
+
+		if h == nil {
+			d.ToWGS84 = known.ToWGS84
+		}
+	}
+
+	return withRegisteredGridShift(d)
+}
+
+func parseWKTProjected(n *wktNode) (CRSDefinition, error) {
+	bn := n.child(wktGeographicKeywords...)
+	if bn == nil {
+		return CRSDefinition{}, GeoFormatError{Msg: fmt.Sprintf("invalid CRS WKT - %s has no geographic coordinate reference system", n.keyword)}
+	}
+
+	base, err := parseWKTGeographic(bn)
+	if err != nil {
+		return CRSDefinition{}, err
+	}
+
+	d := CRSDefinition{Name: n.text(0), EPSG: parseWKTID(n), Datum: base.Datum, PrimeMeridian: base.PrimeMeridian, AreaOfUse: parseWKTArea(n)}
+
+	var axisUnit *wktNode
+	d.Axes, axisUnit = parseWKTAxes(n)
+
+	if d.Unit, err = parseWKTUnit(n, MetreUnit, "LENGTHUNIT"); err != nil {
+		return d, err
+	}
+
+	if n.child("UNIT", "LENGTHUNIT") == nil && axisUnit != nil {
+		if d.Unit, err = parseWKTUnit(axisUnit, MetreUnit, "LENGTHUNIT"); err != nil {
+			return d, err
+		}
+	}
+
+	// WKT1 has the method and parameters directly in PROJCS, WKT2 in a CONVERSION
+	pn := n
+	if c := n.child("CONVERSION", "DERIVINGCONVERSION"); c != nil {
+		pn = c
+	}
+
+	mn := pn.child("PROJECTION", "METHOD", "PROJECTIONMETHOD")
+	if mn == nil {
+		return d, GeoFormatError{Msg: fmt.Sprintf("invalid CRS WKT - %s has no projection", n.keyword)}
+	}
+
+	d.ProjectionMethod = mn.text(0)
+
+	var pp projectionParameters
+
+	for _, p := range pn.children("PARAMETER") {
+		v, err := p.number(1)
+		if err != nil {
+			return d, err
+		}
+
+		d.ProjectionParameters = append(d.ProjectionParameters, CRSParameter{Name: p.text(0), Value: v})
+
+		key, ok := projectionParameterAliases[normaliseWKTName(p.text(0))]
+		if !ok {
+			continue
+		}
+
+		switch key {
+		case "lat0", "lon0", "sp1", "sp2":
+			u, err := parseWKTUnit(p, base.Unit, "ANGLEUNIT")
+			if err != nil {
+				return d, err
+			}
+
+			v = angleToDegrees(v, u.Factor)
+		case "fe", "fn":
+			u, err := parseWKTUnit(p, d.Unit, "LENGTHUNIT")
+			if err != nil {
+				return d, err
+			}
+
+			v *= u.Factor
+		}
+
+		pp.set(key, v)
+	}
+
+	d.Projection = pp.projection(d.ProjectionMethod, d.Datum.Ellipsoid)
+
+	return d, nil
+}
+
+func parseWKTBound(n *wktNode) (CRSDefinition, error) {
+	src := n.child("SOURCECRS")
+	if src == nil || len(src.args) == 0 {
+		return CRSDefinition{}, GeoFormatError{Msg: "invalid CRS WKT - BOUNDCRS has no source"}
+	}
+
+	sn, ok := src.args[0].(*wktNode)
+	if !ok {
+		return CRSDefinition{}, GeoFormatError{Msg: "invalid CRS WKT - BOUNDCRS has no source"}
+	}
+
+	d, err := parseWKTCRS(sn)
+	if err != nil {
+		return d, err
+	}
+
+	tn := n.child("ABRIDGEDTRANSFORMATION")
+	if tn == nil {
+		return d, nil
+	}
+
+	method := ""
+	if mn := tn.child("METHOD"); mn != nil {
+		method = normaliseWKTName(mn.text(0))
+	}
+
+	if !strings.Contains(method, "positionvector") && !strings.Contains(method, "coordinateframe") && !strings.Contains(method, "geocentrictranslation") {
+		return d, nil
+	}
+
+	var h Helmert
+
+	for _, p := range tn.children("PARAMETER") {
+		v, err := p.number(1)
+		if err != nil {
+			return d, err
+		}
+
+		name := normaliseWKTName(p.text(0))
+
+		// without units, translations are in metres, rotations in arc-seconds and the scale in parts per million
+		fallback := CRSUnit{Factor: 1}
+		switch {
+		case strings.HasSuffix(name, "rotation"):
+			fallback.Factor = arcSecond
+		case name == "scaledifference":
+			fallback.Factor = 1e-6
+		}
+
+		u, err := parseWKTUnit(p, fallback, "LENGTHUNIT", "ANGLEUNIT", "SCALEUNIT")
+		if err != nil {
+			return d, err
+		}
+
+		switch name {
+		case "xaxistranslation":
+			h.Dx = v * u.Factor
+		case "yaxistranslation":
+			h.Dy = v * u.Factor
+		case "zaxistranslation":
+			h.Dz = v * u.Factor
+		case "xaxisrotation":
+			h.Rx = convertUnit(v, u.Factor, arcSecond)
+		case "yaxisrotation":
+			h.Ry = convertUnit(v, u.Factor, arcSecond)
+		case "zaxisrotation":
+			h.Rz = convertUnit(v, u.Factor, arcSecond)
+		case "scaledifference":
+			h.Scale = convertUnit(v, u.Factor, 1e-6)
+		}
+	}
+
+	if strings.Contains(method, "coordinateframe") {
+		h.Rx, h.Ry, h.Rz = -h.Rx, -h.Ry, -h.Rz
+	}
+
+	d.Datum.ToWGS84 = &h
+
+	return d, nil
+}
+
+// convertUnit converts a value from one unit to another by their factors, leaving it as is when the units only
+// differ by the rounding of their factors
+func convertUnit(v, from, to float64) float64 {
+	if math.Abs(from-to) <= 1e-12*math.Abs(to) {
+		return v
+	}
+
+	return v * from / to
+}
+
+// projectionParameters holds the parameters of the supported projections, with angles in degrees and lengths in
+// metres
+type projectionParameters struct {
+	lat0, lon0, k0, fe, fn, sp1, sp2 float64
+	hasK0, hasSP1, hasSP2            bool
+}
+
+// projectionParameterAliases maps the normalised OGC, ESRI and WKT2 parameter names to projectionParameters
+var projectionParameterAliases = map[string]string{
+	"latitudeoforigin": "lat0", "latitudeofnaturalorigin": "lat0", "latitudeoffalseorigin": "lat0",
+	"latitudeofcenter": "lat0", "latitudeofcentre": "lat0",
+	"centralmeridian": "lon0", "longitudeofnaturalorigin": "lon0", "longitudeoffalseorigin": "lon0",
+	"longitudeofcenter": "lon0", "longitudeofcentre": "lon0", "longitudeoforigin": "lon0",
+	"scalefactor": "k0", "scalefactoratnaturalorigin": "k0",
+	"falseeasting": "fe", "eastingatfalseorigin": "fe",
+	"falsenorthing": "fn", "northingatfalseorigin": "fn",
+	"standardparallel1": "sp1", "latitudeof1ststandardparallel": "sp1",
+	"standardparallel2": "sp2", "latitudeof2ndstandardparallel": "sp2",
+}
+
+func (pp *projectionParameters) set(key string, v float64) {
+	switch key {
+	case "lat0":
+		pp.lat0 = v
+	case "lon0":
+		pp.lon0 = v
+	case "k0":
+		pp.k0, pp.hasK0 = v, true
+	case "fe":
+		pp.fe = v
+	case "fn":
+		pp.fn = v
+	case "sp1":
+		pp.sp1, pp.hasSP1 = v, true
+	case "sp2":
+		pp.sp2, pp.hasSP2 = v, true
+	}
+}
+
+// projection returns the projection for a method named in OGC, ESRI or WKT2 form, or nil if it is not supported
+func (pp projectionParameters) projection(method string, e Ellipsoid) Projection {
+	k0 := pp.k0
+	if !pp.hasK0 {
+		k0 = 1
+	}
+
+	switch normaliseWKTName(method) {
+	case "transversemercator", "gausskruger":
+		return TransverseMercator{Ellipsoid: e, LatitudeOfOrigin: pp.lat0, CentralMeridian: pp.lon0, ScaleFactor: k0, FalseEasting: pp.fe, FalseNorthing: pp.fn}
+	case "lambertconformalconic2sp", "lambertconicconformal2sp", "lambertconformalconic1sp", "lambertconicconformal1sp", "lambertconformalconic":
+		l := LambertConformalConic{Ellipsoid: e, LatitudeOfOrigin: pp.lat0, CentralMeridian: pp.lon0, StandardParallel1: pp.sp1, StandardParallel2: pp.sp2, ScaleFactor: k0, FalseEasting: pp.fe, FalseNorthing: pp.fn}
+
+		// one standard parallel variants have their scale factor at the latitude of origin
+		if !pp.hasSP1 {
+			l.StandardParallel1 = pp.lat0
+		}
+		if !pp.hasSP2 {
+			l.StandardParallel2 = l.StandardParallel1
+		}
+
+		return l
+	case "albersconicequalarea", "albersequalarea", "albers":
+		return AlbersEqualArea{Ellipsoid: e, LatitudeOfOrigin: pp.lat0, CentralMeridian: pp.lon0, StandardParallel1: pp.sp1, StandardParallel2: pp.sp2, FalseEasting: pp.fe, FalseNorthing: pp.fn}
+	case "popularvisualisationpseudomercator", "mercatorauxiliarysphere":
+		if pp.lon0 != 0 || pp.fe != 0 || pp.fn != 0 {
+			return nil
+		}
+
+		return WebMercator{Ellipsoid: e}
+	}
+
+	return nil
+}
+
+// wktMethod is a supported projection method with its names in the different WKT flavours
+type wktMethod struct {
+	ogc, esri, wkt2 string
+	epsg            int
+}
+
+var (
+	transverseMercatorMethod = wktMethod{"Transverse_Mercator", "Transverse_Mercator", "Transverse Mercator", 9807}
+	lcc1SPMethod             = wktMethod{"Lambert_Conformal_Conic_1SP", "Lambert_Conformal_Conic", "Lambert Conic Conformal (1SP)", 9801}
+	lcc2SPMethod             = wktMethod{"Lambert_Conformal_Conic_2SP", "Lambert_Conformal_Conic", "Lambert Conic Conformal (2SP)", 9802}
+	albersMethod             = wktMethod{"Albers_Conic_Equal_Area", "Albers", "Albers Equal Area", 9822}
+	pseudoMercatorMethod     = wktMethod{"Popular_Visualisation_Pseudo_Mercator", "Mercator_Auxiliary_Sphere", "Popular Visualisation Pseudo Mercator", 1024}
+)
+
+// wktParameter is a projection parameter with its names in the different WKT flavours. Angular parameters are
+// in degrees and linear ones in metres.
+type wktParameter struct {
+	ogc, esri, wkt2 string
+	value           float64
+	kind            string
+}
+
+func angleParameter(ogc, esri, wkt2 string, v float64) wktParameter {
+	return wktParameter{ogc, esri, wkt2, v, "angle"}
+}
+
+func lengthParameter(ogc, esri, wkt2 string, v float64) wktParameter {
+	return wktParameter{ogc, esri, wkt2, v, "length"}
+}
+
+func scaleParameter(ogc, esri, wkt2 string, v float64) wktParameter {
+	return wktParameter{ogc, esri, wkt2, v, "scale"}
+}
+
+// describeProjection returns the method and parameters of a supported projection, or false for other projections
+func describeProjection(p Projection) (wktMethod, []wktParameter, bool) {
+	switch v := p.(type) {
+	case TransverseMercator:
+		return transverseMercatorMethod, []wktParameter{
+			angleParameter("latitude_of_origin", "Latitude_Of_Origin", "Latitude of natural origin", v.LatitudeOfOrigin),
+			angleParameter("central_meridian", "Central_Meridian", "Longitude of natural origin", v.CentralMeridian),
+			scaleParameter("scale_factor", "Scale_Factor", "Scale factor at natural origin", v.ScaleFactor),
+			lengthParameter("false_easting", "False_Easting", "False easting", v.FalseEasting),
+			lengthParameter("false_northing", "False_Northing", "False northing", v.FalseNorthing),
+		}, true
+	case LambertConformalConic:
+		k0 := v.ScaleFactor
+		if k0 == 0 {
+			k0 = 1
+		}
+
+		if v.StandardParallel1 == v.StandardParallel2 && v.StandardParallel1 == v.LatitudeOfOrigin {
+			return lcc1SPMethod, []wktParameter{
+				angleParameter("latitude_of_origin", "Latitude_Of_Origin", "Latitude of natural origin", v.LatitudeOfOrigin),
+				angleParameter("central_meridian", "Central_Meridian", "Longitude of natural origin", v.CentralMeridian),
+				scaleParameter("scale_factor", "Scale_Factor", "Scale factor at natural origin", k0),
+				lengthParameter("false_easting", "False_Easting", "False easting", v.FalseEasting),
+				lengthParameter("false_northing", "False_Northing", "False northing", v.FalseNorthing),
+			}, true
+		}
+
+		params := []wktParameter{
+			angleParameter("standard_parallel_1", "Standard_Parallel_1", "Latitude of 1st standard parallel", v.StandardParallel1),
+			angleParameter("standard_parallel_2", "Standard_Parallel_2", "Latitude of 2nd standard parallel", v.StandardParallel2),
+			angleParameter("latitude_of_origin", "Latitude_Of_Origin", "Latitude of false origin", v.LatitudeOfOrigin),
+			angleParameter("central_meridian", "Central_Meridian", "Longitude of false origin", v.CentralMeridian),
+			lengthParameter("false_easting", "False_Easting", "Easting at false origin", v.FalseEasting),
+			lengthParameter("false_northing", "False_Northing", "Northing at false origin", v.FalseNorthing),
+		}
+
+		if k0 != 1 {
+			params = append(params, scaleParameter("scale_factor", "Scale_Factor", "Scale factor at natural origin", k0))
+		}
+
+		return lcc2SPMethod, params, true
+	case AlbersEqualArea:
+		return albersMethod, []wktParameter{
+			angleParameter("standard_parallel_1", "Standard_Parallel_1", "Latitude of 1st standard parallel", v.StandardParallel1),
+			angleParameter("standard_parallel_2", "Standard_Parallel_2", "Latitude of 2nd standard parallel", v.StandardParallel2),
+			angleParameter("latitude_of_center", "Latitude_Of_Origin", "Latitude of false origin", v.LatitudeOfOrigin),
+			angleParameter("longitude_of_center", "Central_Meridian", "Longitude of false origin", v.CentralMeridian),
+			lengthParameter("false_easting", "False_Easting", "Easting at false origin", v.FalseEasting),
+			lengthParameter("false_northing", "False_Northing", "Northing at false origin", v.FalseNorthing),
+		}, true
+	case WebMercator:
+		return pseudoMercatorMethod, []wktParameter{
+			angleParameter("latitude_of_origin", "Standard_Parallel_1", "Latitude of natural origin", 0),
+			angleParameter("central_meridian", "Central_Meridian", "Longitude of natural origin", 0),
+			lengthParameter("false_easting", "False_Easting", "False easting", 0),
+			lengthParameter("false_northing", "False_Northing", "False northing", 0),
+		}, true
+	}
+
+	return wktMethod{}, nil, false
+}
+
+// wktNumber formats a number for WKT, rounded to 15 significant digits to hide floating point noise
+func wktNumber(v float64) string {
+	return strconv.FormatFloat(v, 'g', 15, 64)
+}
+
+func wktQuote(s string) string {
+	return `"` + strings.ReplaceAll(s, `"`, `""`) + `"`
+}
+
+// esriName converts a name to the underscored form used by ESRI
+func esriName(s string) string {
+	if s == "WGS 84" {
+		return "WGS_1984"
+	}
+
+	return strings.Map(func(r rune) rune {
+		if r == ' ' || r == '-' || r == '/' {
+			return '_'
+		}
+
+		return r
+	}, s)
+}
+
+func (u CRSUnit) toWKT(keyword string) string {
+	return fmt.Sprintf("%s[%s,%s]", keyword, wktQuote(u.Name), wktNumber(u.Factor))
+}
+
+// ToWKT writes the definition of the coordinate reference system as WKT in the requested format. Projections
+// which are not supported are written as they were parsed.
+func (d CRSDefinition) ToWKT(format WKTFormat) string {
+	switch format {
+	case WKT1ESRI:
+		return d.toWKT1(true)
+	case WKT2:
+		return d.toWKT2()
+	default:
+		return d.toWKT1(false)
+	}
+}
+
+func (d CRSDefinition) toWKT1(esri bool) string {
+	datum := d.Datum.Name
+	ellipsoid := d.Datum.Ellipsoid.Name
+	geogName := d.Datum.Name
+	if d.IsGeographic() {
+		geogName = d.Name
+	}
+
+	if esri {
+		datum = "D_" + esriName(strings.TrimPrefix(datum, "D_"))
+		ellipsoid = esriName(ellipsoid)
+		geogName = "GCS_" + esriName(strings.TrimPrefix(geogName, "GCS_"))
+	}
+
+	angular := DegreeUnit
+	if d.IsGeographic() {
+		angular = d.unit()
+	}
+
+	var sb strings.Builder
+
+	fmt.Fprintf(&sb, "GEOGCS[%s,DATUM[%s,SPHEROID[%s,%s,%s]", wktQuote(geogName), wktQuote(datum),
+		wktQuote(ellipsoid), wktNumber(d.Datum.Ellipsoid.A), wktNumber(inverseFlattening(d.Datum.Ellipsoid)))
+
+	if h := d.Datum.ToWGS84; h != nil && !esri {
+		fmt.Fprintf(&sb, ",TOWGS84[%s,%s,%s,%s,%s,%s,%s]", wktNumber(h.Dx), wktNumber(h.Dy), wktNumber(h.Dz),
+			wktNumber(h.Rx), wktNumber(h.Ry), wktNumber(h.Rz), wktNumber(h.Scale))
+	}
+
+	fmt.Fprintf(&sb, "],PRIMEM[\"Greenwich\",%s],%s", wktNumber(degreesToAngle(d.PrimeMeridian, angular.Factor)), angular.toWKT("UNIT"))
+
+	if d.IsGeographic() {
+		if !esri {
+			d.writeWKT1Axes(&sb)
+			if d.EPSG != 0 {
+				fmt.Fprintf(&sb, `,AUTHORITY["EPSG","%d"]`, d.EPSG)
+			}
+		}
+		sb.WriteString("]")
+
+		return sb.String()
+	}
+
+	sb.WriteString("]")
+	geog := sb.String()
+
+	sb.Reset()
+
+	name := d.Name
+	if esri {
+		name = esriName(name)
+	}
+
+	unit := d.unit()
+	fmt.Fprintf(&sb, "PROJCS[%s,%s,", wktQuote(name), geog)
+
+	if method, params, ok := describeProjection(d.Projection); ok {
+		if esri {
+			fmt.Fprintf(&sb, "PROJECTION[%s]", wktQuote(method.esri))
+		} else {
+			fmt.Fprintf(&sb, "PROJECTION[%s]", wktQuote(method.ogc))
+		}
+
+		for _, p := range params {
+			v := p.value
+			if p.kind == "length" {
+				v /= unit.Factor
+			}
+
+			pn := p.ogc
+			if esri {
+				pn = p.esri
+			}
+
+			fmt.Fprintf(&sb, ",PARAMETER[%s,%s]", wktQuote(pn), wktNumber(v))
+		}
+	} else {
+		fmt.Fprintf(&sb, "PROJECTION[%s]", wktQuote(d.ProjectionMethod))
+
+		for _, p := range d.ProjectionParameters {
+			fmt.Fprintf(&sb, ",PARAMETER[%s,%s]", wktQuote(p.Name), wktNumber(p.Value))
+		}
+	}
+
+	fmt.Fprintf(&sb, ",%s", unit.toWKT("UNIT"))
+
+	if !esri {
+		d.writeWKT1Axes(&sb)
+		if d.EPSG != 0 {
+			fmt.Fprintf(&sb, `,AUTHORITY["EPSG","%d"]`, d.EPSG)
+		}
+	}
+
+	sb.WriteString("]")
+
+	return sb.String()
+}
+
+// wktAxes returns the axes of the coordinate reference system, defaulting to east/north
+func (d CRSDefinition) wktAxes() []CRSAxis {
+	switch {
+	case len(d.Axes) > 0:
+		return d.Axes
+	case d.IsGeographic():
+		return []CRSAxis{{Name: "Longitude", Direction: "east"}, {Name: "Latitude", Direction: "north"}}
+	default:
+		return []CRSAxis{{Name: "Easting", Direction: "east"}, {Name: "Northing", Direction: "north"}}
+	}
+}
+
+func (d CRSDefinition) writeWKT1Axes(sb *strings.Builder) {
+	for _, a := range d.wktAxes() {
+		fmt.Fprintf(sb, ",AXIS[%s,%s]", wktQuote(a.Name), strings.ToUpper(a.Direction))
+	}
+}
+
+func (d CRSDefinition) writeWKT2Axes(sb *strings.Builder, unit string) {
+	for x, a := range d.wktAxes() {
+		fmt.Fprintf(sb, ",AXIS[%s,%s,ORDER[%d],%s]", wktQuote(a.Name), strings.ToLower(a.Direction), x+1, unit)
+	}
+}
+
+func inverseFlattening(e Ellipsoid) float64 {
+	if e.F == 0 {
+		return 0
+	}
+
+	return 1 / e.F
+}
+
+func (d CRSDefinition) toWKT2() string {
+	var sb strings.Builder
+
+	angular := DegreeUnit
+	if d.IsGeographic() {
+		angular = d.unit()
+	}
+
+	geogName := d.Datum.Name
+	keyword := "BASEGEOGCRS"
+	if d.IsGeographic() {
+		geogName = d.Name
+		keyword = "GEOGCRS"
+	}
+
+	fmt.Fprintf(&sb, "%s[%s,DATUM[%s,ELLIPSOID[%s,%s,%s,%s]],PRIMEM[\"Greenwich\",%s,%s]", keyword, wktQuote(geogName),
+		wktQuote(d.Datum.Name), wktQuote(d.Datum.Ellipsoid.Name), wktNumber(d.Datum.Ellipsoid.A),
+		wktNumber(inverseFlattening(d.Datum.Ellipsoid)), MetreUnit.toWKT("LENGTHUNIT"),
+		wktNumber(degreesToAngle(d.PrimeMeridian, angular.Factor)), angular.toWKT("ANGLEUNIT"))
+
+	if d.IsGeographic() {
+		unit := angular.toWKT("ANGLEUNIT")
+		sb.WriteString(",CS[ellipsoidal,2]")
+		d.writeWKT2Axes(&sb, unit)
+	} else {
+		base := sb.String() + "]"
+		sb.Reset()
+
+		fmt.Fprintf(&sb, "PROJCRS[%s,%s,CONVERSION[%s,", wktQuote(d.Name), base, wktQuote(d.Name))
+
+		if method, params, ok := describeProjection(d.Projection); ok {
+			fmt.Fprintf(&sb, "METHOD[%s,ID[\"EPSG\",%d]]", wktQuote(method.wkt2), method.epsg)
+
+			for _, p := range params {
+				var unit string
+
+				switch p.kind {
+				case "angle":
+					unit = DegreeUnit.toWKT("ANGLEUNIT")
+				case "length":
+					unit = MetreUnit.toWKT("LENGTHUNIT")
+				default:
+					unit = `SCALEUNIT["unity",1]`
+				}
+
+				fmt.Fprintf(&sb, ",PARAMETER[%s,%s,%s]", wktQuote(p.wkt2), wktNumber(p.value), unit)
+			}
+		} else {
+			fmt.Fprintf(&sb, "METHOD[%s]", wktQuote(d.ProjectionMethod))
+
+			for _, p := range d.ProjectionParameters {
+				fmt.Fprintf(&sb, ",PARAMETER[%s,%s]", wktQuote(p.Name), wktNumber(p.Value))
+			}
+		}
+
+		unit := d.unit().toWKT("LENGTHUNIT")
+		sb.WriteString("],CS[Cartesian,2]")
+		d.writeWKT2Axes(&sb, unit)
+	}
+
+	if a := d.AreaOfUse; a != nil {
+		fmt.Fprintf(&sb, `,USAGE[SCOPE["Not known."],BBOX[%s,%s,%s,%s]]`, wktNumber(a.MinY), wktNumber(a.MinX), wktNumber(a.MaxY), wktNumber(a.MaxX))
+	}
+
+	if d.EPSG != 0 {
+		fmt.Fprintf(&sb, `,ID["EPSG",%d]`, d.EPSG)
+	}
+
+	sb.WriteString("]")
+
+	h := d.Datum.ToWGS84
+	if h == nil {
+		return sb.String()
+	}
+
+	// Helmert parameters are not part of a WKT2 coordinate reference system, but of a bound one
+	wgs84 := CRSDefinition{Name: "WGS 84", EPSG: 4326, Datum: WGS84Datum}
+	arcSec := CRSUnit{Name: "arc-second", Factor: arcSecond}
+
+	return fmt.Sprintf("BOUNDCRS[SOURCECRS[%s],TARGETCRS[%s],ABRIDGEDTRANSFORMATION[%s,"+
+		"METHOD[\"Position Vector transformation (geog2D domain)\",ID[\"EPSG\",9606]],"+
+		"PARAMETER[\"X-axis translation\",%s,%s],PARAMETER[\"Y-axis translation\",%s,%s],PARAMETER[\"Z-axis translation\",%s,%s],"+
+		"PARAMETER[\"X-axis rotation\",%s,%s],PARAMETER[\"Y-axis rotation\",%s,%s],PARAMETER[\"Z-axis rotation\",%s,%s],"+
+		"PARAMETER[\"Scale difference\",%s,SCALEUNIT[\"parts per million\",1e-06]]]]",
+		sb.String(), wgs84.toWKT2(), wktQuote(d.Datum.Name+" to WGS 84"),
+		wktNumber(h.Dx), MetreUnit.toWKT("LENGTHUNIT"), wktNumber(h.Dy), MetreUnit.toWKT("LENGTHUNIT"), wktNumber(h.Dz), MetreUnit.toWKT("LENGTHUNIT"),
+		wktNumber(h.Rx), arcSec.toWKT("ANGLEUNIT"), wktNumber(h.Ry), arcSec.toWKT("ANGLEUNIT"), wktNumber(h.Rz), arcSec.toWKT("ANGLEUNIT"),
+		wktNumber(h.Scale))
+}
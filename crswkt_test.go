@@ -0,0 +1,180 @@
+package gegography
+
+import (
+	"math"
+	"os"
+	"testing"
+)
+
+const sweref99TMWKT1 = `PROJCS["SWEREF99 TM",
+    GEOGCS["SWEREF99",
+        DATUM["SWEREF99",
+            SPHEROID["GRS 1980",6378137,298.257222101,AUTHORITY["EPSG","7019"]],
+            TOWGS84[0,0,0,0,0,0,0],
+            AUTHORITY["EPSG","6619"]],
+        PRIMEM["Greenwich",0,AUTHORITY["EPSG","8901"]],
+        UNIT["degree",0.0174532925199433,AUTHORITY["EPSG","9122"]],
+        AUTHORITY["EPSG","4619"]],
+    PROJECTION["Transverse_Mercator"],
+    PARAMETER["latitude_of_origin",0],
+    PARAMETER["central_meridian",15],
+    PARAMETER["scale_factor",0.9996],
+    PARAMETER["false_easting",500000],
+    PARAMETER["false_northing",0],
+    UNIT["metre",1,AUTHORITY["EPSG","9001"]],
+    AUTHORITY["EPSG","3006"]]`
+
+const utm33WKT2 = `PROJCRS["WGS 84 / UTM zone 33N",
+    BASEGEOGCRS["WGS 84",
+        ENSEMBLE["World Geodetic System 1984 ensemble",
+            MEMBER["World Geodetic System 1984 (G2139)"],
+            ELLIPSOID["WGS 84",6378137,298.257223563,LENGTHUNIT["metre",1]],
+            ENSEMBLEACCURACY[2.0]],
+        PRIMEM["Greenwich",0,ANGLEUNIT["degree",0.0174532925199433]]],
+    CONVERSION["UTM zone 33N",
+        METHOD["Transverse Mercator",ID["EPSG",9807]],
+        PARAMETER["Latitude of natural origin",0,ANGLEUNIT["degree",0.0174532925199433]],
+        PARAMETER["Longitude of natural origin",15,ANGLEUNIT["degree",0.0174532925199433]],
+        PARAMETER["Scale factor at natural origin",0.9996,SCALEUNIT["unity",1]],
+        PARAMETER["False easting",500000,LENGTHUNIT["metre",1]],
+        PARAMETER["False northing",0,LENGTHUNIT["metre",1]]],
+    CS[Cartesian,2],
+        AXIS["(E)",east,ORDER[1],LENGTHUNIT["metre",1]],
+        AXIS["(N)",north,ORDER[2],LENGTHUNIT["metre",1]],
+    USAGE[SCOPE["Navigation and medium accuracy spatial referencing."],BBOX[0,12,84,18]]]`
+
+const lambert93ESRI = `PROJCS["RGF_1993_Lambert_93",GEOGCS["GCS_RGF_1993",DATUM["D_RGF_1993",SPHEROID["GRS_1980",6378137.0,298.257222101]],PRIMEM["Greenwich",0.0],UNIT["Degree",0.0174532925199433]],PROJECTION["Lambert_Conformal_Conic"],PARAMETER["False_Easting",700000.0],PARAMETER["False_Northing",6600000.0],PARAMETER["Central_Meridian",3.0],PARAMETER["Standard_Parallel_1",49.0],PARAMETER["Standard_Parallel_2",44.0],PARAMETER["Latitude_Of_Origin",46.5],UNIT["Meter",1.0]]`
+
+func TestParseCRSWKT(t *testing.T) {
+	prj, err := os.ReadFile("test_data/test_shapefile.prj")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		wkt        string
+		epsg       int
+		geographic bool
+		ellipsoid  Ellipsoid
+	}{
+		{"OGC WKT1", sweref99TMWKT1, 3006, false, GRS80Ellipsoid},
+		{"WKT2", utm33WKT2, 32633, false, WGS84Ellipsoid},
+		{"ESRI projected", lambert93ESRI, 2154, false, GRS80Ellipsoid},
+		{"ESRI geographic", string(prj), 4326, true, WGS84Ellipsoid},
+	}
+
+	for _, test := range tests {
+		d, err := ParseCRSWKT(test.wkt)
+		if err != nil {
+			t.Errorf("%s: %v", test.name, err)
+			continue
+		}
+
+		if d.IsGeographic() != test.geographic || !sameEllipsoid(d.Datum.Ellipsoid, test.ellipsoid) {
+			t.Errorf("%s: unexpected definition %+v", test.name, d)
+		}
+
+		code, err := MatchEPSG(d)
+		if err != nil || code != test.epsg {
+			t.Errorf("%s: MatchEPSG, want %d got %d (%v)", test.name, test.epsg, code, err)
+		}
+
+		// the parsed definition must convert coordinates like the one in the EPSG table
+		want, _ := LookupEPSG(test.epsg)
+		g := Point{X: 15.5, Y: 58.2}
+		if test.epsg == 2154 {
+			g = Point{X: 2.35, Y: 48.85}
+		}
+
+		a, errA := d.FromGeographic(g)
+		b, errB := want.FromGeographic(g)
+		if errA != nil || errB != nil || !closeTo(a, b, 1e-6) {
+			t.Errorf("%s: FromGeographic, want %v got %v", test.name, b, a)
+		}
+	}
+}
+
+func TestParseCRSWKTUnits(t *testing.T) {
+	// NAD83 / Texas South Central in US survey feet, with the false easting in feet
+	wkt := `PROJCS["NAD83 / Texas South Central (ftUS)",GEOGCS["NAD83",DATUM["North_American_Datum_1983",SPHEROID["GRS 1980",6378137,298.257222101]],PRIMEM["Greenwich",0],UNIT["degree",0.0174532925199433]],PROJECTION["Lambert_Conformal_Conic_2SP"],PARAMETER["standard_parallel_1",30.2833333333333],PARAMETER["standard_parallel_2",28.3833333333333],PARAMETER["latitude_of_origin",27.8333333333333],PARAMETER["central_meridian",-99],PARAMETER["false_easting",1968500],PARAMETER["false_northing",13123333.333],UNIT["US survey foot",0.304800609601219],AXIS["Easting",EAST],AXIS["Northing",NORTH]]`
+
+	d, err := ParseCRSWKT(wkt)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	l, ok := d.Projection.(LambertConformalConic)
+	if !ok || math.Abs(l.FalseEasting-600000) > 1e-3 || d.Unit != USSurveyFootUnit {
+		t.Fatalf("ParseCRSWKT, unexpected definition %+v", d)
+	}
+
+	if len(d.Axes) != 2 || d.Axes[0].Direction != "east" || d.Axes[1].Direction != "north" {
+		t.Errorf("ParseCRSWKT, unexpected axes %v", d.Axes)
+	}
+
+	// the origin of the projection lies at the false easting/northing, in feet
+	p, err := d.FromGeographic(Point{X: -99, Y: 27.8333333333333})
+	if err != nil || !closeTo(p, Point{X: 1968500, Y: 13123333.333}, 1e-3) {
+		t.Errorf("FromGeographic, want 1968500 13123333.333 got %v (%v)", p, err)
+	}
+
+	// Paris based NTF, in grads with a prime meridian east of Greenwich
+	ntf := `GEOGCS["NTF (Paris)",DATUM["Nouvelle_Triangulation_Francaise_Paris",SPHEROID["Clarke 1880 (IGN)",6378249.2,293.466021293627]],PRIMEM["Paris",2.5969213],UNIT["grad",0.015707963267949]]`
+
+	d, err = ParseCRSWKT(ntf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	g, err := d.ToGeographic(Point{X: 0, Y: 50})
+	if err != nil || !closeTo(g, Point{X: 2.33722917, Y: 45}, 1e-8) {
+		t.Errorf("ToGeographic, want 2.33722917 45 got %v (%v)", g, err)
+	}
+}
+
+func TestCRSToWKT(t *testing.T) {
+	for _, code := range []int{4326, 3006, 2154, 3034, 5070, 3857, 27700, 3021, 32633} {
+		d, err := LookupEPSG(code)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, format := range []WKTFormat{WKT1, WKT1ESRI, WKT2} {
+			wkt := d.ToWKT(format)
+
+			parsed, err := ParseCRSWKT(wkt)
+			if err != nil {
+				t.Errorf("EPSG:%d format %d: %v\n%s", code, format, err, wkt)
+				continue
+			}
+
+			if !equivalentCRS(parsed, d) {
+				t.Errorf("EPSG:%d format %d: round trip changed the definition\n%s", code, format, wkt)
+			}
+
+			if (d.Datum.ToWGS84 == nil) != (parsed.Datum.ToWGS84 == nil) ||
+				(d.Datum.ToWGS84 != nil && *d.Datum.ToWGS84 != *parsed.Datum.ToWGS84) {
+				t.Errorf("EPSG:%d format %d: round trip changed the datum shift %v", code, format, parsed.Datum.ToWGS84)
+			}
+
+			if m, err := MatchEPSG(parsed); err != nil || m != code {
+				t.Errorf("EPSG:%d format %d: MatchEPSG got %d (%v)", code, format, m, err)
+			}
+		}
+	}
+}
+
+func TestParseCRSWKTErrors(t *testing.T) {
+	for _, wkt := range []string{
+		``,
+		`GEOGCS["WGS 84"`,
+		`GEOGCS["WGS 84",PRIMEM["Greenwich",0]]`,
+		`VERTCS["NAVD88",VDATUM["North American Vertical Datum 1988"]]`,
+		`GEOGCS["WGS 84",DATUM["WGS_1984",SPHEROID["WGS 84",6378137,x]]]`,
+	} {
+		if _, err := ParseCRSWKT(wkt); err == nil {
+			t.Errorf("ParseCRSWKT(%q), expected an error", wkt)
+		}
+	}
+}
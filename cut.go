@@ -0,0 +1,199 @@
+package gegography
+
+import (
+	"math"
+	"sort"
+)
+
+// cutChain is a part of a ring lying entirely on one side of a cutting line, starting and ending on the line
+type cutChain struct {
+	points MultiPoint
+	start  int
+	end    int
+}
+
+// cutPolygonAtX cuts a polygon along the vertical line x = c, returning the parts on either side of the line.
+// Points on the line are considered to belong to the right side.
+func cutPolygonAtX(p Polygon, c float64) (left MultiPolygon, right MultiPolygon) {
+	if len(p) == 0 {
+		return nil, nil
+	}
+
+	type crossing struct {
+		p  Point
+		id int
+	}
+
+	crossings := make([]crossing, 0)
+	chains := [2][]cutChain{}
+	whole := [2][]MultiPoint{}
+
+	side := func(pt Point) int {
+		if pt.X < c {
+			return 0
+		}
+		return 1
+	}
+
+	for x := range p {
+		ring := ringVertices(p[x])
+		if len(ring) < 3 {
+			continue
+		}
+		ring = orientRing(ring, x == 0)
+		ring = ring[:len(ring)-1]
+		n := len(ring)
+
+		first := -1
+		for y := range n {
+			if side(ring[y]) != side(ring[(y+n-1)%n]) {
+				first = y
+				break
+			}
+		}
+
+		if first < 0 {
+			s := side(ring[0])
+			whole[s] = append(whole[s], closeRing(ring))
+			continue
+		}
+
+		var current cutChain
+		firstID := len(crossings)
+
+		for y := range n + 1 {
+			pt := ring[(first+y)%n]
+			prev := ring[(first+y+n-1)%n]
+
+			if side(pt) != side(prev) {
+				t := (c - prev.X) / (pt.X - prev.X)
+				cp := Point{X: c, Y: prev.Y + t*(pt.Y-prev.Y)}
+				if pt.X == c {
+					cp = pt
+				}
+
+				id := firstID
+				if y < n {
+					id = len(crossings)
+					crossings = append(crossings, crossing{p: cp, id: id})
+				}
+
+				if y > 0 {
+					current.points = append(current.points, cp)
+					current.end = id
+					chains[side(prev)] = append(chains[side(prev)], current)
+				}
+
+				if y == n {
+					break
+				}
+
+				current = cutChain{points: MultiPoint{cp}, start: id}
+			}
+
+			if !pt.equals(current.points[len(current.points)-1]) {
+				current.points = append(current.points, pt)
+			}
+		}
+	}
+
+	sorted := make([]crossing, len(crossings))
+	copy(sorted, crossings)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].p.Y < sorted[j].p.Y
+	})
+
+	partner := make([]int, len(crossings))
+	for x := 0; x+1 < len(sorted); x += 2 {
+		partner[sorted[x].id] = sorted[x+1].id
+		partner[sorted[x+1].id] = sorted[x].id
+	}
+
+	var out [2]MultiPolygon
+	for s := range 2 {
+		rings := assembleCutChains(chains[s], partner)
+		out[s] = assembleRings(append(rings, whole[s]...))
+	}
+
+	return out[0], out[1]
+}
+
+// assembleCutChains joins chains into closed rings by walking along the cutting line from the end of each
+// chain to its partner crossing, where the next chain starts
+func assembleCutChains(chains []cutChain, partner []int) []MultiPoint {
+	byStart := make(map[int]int)
+	for x := range chains {
+		byStart[chains[x].start] = x
+	}
+
+	used := make([]bool, len(chains))
+	rings := make([]MultiPoint, 0)
+
+	for x := range chains {
+		if used[x] {
+			continue
+		}
+
+		ring := make(MultiPoint, 0)
+		current := x
+
+		for !used[current] {
+			used[current] = true
+			ring = append(ring, chains[current].points...)
+
+			next, ok := byStart[partner[chains[current].end]]
+			if !ok {
+				break
+			}
+			current = next
+		}
+
+		rings = append(rings, ring)
+	}
+
+	return rings
+}
+
+// assembleRings builds polygons from a set of rings, treating counter-clockwise rings as shells and clockwise
+// rings as holes. Holes are assigned to the smallest shell containing them, degenerate rings are dropped.
+func assembleRings(rings []MultiPoint) MultiPolygon {
+	shells := make([]MultiPoint, 0)
+	holes := make([]MultiPoint, 0)
+
+	for x := range rings {
+		r := ringVertices(rings[x])
+		if len(r) < 3 || isDegenerateRing(r) {
+			continue
+		}
+
+		if isCCW(r) {
+			shells = append(shells, closeRing(r))
+		} else {
+			holes = append(holes, closeRing(r))
+		}
+	}
+
+	out := make(MultiPolygon, 0, len(shells))
+	for x := range shells {
+		out = append(out, Polygon{shells[x]})
+	}
+
+	for x := range holes {
+		best := -1
+		bestArea := math.Inf(1)
+
+		for y := range shells {
+			area := math.Abs(signedRingArea(shells[y]))
+			if area < bestArea && ringInside(holes[x], shells[y]) {
+				best = y
+				bestArea = area
+			}
+		}
+
+		if best >= 0 {
+			out[best] = append(out[best], holes[x])
+		}
+	}
+
+	return out
+}
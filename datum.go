@@ -0,0 +1,184 @@
+package gegography
+
+import "math"
+
+// Additional reference ellipsoids used by legacy datums
+var (
+	Bessel1841Ellipsoid        = Ellipsoid{Name: "Bessel 1841", A: 6377397.155, F: 1 / 299.1528128}
+	International1924Ellipsoid = Ellipsoid{Name: "International 1924", A: 6378388, F: 1 / 297.0}
+	Clarke1866Ellipsoid        = Ellipsoid{Name: "Clarke 1866", A: 6378206.4, F: 1 / 294.9786982}
+	Airy1830Ellipsoid          = Ellipsoid{Name: "Airy 1830", A: 6377563.396, F: 1 / 299.3249646}
+)
+
+// Legacy datums with their most commonly used Helmert parameters to WGS84
+var (
+	RT90Datum = Datum{Name: "RT90", Ellipsoid: Bessel1841Ellipsoid,
+		ToWGS84: &Helmert{Dx: 414.1, Dy: 41.3, Dz: 603.1, Rx: -0.855, Ry: 2.141, Rz: -7.023}}
+	ED50Datum = Datum{Name: "ED50", Ellipsoid: International1924Ellipsoid,
+		ToWGS84: &Helmert{Dx: -87, Dy: -98, Dz: -121}}
+	NAD27Datum = Datum{Name: "NAD27", Ellipsoid: Clarke1866Ellipsoid,
+		ToWGS84: &Helmert{Dx: -8, Dy: 160, Dz: 176}}
+	OSGB36Datum = Datum{Name: "OSGB36", Ellipsoid: Airy1830Ellipsoid,
+		ToWGS84: &Helmert{Dx: 446.448, Dy: -125.157, Dz: 542.06, Rx: 0.15, Ry: 0.247, Rz: 0.842, Scale: -20.489}}
+	DHDNDatum = Datum{Name: "DHDN", Ellipsoid: Bessel1841Ellipsoid,
+		ToWGS84: &Helmert{Dx: 598.1, Dy: 73.7, Dz: 418.2, Rx: 0.202, Ry: 0.045, Rz: -2.455, Scale: 6.7}}
+)
+
+// Helmert describes a 3- or 7-parameter Helmert transformation between geocentric cartesian coordinates, using
+// the position vector convention (as used by the TOWGS84 parameters of WKT). Translations are in metres,
+// rotations in arc-seconds and the scale difference in parts per million. For parameters published in the
+// coordinate frame convention, negate the rotations.
+type Helmert struct {
+	Dx    float64
+	Dy    float64
+	Dz    float64
+	Rx    float64
+	Ry    float64
+	Rz    float64
+	Scale float64
+}
+
+const arcSecond = math.Pi / (180 * 3600)
+
+func (h Helmert) parameters() (rx, ry, rz, s float64) {
+	return h.Rx * arcSecond, h.Ry * arcSecond, h.Rz * arcSecond, 1 + h.Scale*1e-6
+}
+
+// Apply transforms geocentric cartesian coordinates
+func (h Helmert) Apply(x, y, z float64) (float64, float64, float64) {
+	rx, ry, rz, s := h.parameters()
+
+	return h.Dx + s*(x-rz*y+ry*z),
+		h.Dy + s*(rz*x+y-rx*z),
+		h.Dz + s*(-ry*x+rx*y+z)
+}
+
+// ApplyInverse reverses the transformation done by Apply
+func (h Helmert) ApplyInverse(x, y, z float64) (float64, float64, float64) {
+	rx, ry, rz, s := h.parameters()
+
+	x = (x - h.Dx) / s
+	y = (y - h.Dy) / s
+	z = (z - h.Dz) / s
+
+	// invert the (small angle) rotation matrix exactly, using its adjugate
+	m := [3][3]float64{{1, -rz, ry}, {rz, 1, -rx}, {-ry, rx, 1}}
+	det := m[0][0]*(m[1][1]*m[2][2]-m[1][2]*m[2][1]) -
+		m[0][1]*(m[1][0]*m[2][2]-m[1][2]*m[2][0]) +
+		m[0][2]*(m[1][0]*m[2][1]-m[1][1]*m[2][0])
+
+	ix := ((m[1][1]*m[2][2]-m[1][2]*m[2][1])*x + (m[0][2]*m[2][1]-m[0][1]*m[2][2])*y + (m[0][1]*m[1][2]-m[0][2]*m[1][1])*z) / det
+	iy := ((m[1][2]*m[2][0]-m[1][0]*m[2][2])*x + (m[0][0]*m[2][2]-m[0][2]*m[2][0])*y + (m[0][2]*m[1][0]-m[0][0]*m[1][2])*z) / det
+	iz := ((m[1][0]*m[2][1]-m[1][1]*m[2][0])*x + (m[0][1]*m[2][0]-m[0][0]*m[2][1])*y + (m[0][0]*m[1][1]-m[0][1]*m[1][0])*z) / det
+
+	return ix, iy, iz
+}
+
+// ToGeocentric converts a longitude/latitude point (in degrees) at height h (in metres) above the ellipsoid
+// to geocentric cartesian coordinates
+func (e Ellipsoid) ToGeocentric(p Point, h float64) (float64, float64, float64) {
+	lon := toRadians(p.X)
+	lat := toRadians(p.Y)
+	e2 := e.E2()
+
+	sinLat, cosLat := math.Sincos(lat)
+	n := e.A / math.Sqrt(1-e2*sinLat*sinLat)
+
+	return (n + h) * cosLat * math.Cos(lon),
+		(n + h) * cosLat * math.Sin(lon),
+		(n*(1-e2) + h) * sinLat
+}
+
+// FromGeocentric converts geocentric cartesian coordinates to a longitude/latitude point (in degrees) and the
+// height above the ellipsoid (in metres)
+func (e Ellipsoid) FromGeocentric(x, y, z float64) (Point, float64) {
+	e2 := e.E2()
+	p := math.Hypot(x, y)
+	lon := math.Atan2(y, x)
+
+	lat := math.Atan2(z, p*(1-e2))
+	var h float64
+
+	for range 10 {
+		sinLat := math.Sin(lat)
+		n := e.A / math.Sqrt(1-e2*sinLat*sinLat)
+
+		if math.Abs(math.Cos(lat)) > 1e-10 {
+			h = p/math.Cos(lat) - n
+		} else {
+			h = math.Abs(z) - e.B()
+		}
+
+		next := math.Atan2(z, p*(1-e2*n/(n+h)))
+		if math.Abs(next-lat) < 1e-14 {
+			lat = next
+			break
+		}
+		lat = next
+	}
+
+	return Point{X: toDegrees(lon), Y: toDegrees(lat)}, h
+}
+
+// sameAs reports whether two datums are related to WGS84 in the same way, in which case no datum shift is needed
+// between them
+func (d Datum) sameAs(o Datum) bool {
+	if d.Ellipsoid.A != o.Ellipsoid.A || d.Ellipsoid.F != o.Ellipsoid.F || d.GridShift != o.GridShift {
+		return false
+	}
+
+	if d.ToWGS84 == nil || o.ToWGS84 == nil {
+		return d.ToWGS84 == o.ToWGS84
+	}
+
+	return *d.ToWGS84 == *o.ToWGS84
+}
+
+// shiftToWGS84 converts a longitude/latitude point on the datum to WGS84, using its grid shift if it has one and its
+// Helmert parameters otherwise. Datums with neither are assumed to coincide with WGS84.
+func (d Datum) shiftToWGS84(p Point) (Point, error) {
+	if d.GridShift != nil {
+		return d.GridShift.Forward(p)
+	}
+
+	if d.ToWGS84 == nil {
+		return p, nil
+	}
+
+	x, y, z := d.Ellipsoid.ToGeocentric(p, 0)
+	x, y, z = d.ToWGS84.Apply(x, y, z)
+	out, _ := WGS84Ellipsoid.FromGeocentric(x, y, z)
+
+	return out, nil
+}
+
+// shiftFromWGS84 reverses the conversion done by shiftToWGS84
+func (d Datum) shiftFromWGS84(p Point) (Point, error) {
+	if d.GridShift != nil {
+		return d.GridShift.Inverse(p)
+	}
+
+	if d.ToWGS84 == nil {
+		return p, nil
+	}
+
+	x, y, z := WGS84Ellipsoid.ToGeocentric(p, 0)
+	x, y, z = d.ToWGS84.ApplyInverse(x, y, z)
+	out, _ := d.Ellipsoid.FromGeocentric(x, y, z)
+
+	return out, nil
+}
+
+// shiftDatum converts a longitude/latitude point from one datum to another, via WGS84
+func shiftDatum(p Point, from, to Datum) (Point, error) {
+	if from.sameAs(to) {
+		return p, nil
+	}
+
+	w, err := from.shiftToWGS84(p)
+	if err != nil {
+		return Point{}, err
+	}
+
+	return to.shiftFromWGS84(w)
+}
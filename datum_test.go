@@ -0,0 +1,211 @@
+package gegography
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"testing"
+)
+
+func TestHelmertRoundTrip(t *testing.T) {
+	p := Point{X: 18.06, Y: 59.33}
+
+	x, y, z := Bessel1841Ellipsoid.ToGeocentric(p, 0)
+	x2, y2, z2 := RT90Datum.ToWGS84.ApplyInverse(RT90Datum.ToWGS84.Apply(x, y, z))
+
+	if math.Abs(x-x2) > 1e-6 || math.Abs(y-y2) > 1e-6 || math.Abs(z-z2) > 1e-6 {
+		t.Errorf("ApplyInverse(Apply()), want round trip got %v %v %v", x2-x, y2-y, z2-z)
+	}
+
+	back, h := Bessel1841Ellipsoid.FromGeocentric(x, y, z)
+	if !closeTo(back, p, 1e-11) || math.Abs(h) > 1e-6 {
+		t.Errorf("FromGeocentric(ToGeocentric(%v)), want round trip got %v at height %v", p, back, h)
+	}
+}
+
+func TestRT90ToSWEREF99(t *testing.T) {
+	rt90, err := LookupEPSG(3021)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sweref, err := LookupEPSG(3006)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tr := NewTransformer(rt90, sweref)
+
+	p, err := tr.Transform(Point{X: 1628293, Y: 6580822})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// reference computed with Lantmäteriet's direct projection from SWEREF99 to RT90, which agrees with the
+	// 7-parameter transformation to well within a metre
+	if !closeTo(p, Point{X: 674033.556, Y: 6580649.854}, 1) {
+		t.Errorf("Transform(RT90 2.5 gon V to SWEREF99 TM), want approximately 674033.556 6580649.854 got %v", p)
+	}
+
+	back, err := NewTransformer(sweref, rt90).Transform(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !closeTo(back, Point{X: 1628293, Y: 6580822}, 1e-3) {
+		t.Errorf("Transform(SWEREF99 TM to RT90 2.5 gon V), want round trip got %v", back)
+	}
+}
+
+// testNTv2Grid returns an NTv2 file with a single subgrid covering 10-12°E, 55-57°N. At the node r degrees north
+// of 55°N and c degrees west of 12°E the shift is 1 + r + c/2 + rc/8 arc-seconds north and 2 + 3c + r/4
+// arc-seconds east, which bilinear interpolation reproduces exactly between the nodes.
+func testNTv2Grid() []byte {
+	buf := &bytes.Buffer{}
+
+	record := func(key string, value any) {
+		k := make([]byte, 8)
+		copy(k, key)
+		buf.Write(k)
+
+		v := make([]byte, 8)
+		switch val := value.(type) {
+		case int:
+			binary.LittleEndian.PutUint32(v, uint32(val))
+		case float64:
+			binary.LittleEndian.PutUint64(v, math.Float64bits(val))
+		case string:
+			copy(v, val)
+		}
+		buf.Write(v)
+	}
+
+	record("NUM_OREC", 11)
+	record("NUM_SREC", 11)
+	record("NUM_FILE", 1)
+	record("GS_TYPE", "SECONDS")
+	record("VERSION", "NTv2.0")
+	record("SYSTEM_F", "TEST_F")
+	record("SYSTEM_T", "TEST_T")
+	record("MAJOR_F", 6378388.0)
+	record("MINOR_F", 6356911.946)
+	record("MAJOR_T", 6378137.0)
+	record("MINOR_T", 6356752.314)
+
+	record("SUB_NAME", "TEST")
+	record("PARENT", "NONE")
+	record("CREATED", "")
+	record("UPDATED", "")
+	record("S_LAT", 55*3600.0)
+	record("N_LAT", 57*3600.0)
+	record("E_LONG", -12*3600.0)
+	record("W_LONG", -10*3600.0)
+	record("LAT_INC", 3600.0)
+	record("LONG_INC", 3600.0)
+	record("GS_COUNT", 9)
+
+	// nodes run from south to north, and within a row from east to west
+	for r := range 3 {
+		for c := range 3 {
+			lat, lonE := testNTv2Shift(float64(r), float64(c))
+
+			node := make([]byte, 16)
+			binary.LittleEndian.PutUint32(node[0:4], math.Float32bits(float32(lat)))
+			binary.LittleEndian.PutUint32(node[4:8], math.Float32bits(float32(-lonE)))
+			buf.Write(node)
+		}
+	}
+
+	return buf.Bytes()
+}
+
+// testNTv2Shift returns the north and east shift of testNTv2Grid in arc-seconds, r degrees north of 55°N and c
+// degrees west of 12°E
+func testNTv2Shift(r, c float64) (float64, float64) {
+	return 1 + r + c/2 + r*c/8, 2 + 3*c + r/4
+}
+
+func TestNTv2Grid(t *testing.T) {
+	grid, err := ReadNTv2(bytes.NewReader(testNTv2Grid()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, p := range []Point{{X: 11.5, Y: 56.25}, {X: 10.2, Y: 55.7}, {X: 12, Y: 55}, {X: 10, Y: 57}} {
+		lat, lonE := testNTv2Shift(p.Y-55, 12-p.X)
+		want := Point{X: p.X + lonE/3600, Y: p.Y + lat/3600}
+
+		shifted, err := grid.Forward(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !closeTo(shifted, want, 1e-12) {
+			t.Errorf("NTv2Grid.Forward(%v), want %v got %v", p, want, shifted)
+		}
+	}
+
+	p := Point{X: 11.5, Y: 56.25}
+	shifted, err := grid.Forward(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	back, err := grid.Inverse(shifted)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !closeTo(back, p, 1e-12) {
+		t.Errorf("NTv2Grid.Inverse(%v), want %v got %v", shifted, p, back)
+	}
+
+	if _, err = grid.Forward(Point{X: 20, Y: 56}); err == nil {
+		t.Error("NTv2Grid.Forward(), want error for point outside grid")
+	}
+}
+
+func TestRegisterGridShift(t *testing.T) {
+	grid, err := ReadNTv2(bytes.NewReader(testNTv2Grid()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	RegisterGridShift("ED 50", grid)
+	defer RegisterGridShift("ED 50", nil)
+
+	ed50, err := LookupEPSG(4230)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if ed50.Datum.GridShift != grid {
+		t.Error("LookupEPSG(4230), want the registered grid shift")
+	}
+
+	parsed, err := ParseCRSWKT(`GEOGCS["ED50",DATUM["European_Datum_1950",SPHEROID["International 1924",6378388,297]],PRIMEM["Greenwich",0],UNIT["degree",0.0174532925199433]]`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if parsed.Datum.GridShift != grid {
+		t.Errorf("ParseCRSWKT(ED50), want the registered grid shift got datum %v", parsed.Datum)
+	}
+
+	fc := NewFeatureCollection()
+	fc.AddFeature(Feature{Type: "Point", Coordinates: Point{X: 11.5, Y: 56.25}})
+
+	if err := fc.Transform(CRS{Type: "name", Properties: CRSProperties{Name: "EPSG:4230"}}, CRS{Type: "name", Properties: CRSProperties{Name: "EPSG:4326"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	want, _ := grid.Forward(Point{X: 11.5, Y: 56.25})
+	if got := fc.Features[0].Coordinates.(Point); !closeTo(got, want, 1e-9) {
+		t.Errorf("Transform(EPSG:4230 to EPSG:4326), want %v from the grid got %v", want, got)
+	}
+
+	RegisterGridShift("ED50", nil)
+	if d, _ := LookupEPSG(4230); d.Datum.GridShift != nil {
+		t.Error("LookupEPSG(4230), want no grid shift after removing it")
+	}
+}
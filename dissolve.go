@@ -0,0 +1,88 @@
+package gegography
+
+import (
+	"fmt"
+	"strings"
+)
+
+// dissolveKey returns a string identifying the values of the given properties of a feature. Values of different
+// types never share a key, so the number 1 and the string "1" form separate groups.
+func dissolveKey(f *Feature, keys []string) string {
+	var sb strings.Builder
+
+	for _, k := range keys {
+		fmt.Fprintf(&sb, "%#v\x00", f.Properties[k])
+	}
+
+	return sb.String()
+}
+
+// featureGroup holds the features sharing the same values for a set of grouping properties
+type featureGroup []*Feature
+
+// groupFeatures groups the features of the collection by the values of the given properties, in the order in which
+// the groups first appear. Without keys, all features form one group.
+func groupFeatures(fc *FeatureCollection, keys []string) []featureGroup {
+	groups := make([]featureGroup, 0)
+	index := make(map[string]int)
+
+	for x := range fc.Features {
+		f := &fc.Features[x]
+
+		k := dissolveKey(f, keys)
+		i, ok := index[k]
+		if !ok {
+			i = len(groups)
+			index[k] = i
+			groups = append(groups, nil)
+		}
+
+		groups[i] = append(groups[i], f)
+	}
+
+	return groups
+}
+
+// properties returns the properties of the feature made from the group, holding the grouping properties, taken
+// from its first feature, and the aggregates computed over all of its features
+func (g featureGroup) properties(keys []string, aggregates []PropertyAggregate) map[string]any {
+	props := make(map[string]any, len(keys)+len(aggregates))
+	for _, key := range keys {
+		props[key] = g[0].Properties[key]
+	}
+
+	for _, a := range aggregates {
+		props[a.name("")] = a.aggregate(g)
+	}
+
+	return props
+}
+
+// Dissolve merges the polygons of all features sharing the same values for the given properties into one Polygon
+// or MultiPolygon feature each, in the order in which the groups first appear. Output features hold the key
+// properties and the aggregates, computed over the features of their group. Without keys, every feature is merged
+// into one. Features missing a key property are grouped with those for which it is nil. Only polygonal features
+// can be dissolved.
+func (fc *FeatureCollection) Dissolve(keys []string, aggregates []PropertyAggregate) (FeatureCollection, error) {
+	out := fc.emptyCopy()
+
+	if err := validateAggregates(aggregates); err != nil {
+		return FeatureCollection{}, err
+	}
+
+	for _, g := range groupFeatures(fc, keys) {
+		parts := make([]MultiPolygon, len(g))
+		for x, f := range g {
+			mp, err := f.polygons()
+			if err != nil {
+				return FeatureCollection{}, err
+			}
+
+			parts[x] = mp
+		}
+
+		out.AddFeature(polygonalFeature(unionAll(parts), g.properties(keys, aggregates)))
+	}
+
+	return out, nil
+}
@@ -0,0 +1,123 @@
+package gegography
+
+import (
+	"math"
+	"slices"
+	"testing"
+)
+
+func TestDissolve(t *testing.T) {
+	parcels := NewFeatureCollection()
+	parcels.Name = "parcels"
+
+	for _, p := range []struct {
+		wkt   string
+		owner any
+		area  any
+	}{
+		{"POLYGON ((0 0, 10 0, 10 10, 0 10, 0 0))", "anna", 100.0},
+		{"POLYGON ((10 0, 20 0, 20 10, 10 10, 10 0))", "anna", 100.0},
+		{"POLYGON ((30 0, 40 0, 40 10, 30 10, 30 0))", "anna", "unknown"},
+		{"POLYGON ((0 10, 20 10, 20 20, 0 20, 0 10))", "bertil", 200.0},
+		{"MULTIPOLYGON (((50 0, 60 0, 60 5, 50 5, 50 0)), ((50 5, 60 5, 60 10, 50 10, 50 5)))", nil, 50.0},
+		{"POLYGON ((60 0, 70 0, 70 10, 60 10, 60 0))", nil, nil},
+	} {
+		f := mustParseWKT(t, p.wkt)
+		f.Properties["owner"] = p.owner
+		f.Properties["size"] = p.area
+		parcels.AddFeature(f)
+	}
+
+	// a parcel without an owner property is grouped with those without an owner
+	missing := mustParseWKT(t, "POLYGON ((70 0, 80 0, 80 10, 70 10, 70 0))")
+	parcels.AddFeature(missing)
+
+	got, err := parcels.Dissolve([]string{"owner"}, []PropertyAggregate{
+		{Func: AggregateCount},
+		{Property: "size", Func: AggregateSum},
+		{Property: "size", Func: AggregateFirst},
+		{Property: "size", Func: AggregateList, Name: "sizes"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []struct {
+		owner any
+		typ   string
+		area  float64
+		count float64
+		sum   float64
+		first any
+		sizes []any
+	}{
+		{"anna", "MultiPolygon", 300, 3, 200, 100.0, []any{100.0, 100.0, "unknown"}},
+		{"bertil", "Polygon", 200, 1, 200, 200.0, []any{200.0}},
+		{nil, "Polygon", 300, 3, 50, 50.0, []any{50.0}},
+	}
+
+	if got.Name != "parcels" || len(got.Features) != len(want) {
+		t.Fatalf("Dissolve(owner), want %d features in parcels got %d in %q", len(want), len(got.Features), got.Name)
+	}
+
+	for x, w := range want {
+		f := got.Features[x]
+
+		if f.Properties["owner"] != w.owner || f.Type != w.typ || math.Abs(f.Area()-w.area) > 1e-9 {
+			t.Errorf("Dissolve(owner), want feature %d a %s owned by %v with area %v got a %s owned by %v with area %v",
+				x, w.typ, w.owner, w.area, f.Type, f.Properties["owner"], f.Area())
+		}
+
+		if f.Properties["count"] != w.count || f.Properties["size_sum"] != w.sum || f.Properties["size_first"] != w.first {
+			t.Errorf("Dissolve(owner), want feature %d with count %v, size_sum %v and size_first %v got %v", x, w.count, w.sum, w.first, f.Properties)
+		}
+
+		if sizes, _ := f.Properties["sizes"].([]any); !slices.Equal(sizes, w.sizes) {
+			t.Errorf("Dissolve(owner), want feature %d with sizes %v got %v", x, w.sizes, sizes)
+		}
+	}
+
+	// the merged neighbours form a single ring
+	if mp := got.Features[0].Coordinates.(MultiPolygon); len(mp) != 2 || len(mp[0]) != 1 || len(mp[1]) != 1 {
+		t.Errorf("Dissolve(owner), want anna's parcels as 2 polygons without holes got %v", mp)
+	}
+}
+
+func TestDissolveAll(t *testing.T) {
+	fc := NewFeatureCollection()
+	for _, wkt := range []string{
+		"POLYGON ((0 0, 10 0, 10 10, 0 10, 0 0))",
+		"POLYGON ((5 5, 15 5, 15 15, 5 15, 5 5))",
+		"POLYGON ((10 0, 20 0, 20 5, 10 5, 10 0))",
+	} {
+		fc.AddFeature(mustParseWKT(t, wkt))
+	}
+
+	got, err := fc.Dissolve(nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(got.Features) != 1 || got.Features[0].Type != "Polygon" || math.Abs(got.Features[0].Area()-225) > 1e-9 {
+		t.Errorf("Dissolve(nil), want one Polygon with area 225 got %v", got.Features)
+	}
+
+	if len(got.Features[0].Properties) != 0 {
+		t.Errorf("Dissolve(nil), want no properties got %v", got.Features[0].Properties)
+	}
+
+	for _, a := range []PropertyAggregate{{Func: AggregateMean}, {Property: "id", Func: AggregateFunc(100)}} {
+		if _, err := fc.Dissolve(nil, []PropertyAggregate{a}); err == nil {
+			t.Errorf("Dissolve(nil, %+v), want an error for the aggregate", a)
+		}
+
+		if v := a.aggregate([]*Feature{&fc.Features[0]}); v != nil {
+			t.Errorf("aggregate(%+v), want nil got %v", a, v)
+		}
+	}
+
+	fc.AddFeature(mustParseWKT(t, "LINESTRING (0 0, 1 1)"))
+	if _, err := fc.Dissolve(nil, nil); err == nil {
+		t.Error("Dissolve(nil) with a LineString, want an error")
+	}
+}
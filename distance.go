@@ -0,0 +1,344 @@
+package gegography
+
+import (
+	"fmt"
+	"math"
+)
+
+// facet is a segment of a line or polygon ring, or a point when a and b are equal
+type facet struct {
+	a, b Point
+}
+
+func (f facet) bounds() BBox {
+	return EmptyBBox().ExtendPoint(f.a).ExtendPoint(f.b)
+}
+
+// facets returns the points and segments making up the geometry
+func (g relateGeometry) facets() []facet {
+	out := make([]facet, 0)
+
+	for _, p := range g.points {
+		out = append(out, facet{a: p, b: p})
+	}
+
+	add := func(pts []Point) {
+		if len(pts) == 1 {
+			out = append(out, facet{a: pts[0], b: pts[0]})
+		}
+
+		for x := 1; x < len(pts); x++ {
+			out = append(out, facet{a: pts[x-1], b: pts[x]})
+		}
+	}
+
+	for _, l := range g.lines {
+		add(l)
+	}
+
+	for _, p := range g.polys {
+		for _, r := range p {
+			add(closeRing(r))
+		}
+	}
+
+	return out
+}
+
+// facetDistance returns the distance between two facets and the closest point of each
+func facetDistance(f, g facet) (float64, Point, Point) {
+	if !f.a.equals(f.b) && !g.a.equals(g.b) {
+		if i := intersectSegments(f.a, f.b, g.a, g.b); i.Kind != noIntersection {
+			return 0, i.Points[0], i.Points[0]
+		}
+	}
+
+	best, pf, pg := math.Inf(1), Point{}, Point{}
+
+	try := func(a, b Point) {
+		if d := distance(a, b); d < best {
+			best, pf, pg = d, a, b
+		}
+	}
+
+	try(f.a, closestPointOnSegment(f.a, g.a, g.b))
+	try(f.b, closestPointOnSegment(f.b, g.a, g.b))
+	try(closestPointOnSegment(g.a, f.a, f.b), g.a)
+	try(closestPointOnSegment(g.b, f.a, f.b), g.b)
+
+	return best, pf, pg
+}
+
+// firstPoints returns one point of every component of the geometry
+func (g relateGeometry) firstPoints() []Point {
+	pts := append([]Point{}, g.points...)
+
+	for _, l := range g.lines {
+		if len(l) > 0 {
+			pts = append(pts, l[0])
+		}
+	}
+
+	for _, p := range g.polys {
+		if len(p) > 0 && len(p[0]) > 0 {
+			pts = append(pts, p[0][0])
+		}
+	}
+
+	return pts
+}
+
+// facetIndex is a geometry with its facets indexed, for measuring the distance to it from other geometries
+type facetIndex struct {
+	geom   relateGeometry
+	facets []facet
+	si     *SpatialIndex
+}
+
+func newFacetIndex(g relateGeometry) *facetIndex {
+	fi := &facetIndex{geom: g, facets: g.facets()}
+
+	entries := make([]rtreeEntry, len(fi.facets))
+	for x := range fi.facets {
+		entries[x] = rtreeEntry{id: x, bounds: fi.facets[x].bounds()}
+	}
+
+	fi.si = &SpatialIndex{root: bulkLoad(entries)}
+
+	return fi
+}
+
+// closestPoints returns the distance from a geometry to the indexed one and the closest point of each, or false if
+// either is empty
+func (fi *facetIndex) closestPoints(a relateGeometry) (float64, Point, Point, bool) {
+	b, fb := fi.geom, fi.facets
+
+	fa := a.facets()
+	if len(fa) == 0 || len(fb) == 0 {
+		return 0, Point{}, Point{}, false
+	}
+
+	// a component lying inside a polygon without crossing its boundary is found by any of its points
+	if b.locator != nil {
+		for _, p := range a.firstPoints() {
+			if b.locator.locate(p) != locExterior {
+				return 0, p, p, true
+			}
+		}
+	}
+
+	if a.locator != nil {
+		for _, p := range b.firstPoints() {
+			if a.locator.locate(p) != locExterior {
+				return 0, p, p, true
+			}
+		}
+	}
+
+	// start from the facet of b nearest to a point of a, which bounds the distance well before the full search
+	nearest := fi.si.nearest(fa[0].a, 1, func(id int) float64 { return segmentDistance(fa[0].a, fb[id].a, fb[id].b) })
+	best, pa, pb := facetDistance(fa[0], fb[nearest[0]])
+
+	for _, f := range fa {
+		for _, id := range fi.si.Search(f.bounds().Buffer(best)) {
+			if d, p, q := facetDistance(f, fb[id]); d < best {
+				best, pa, pb = d, p, q
+			}
+
+			if best == 0 {
+				return 0, pa, pb, true
+			}
+		}
+	}
+
+	return best, pa, pb, true
+}
+
+// closestPoints returns the distance between two geometries and the closest point of each, or false if either is
+// empty. The facets of b are indexed, so a should be the smaller geometry.
+func closestPoints(a, b relateGeometry) (float64, Point, Point, bool) {
+	return newFacetIndex(b).closestPoints(a)
+}
+
+// featureClosestPoints returns the distance between two features and the closest point of each
+func featureClosestPoints(f, o *Feature) (float64, Point, Point, error) {
+	a, err := f.relateGeometry()
+	if err != nil {
+		return 0, Point{}, Point{}, err
+	}
+
+	b, err := o.relateGeometry()
+	if err != nil {
+		return 0, Point{}, Point{}, err
+	}
+
+	d, pa, pb, ok := closestPoints(a, b)
+	if !ok {
+		return 0, Point{}, Point{}, GeoFormatError{Msg: "cannot measure the distance to an empty geometry"}
+	}
+
+	return d, pa, pb, nil
+}
+
+// Distance returns the smallest planar distance between any point of the feature and any point of o, which is 0
+// when they intersect or one lies inside the other
+func (f *Feature) Distance(o *Feature) (float64, error) {
+	d, _, _, err := featureClosestPoints(f, o)
+
+	return d, err
+}
+
+// ClosestPoints returns the point of the feature and the point of o which are nearest to each other. When the
+// features intersect, both are the same point of the intersection.
+func (f *Feature) ClosestPoints(o *Feature) (Point, Point, error) {
+	_, pa, pb, err := featureClosestPoints(f, o)
+
+	return pa, pb, err
+}
+
+// NearestOptions controls nearest feature queries
+type NearestOptions struct {
+	// Geodesic takes the coordinates to be WGS84 longitude/latitude and measures distances in metres along the
+	// ellipsoid. Distances to vertices are exact, while edges are taken to be straight in an azimuthal equidistant
+	// projection centred on the query point, which is accurate for edges much shorter than their distance from it.
+	Geodesic bool
+}
+
+// FeatureDistance is a feature found by a nearest feature query
+type FeatureDistance struct {
+	// Index is the position of the feature in the collection
+	Index int
+	// Distance is the distance from the query point to the feature
+	Distance float64
+	// Point is the point of the feature nearest to the query point
+	Point Point
+}
+
+// azimuthalEquidistant returns a function projecting longitude/latitude points to an azimuthal equidistant
+// projection centred on c, in which distances and bearings from c are those along the WGS84 ellipsoid
+func azimuthalEquidistant(c Point) func(Point) (Point, error) {
+	return func(p Point) (Point, error) {
+		d, bearing, _ := WGS84Ellipsoid.Inverse(c, p)
+		s, co := math.Sincos(toRadians(bearing))
+
+		return Point{X: d * s, Y: d * co}, nil
+	}
+}
+
+// geodesicBoxDistance returns a lower bound for the WGS84 geodesic distance in metres from p to any point of a
+// longitude/latitude bounding box. Every point of the ellipsoid lies at least the semi-minor axis from its centre,
+// so no path along it is shorter than the angle between the geocentric directions of its ends times that axis.
+func geodesicBoxDistance(p Point, b BBox) float64 {
+	if b.IsEmpty() {
+		return math.Inf(1)
+	}
+
+	geocentric := func(lat float64) float64 {
+		return math.Atan((1 - WGS84Ellipsoid.E2()) * math.Tan(toRadians(math.Max(-90, math.Min(90, lat)))))
+	}
+
+	// angle returns the angle between p and a point with geocentric latitude lat, dLon radians of longitude away
+	psi := geocentric(p.Y)
+	angle := func(lat, dLon float64) float64 {
+		return math.Acos(math.Max(-1, math.Min(1, math.Sin(psi)*math.Sin(lat)+math.Cos(psi)*math.Cos(lat)*math.Cos(dLon))))
+	}
+
+	lo, hi := geocentric(b.MinY), geocentric(b.MaxY)
+	clamp := func(lat float64) float64 { return math.Max(lo, math.Min(hi, lat)) }
+
+	// toP is how far east of the western edge of the box p lies, in degrees
+	width := b.MaxX - b.MinX
+	toP := math.Mod(math.Mod(p.X-b.MinX, 360)+360, 360)
+	if width >= 360 || toP <= width {
+		return WGS84Ellipsoid.B() * angle(clamp(psi), 0)
+	}
+
+	// outside the longitudes of the box, the nearest point lies on one of its edges, either at the foot of the great
+	// circle from p meeting the edge at right angles or, when that is beyond the edge, at one of its ends
+	best := math.Inf(1)
+	for _, dLon := range []float64{toRadians(toP - width), toRadians(360 - toP)} {
+		foot := math.Atan2(math.Sin(psi), math.Cos(psi)*math.Cos(dLon))
+		best = math.Min(best, math.Min(angle(clamp(foot), dLon), math.Min(angle(lo, dLon), angle(hi, dLon))))
+	}
+
+	return WGS84Ellipsoid.B() * best
+}
+
+// Nearest returns the k features nearest to p, closest first. Features with empty geometries are never returned,
+// and fewer than k features are returned if the collection holds fewer. Features inside which p lies have distance
+// 0. A new index is built for every call, see SpatialIndex.NearestFeatures for repeated queries.
+func (fc *FeatureCollection) Nearest(p Point, k int, opts NearestOptions) ([]FeatureDistance, error) {
+	return NewSpatialIndex(fc).NearestFeatures(fc, p, k, opts)
+}
+
+// NearestFeatures returns the k features of fc nearest to p, closest first, as FeatureCollection.Nearest does, using
+// the index instead of building a new one. The index must hold the bounds of the features of fc, as built by
+// NewSpatialIndex, and an error is returned for items which are not features of fc. In geodesic mode, only the
+// features reached by the search are projected.
+func (si *SpatialIndex) NearestFeatures(fc *FeatureCollection, p Point, k int, opts NearestOptions) ([]FeatureDistance, error) {
+	project := func(f *Feature) (Feature, error) { return *f, nil }
+	boxDistance := func(b BBox) float64 { return b.Distance(p) }
+	q := p
+
+	if opts.Geodesic {
+		aeqd := azimuthalEquidistant(p)
+		project = func(f *Feature) (Feature, error) { return f.transformed(aeqd) }
+		boxDistance = func(b BBox) float64 { return geodesicBoxDistance(p, b) }
+		q = Point{}
+	}
+
+	target := relateGeometry{points: []Point{q}}
+	closest := make(map[int]Point)
+
+	var err error
+
+	ids := si.nearestBy(k, boxDistance, func(id int) float64 {
+		if err != nil {
+			return math.Inf(1)
+		}
+
+		if fc == nil || id < 0 || id >= len(fc.Features) {
+			err = GeoFormatError{Msg: fmt.Sprintf("index item %d is not a feature of the collection", id)}
+			return math.Inf(1)
+		}
+
+		f, perr := project(&fc.Features[id])
+		if perr != nil {
+			err = perr
+			return math.Inf(1)
+		}
+
+		g, gerr := f.relateGeometry()
+		if gerr != nil {
+			err = gerr
+			return math.Inf(1)
+		}
+
+		d, _, pt, _ := closestPoints(target, g)
+		closest[id] = pt
+
+		// edges are straight in the projection rather than along geodesics, which may bring them slightly closer
+		// than the bound for their box. The search needs the bound to hold.
+		return math.Max(d, boxDistance(si.bounds[id]))
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]FeatureDistance, len(ids))
+	for x, id := range ids {
+		pt := closest[id]
+		out[x] = FeatureDistance{Index: id, Distance: distance(q, pt), Point: pt}
+
+		if opts.Geodesic {
+			// map the closest point back along its geodesic from p, which is exactly Distance long
+			out[x].Point = p
+			if out[x].Distance > 0 {
+				out[x].Point = DestinationPoint(p, toDegrees(math.Atan2(pt.X, pt.Y)), out[x].Distance)
+			}
+		}
+	}
+
+	return out, nil
+}
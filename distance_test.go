@@ -0,0 +1,302 @@
+package gegography
+
+import (
+	"math"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func TestDistance(t *testing.T) {
+	square := "POLYGON ((0 0, 10 0, 10 10, 0 10, 0 0))"
+
+	tests := []struct {
+		name   string
+		a, b   string
+		want   float64
+		pa, pb Point
+	}{
+		{"points", "POINT (0 0)", "POINT (3 4)", 5, Point{X: 0, Y: 0}, Point{X: 3, Y: 4}},
+		{"point and line", "POINT (5 5)", "LINESTRING (0 0, 10 0)", 5, Point{X: 5, Y: 5}, Point{X: 5, Y: 0}},
+		{"crossing lines", "LINESTRING (0 0, 10 10)", "LINESTRING (0 10, 10 0)", 0, Point{X: 5, Y: 5}, Point{X: 5, Y: 5}},
+		{"parallel lines", "LINESTRING (0 0, 10 0)", "LINESTRING (2 3, 8 3)", 3, Point{X: 2, Y: 0}, Point{X: 2, Y: 3}},
+		{"point inside polygon", square, "POINT (3 3)", 0, Point{X: 3, Y: 3}, Point{X: 3, Y: 3}},
+		{"point outside polygon", square, "POINT (13 14)", 5, Point{X: 10, Y: 10}, Point{X: 13, Y: 14}},
+		{"line inside polygon", "LINESTRING (2 2, 3 3)", square, 0, Point{X: 2, Y: 2}, Point{X: 2, Y: 2}},
+		{"disjoint polygons", square, "POLYGON ((12 2, 20 2, 20 8, 12 8, 12 2))", 2, Point{X: 10, Y: 2}, Point{X: 12, Y: 2}},
+		{
+			"polygon in a hole",
+			"POLYGON ((0 0, 10 0, 10 10, 0 10, 0 0), (2 2, 8 2, 8 8, 2 8, 2 2))",
+			"POLYGON ((5 3, 6 5, 4 5, 5 3))",
+			1, Point{X: 5, Y: 2}, Point{X: 5, Y: 3},
+		},
+		{
+			"multipolygon",
+			"MULTIPOLYGON (((20 0, 30 0, 30 10, 20 10, 20 0)), ((5 -1, 0 -10, 10 -10, 5 -1)))",
+			square, 1, Point{X: 5, Y: -1}, Point{X: 5, Y: 0},
+		},
+		{"multipoint and multiline", "MULTIPOINT ((0 20), (5 12))", "MULTILINESTRING ((0 0, 10 0), (0 10, 10 10))", 2, Point{X: 5, Y: 12}, Point{X: 5, Y: 10}},
+	}
+
+	for _, tt := range tests {
+		a, b := mustParseWKT(t, tt.a), mustParseWKT(t, tt.b)
+
+		d, err := a.Distance(&b)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if math.Abs(d-tt.want) > 1e-12 {
+			t.Errorf("Distance(%s, %s), want %v got %v", tt.a, tt.b, tt.want, d)
+		}
+
+		pa, pb, err := a.ClosestPoints(&b)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if distance(pa, tt.pa) > 1e-12 || distance(pb, tt.pb) > 1e-12 {
+			t.Errorf("ClosestPoints(%s, %s), want %v and %v got %v and %v", tt.a, tt.b, tt.pa, tt.pb, pa, pb)
+		}
+
+		if d2, _ := b.Distance(&a); math.Abs(d2-d) > 1e-12 {
+			t.Errorf("Distance(%s, %s), want the reverse distance %v got %v", tt.b, tt.a, d, d2)
+		}
+	}
+}
+
+func TestDistanceAgainstBruteForce(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+
+	line := func(n int, off float64) MultiPoint {
+		l := make(MultiPoint, n)
+		for x := range l {
+			l[x] = Point{X: off + r.Float64()*10, Y: r.Float64() * 10}
+		}
+
+		return l
+	}
+
+	for x := range 100 {
+		a := Feature{Type: "LineString", Coordinates: line(2+r.Intn(30), 0)}
+		b := Feature{Type: "LineString", Coordinates: line(2+r.Intn(30), 5+r.Float64()*10)}
+
+		want := math.Inf(1)
+		la, lb := a.Coordinates.(MultiPoint), b.Coordinates.(MultiPoint)
+
+		for i := 1; i < len(la); i++ {
+			for j := 1; j < len(lb); j++ {
+				d, _, _ := facetDistance(facet{a: la[i-1], b: la[i]}, facet{a: lb[j-1], b: lb[j]})
+				want = math.Min(want, d)
+			}
+		}
+
+		got, err := a.Distance(&b)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if math.Abs(got-want) > 1e-12 {
+			t.Errorf("%d: Distance(a, b), want %v got %v", x, want, got)
+		}
+
+		pa, pb, _ := a.ClosestPoints(&b)
+		if math.Abs(distance(pa, pb)-want) > 1e-9 {
+			t.Errorf("%d: ClosestPoints(a, b), want points %v apart got %v and %v", x, want, pa, pb)
+		}
+	}
+}
+
+func TestDistanceErrors(t *testing.T) {
+	point := mustParseWKT(t, "POINT (0 0)")
+	empty := Feature{Type: "MultiPoint", Coordinates: MultiPoint{}}
+	unknown := Feature{Type: "Circle"}
+
+	if _, err := point.Distance(&empty); err == nil {
+		t.Error("Distance(POINT (0 0), MULTIPOINT EMPTY), want an error for an empty geometry")
+	}
+
+	if _, err := point.Distance(&unknown); err == nil {
+		t.Error("Distance(POINT (0 0), Circle), want an error for an unsupported geometry type")
+	}
+}
+
+func TestNearest(t *testing.T) {
+	fc := &FeatureCollection{}
+	for _, wkt := range []string{
+		"POINT (10 0)",
+		"POINT (0 3)",
+		"LINESTRING (-5 -2, 5 -2)",
+		"POLYGON ((20 20, 30 20, 30 30, 20 30, 20 20))",
+		"POINT (-7 0)",
+	} {
+		f, err := ParseWKT(wkt)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		fc.Features = append(fc.Features, f)
+	}
+
+	fc.Features = slices.Insert(fc.Features, 4, Feature{Type: "MultiPoint", Coordinates: MultiPoint{}})
+
+	got, err := fc.Nearest(Point{X: 0, Y: 0}, 3, NearestOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []FeatureDistance{
+		{Index: 2, Distance: 2, Point: Point{X: 0, Y: -2}},
+		{Index: 1, Distance: 3, Point: Point{X: 0, Y: 3}},
+		{Index: 5, Distance: 7, Point: Point{X: -7, Y: 0}},
+	}
+
+	if len(got) != len(want) {
+		t.Fatalf("Nearest({0 0}, 3), want %v got %v", want, got)
+	}
+
+	for x := range want {
+		if got[x].Index != want[x].Index || math.Abs(got[x].Distance-want[x].Distance) > 1e-12 ||
+			distance(got[x].Point, want[x].Point) > 1e-12 {
+			t.Errorf("Nearest({0 0}, 3)[%d], want %v got %v", x, want[x], got[x])
+		}
+	}
+
+	got, _ = fc.Nearest(Point{X: 25, Y: 25}, 1, NearestOptions{})
+	if len(got) != 1 || got[0].Index != 3 || got[0].Distance != 0 {
+		t.Errorf("Nearest({25 25}, 1) inside a polygon, want feature 3 at distance 0 got %v", got)
+	}
+
+	got, _ = fc.Nearest(Point{}, 10, NearestOptions{})
+	if len(got) != 5 {
+		t.Errorf("Nearest({0 0}, 10), want the 5 non-empty features got %d", len(got))
+	}
+}
+
+func TestNearestGeodesic(t *testing.T) {
+	wells := []Point{{X: 18.07, Y: 59.33}, {X: 17.64, Y: 59.86}, {X: 11.97, Y: 57.71}, {X: 13.0, Y: 55.6}, {X: 179.9, Y: 59.5}}
+
+	fc := &FeatureCollection{}
+	for _, p := range wells {
+		fc.Features = append(fc.Features, Feature{Type: "Point", Properties: map[string]any{}, Coordinates: p})
+	}
+
+	p := Point{X: 16.5, Y: 59.4}
+
+	got, err := fc.Nearest(p, len(wells), NearestOptions{Geodesic: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for x := 1; x < len(got); x++ {
+		if got[x].Distance < got[x-1].Distance {
+			t.Errorf("Nearest(%v, %d) geodesic, want results sorted by distance got %v", p, len(wells), got)
+		}
+	}
+
+	for _, g := range got {
+		if want := GeodesicDistance(p, wells[g.Index]); math.Abs(g.Distance-want) > 1e-3 {
+			t.Errorf("Nearest(%v) geodesic, want distance %v to %v got %v", p, want, wells[g.Index], g.Distance)
+		}
+
+		if distance(g.Point, wells[g.Index]) > 1e-6 {
+			t.Errorf("Nearest(%v) geodesic, want closest point %v got %v", p, wells[g.Index], g.Point)
+		}
+	}
+
+	// a road running north-south: the closest point lies on it, about 1° of longitude east at 60°N
+	road := Feature{Type: "LineString", Properties: map[string]any{}, Coordinates: MultiPoint{{X: 11, Y: 59}, {X: 11, Y: 61}}}
+	roads := &FeatureCollection{Features: []Feature{road}}
+
+	got, err = roads.Nearest(Point{X: 10, Y: 60}, 1, NearestOptions{Geodesic: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(got) != 1 || math.Abs(got[0].Point.X-11) > 1e-3 || math.Abs(got[0].Distance-GeodesicDistance(Point{X: 10, Y: 60}, got[0].Point)) > 1e-3 {
+		t.Errorf("Nearest({10 60}, 1) geodesic, want a point on the road at longitude 11 got %v", got)
+	}
+	if got[0].Distance < 55000 || got[0].Distance > 56000 {
+		t.Errorf("Nearest({10 60}, 1) geodesic, want about 55.8 km got %v", got[0].Distance)
+	}
+}
+
+func TestGeodesicBoxDistance(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+
+	for range 2000 {
+		lon, lat := -180+360*r.Float64(), -80+160*r.Float64()
+		b := BBox{MinX: lon, MinY: lat, MaxX: lon + 20*r.Float64(), MaxY: math.Min(90, lat+20*r.Float64())}
+		p := Point{X: -180 + 360*r.Float64(), Y: -90 + 180*r.Float64()}
+
+		bound := geodesicBoxDistance(p, b)
+		for range 5 {
+			q := Point{X: b.MinX + (b.MaxX-b.MinX)*r.Float64(), Y: b.MinY + (b.MaxY-b.MinY)*r.Float64()}
+			// the same point, within 180° of longitude of p
+			q.X = p.X + math.Remainder(q.X-p.X, 360)
+			if d := GeodesicDistance(p, q); bound > d*(1+1e-9) {
+				t.Fatalf("geodesicBoxDistance(%v, %v), want at most the distance %v to %v got %v", p, b, d, q, bound)
+			}
+		}
+	}
+
+	// due north of a box, the bound is close to the distance along the meridian
+	b := BBox{MinX: 10, MinY: 50, MaxX: 12, MaxY: 55}
+	p := Point{X: 11, Y: 60}
+	if d := GeodesicDistance(p, Point{X: 11, Y: 55}); geodesicBoxDistance(p, b) < 0.99*d {
+		t.Errorf("geodesicBoxDistance(%v, %v), want close to %v got %v", p, b, d, geodesicBoxDistance(p, b))
+	}
+
+	if d := geodesicBoxDistance(Point{X: 11, Y: 52}, b); d != 0 {
+		t.Errorf("geodesicBoxDistance({11 52}, %v) inside the box, want 0 got %v", b, d)
+	}
+}
+
+func TestSpatialIndexNearestFeatures(t *testing.T) {
+	r := rand.New(rand.NewSource(2))
+
+	fc := &FeatureCollection{}
+	for range 300 {
+		p := Point{X: 10 + 10*r.Float64(), Y: 55 + 10*r.Float64()}
+		fc.Features = append(fc.Features, Feature{Type: "Point", Properties: map[string]any{}, Coordinates: p})
+	}
+
+	si := NewSpatialIndex(fc)
+
+	for range 20 {
+		p := Point{X: 5 + 20*r.Float64(), Y: 50 + 20*r.Float64()}
+
+		for _, opts := range []NearestOptions{{}, {Geodesic: true}} {
+			got, err := si.NearestFeatures(fc, p, 5, opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			dist := distance
+			if opts.Geodesic {
+				dist = GeodesicDistance
+			}
+
+			want := make([]float64, len(fc.Features))
+			for x := range fc.Features {
+				want[x] = dist(p, fc.Features[x].Coordinates.(Point))
+			}
+			slices.Sort(want)
+
+			if len(got) != 5 {
+				t.Fatalf("NearestFeatures(%v, 5), want 5 features got %d", p, len(got))
+			}
+
+			for x := range got {
+				if math.Abs(got[x].Distance-want[x]) > 1e-3 {
+					t.Errorf("NearestFeatures(%v, %+v)[%d], want distance %v got %v", p, opts, x, want[x], got[x].Distance)
+				}
+			}
+		}
+	}
+
+	other := &FeatureCollection{Features: fc.Features[:10]}
+	if _, err := si.NearestFeatures(other, Point{X: 30, Y: 70}, 300, NearestOptions{}); err == nil {
+		t.Error("NearestFeatures(other), want an error for an index of another collection")
+	}
+}
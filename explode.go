@@ -0,0 +1,160 @@
+package gegography
+
+// Explode returns one feature per part of a multi-part feature: the points of a MultiPoint, the lines of a
+// MultiLineString and the polygons of a MultiPolygon. Single-part features are returned as they are. The parts
+// share the properties of f.
+func (f *Feature) Explode() ([]Feature, error) {
+	switch f.Type {
+	case "Point", "LineString", "Polygon":
+		return []Feature{*f}, nil
+	case "MultiPoint":
+		mp := f.Coordinates.(MultiPoint)
+		out := make([]Feature, len(mp))
+		for x := range mp {
+			out[x] = Feature{Type: "Point", Properties: f.Properties, Coordinates: mp[x]}
+		}
+
+		return out, nil
+	case "MultiLineString":
+		lines := f.Coordinates.(Polygon)
+		out := make([]Feature, len(lines))
+		for x := range lines {
+			out[x] = Feature{Type: "LineString", Properties: f.Properties, Coordinates: lines[x]}
+		}
+
+		return out, nil
+	case "MultiPolygon":
+		mp := f.Coordinates.(MultiPolygon)
+		out := make([]Feature, len(mp))
+		for x := range mp {
+			out[x] = Feature{Type: "Polygon", Properties: f.Properties, Coordinates: mp[x]}
+		}
+
+		return out, nil
+	}
+
+	return nil, GeoTypeError{Type: f.Type}
+}
+
+// Explode returns a collection with one feature per part of every multi-part feature, as Feature.Explode. Each part
+// gets a copy of the properties of its feature and, if partIndex is not empty, a property of that name holding
+// its position within the feature, counted from 0. Multi-part features without parts are dropped.
+func (fc *FeatureCollection) Explode(partIndex string) (FeatureCollection, error) {
+	out := fc.emptyCopy()
+
+	for x := range fc.Features {
+		parts, err := fc.Features[x].Explode()
+		if err != nil {
+			return FeatureCollection{}, err
+		}
+
+		for i, p := range parts {
+			props := make(map[string]any, len(p.Properties)+1)
+			for k, v := range p.Properties {
+				props[k] = v
+			}
+
+			if partIndex != "" {
+				props[partIndex] = float64(i)
+			}
+
+			p.Properties = props
+			out.AddFeature(p)
+		}
+	}
+
+	return out, nil
+}
+
+// collectGroup gathers the parts of the features of one dimension in a group
+type collectGroup struct {
+	features featureGroup
+	points   MultiPoint
+	lines    Polygon
+	polygons MultiPolygon
+}
+
+// Collect combines the features sharing the same values for the properties in groupBy into one MultiPoint,
+// MultiLineString or MultiPolygon feature for each dimension found in the group, without merging the parts. The
+// output is in the order in which the groups first appear, and holds the multi-part type even when a group has a
+// single part. Output features hold the groupBy properties and the aggregates, computed over the features
+// collected into them. Without groupBy, all features of a dimension are collected into one.
+func (fc *FeatureCollection) Collect(groupBy []string, aggregates []PropertyAggregate) (FeatureCollection, error) {
+	out := fc.emptyCopy()
+
+	if err := validateAggregates(aggregates); err != nil {
+		return FeatureCollection{}, err
+	}
+
+	for _, features := range groupFeatures(fc, groupBy) {
+		order := make([]*collectGroup, 0)
+		groups := make(map[string]*collectGroup)
+
+		for _, f := range features {
+			var dim string
+			switch f.Type {
+			case "Point", "MultiPoint":
+				dim = "point"
+			case "LineString", "MultiLineString":
+				dim = "line"
+			case "Polygon", "MultiPolygon":
+				dim = "polygon"
+			default:
+				return FeatureCollection{}, GeoTypeError{Type: f.Type}
+			}
+
+			g, ok := groups[dim]
+			if !ok {
+				g = &collectGroup{}
+
+				switch dim {
+				case "point":
+					g.points = make(MultiPoint, 0)
+				case "line":
+					g.lines = make(Polygon, 0)
+				default:
+					g.polygons = make(MultiPolygon, 0)
+				}
+
+				groups[dim] = g
+				order = append(order, g)
+			}
+
+			g.features = append(g.features, f)
+
+			switch c := f.Coordinates.(type) {
+			case Point:
+				g.points = append(g.points, c)
+			case MultiPoint:
+				if f.Type == "LineString" {
+					g.lines = append(g.lines, c)
+				} else {
+					g.points = append(g.points, c...)
+				}
+			case Polygon:
+				if f.Type == "MultiLineString" {
+					g.lines = append(g.lines, c...)
+				} else {
+					g.polygons = append(g.polygons, c)
+				}
+			case MultiPolygon:
+				g.polygons = append(g.polygons, c...)
+			}
+		}
+
+		for _, g := range order {
+			props := g.features.properties(groupBy, aggregates)
+
+			switch {
+			case g.points != nil:
+				out.AddFeature(Feature{Type: "MultiPoint", Properties: props, Coordinates: g.points})
+			case g.lines != nil:
+				out.AddFeature(Feature{Type: "MultiLineString", Properties: props, Coordinates: g.lines})
+			default:
+				out.AddFeature(Feature{Type: "MultiPolygon", Properties: props, Coordinates: g.polygons})
+			}
+		}
+	}
+
+	return out, nil
+}
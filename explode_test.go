@@ -0,0 +1,165 @@
+package gegography
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestExplode(t *testing.T) {
+	tests := []struct {
+		wkt   string
+		parts []string
+	}{
+		{"POINT (1 2)", []string{"POINT (1 2)"}},
+		{"MULTIPOINT ((1 2), (3 4))", []string{"POINT (1 2)", "POINT (3 4)"}},
+		{"MULTILINESTRING ((0 0, 1 1))", []string{"LINESTRING (0 0, 1 1)"}},
+		{"MULTILINESTRING ((0 0, 1 1), (2 2, 3 3))", []string{"LINESTRING (0 0, 1 1)", "LINESTRING (2 2, 3 3)"}},
+		{
+			"MULTIPOLYGON (((0 0, 1 0, 1 1, 0 0)), ((5 5, 6 5, 6 6, 5 5)))",
+			[]string{"POLYGON ((0 0, 1 0, 1 1, 0 0))", "POLYGON ((5 5, 6 5, 6 6, 5 5))"},
+		},
+	}
+
+	for _, tt := range tests {
+		f := mustParseWKT(t, tt.wkt)
+		f.Properties["name"] = "road"
+
+		fc := FeatureCollection{Name: "roads", Features: []Feature{f}}
+
+		got, err := fc.Explode("part")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if got.Name != "roads" || len(got.Features) != len(tt.parts) {
+			t.Errorf("Explode(%s), want %d features in roads got %d in %q", tt.wkt, len(tt.parts), len(got.Features), got.Name)
+			continue
+		}
+
+		for x, want := range tt.parts {
+			g := got.Features[x]
+
+			if !sameGeometry(t, g, want) {
+				t.Errorf("Explode(%s), want part %d %s got %s %v", tt.wkt, x, want, g.Type, g.Coordinates)
+			}
+
+			if g.Properties["name"] != "road" || g.Properties["part"] != float64(x) {
+				t.Errorf("Explode(%s), want part %d with name road and part %d got %v", tt.wkt, x, x, g.Properties)
+			}
+		}
+
+		if _, ok := f.Properties["part"]; ok {
+			t.Errorf("Explode(%s), want the properties of the input unchanged got %v", tt.wkt, f.Properties)
+		}
+	}
+
+	bad := FeatureCollection{Features: []Feature{{Type: "Circle"}}}
+	if _, err := bad.Explode(""); err == nil {
+		t.Error("Explode() of a Circle, want an error for an unsupported geometry type")
+	}
+}
+
+func TestCollect(t *testing.T) {
+	fc := NewFeatureCollection()
+	for _, f := range []struct {
+		wkt  string
+		road string
+	}{
+		{"LINESTRING (0 0, 1 1)", "E4"},
+		{"POINT (5 5)", "E4"},
+		{"MULTILINESTRING ((2 2, 3 3), (4 4, 5 5))", "E4"},
+		{"LINESTRING (9 9, 8 8)", "E6"},
+		{"MULTIPOINT ((6 6), (7 7))", "E4"},
+		{"POLYGON ((0 0, 1 0, 1 1, 0 0))", "E6"},
+	} {
+		g := mustParseWKT(t, f.wkt)
+		g.Properties["road"] = f.road
+		g.Properties["lanes"] = 2.0
+		fc.AddFeature(g)
+	}
+
+	got, err := fc.Collect([]string{"road"}, []PropertyAggregate{{Func: AggregateCount}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []struct {
+		wkt   string
+		road  string
+		count float64
+	}{
+		{"MULTILINESTRING ((0 0, 1 1), (2 2, 3 3), (4 4, 5 5))", "E4", 2},
+		{"MULTIPOINT ((5 5), (6 6), (7 7))", "E4", 2},
+		{"MULTILINESTRING ((9 9, 8 8))", "E6", 1},
+		{"MULTIPOLYGON (((0 0, 1 0, 1 1, 0 0)))", "E6", 1},
+	}
+
+	if len(got.Features) != len(want) {
+		t.Fatalf("Collect(road), want %d features got %d", len(want), len(got.Features))
+	}
+
+	for x, w := range want {
+		g := got.Features[x]
+		// only the key and the aggregates are kept
+		if !sameGeometry(t, g, w.wkt) || g.Properties["road"] != w.road || g.Properties["count"] != w.count ||
+			len(g.Properties) != 2 {
+			t.Errorf("Collect(road), want feature %d %s on %s got %s %v %v", x, w.wkt, w.road, g.Type, g.Coordinates, g.Properties)
+		}
+	}
+
+	all, err := fc.Collect(nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(all.Features) != 3 || len(all.Features[0].Properties) != 0 {
+		t.Errorf("Collect(nil), want one feature per dimension without properties got %v", all.Features)
+	}
+
+	if _, err := fc.Collect(nil, []PropertyAggregate{{Func: AggregateList}}); err == nil {
+		t.Error("Collect(nil, AggregateList), want an error for a list without a property")
+	}
+}
+
+func TestExplodeCollectRoundTrip(t *testing.T) {
+	f := mustParseWKT(t, "MULTIPOLYGON (((0 0, 1 0, 1 1, 0 0)), ((5 5, 6 5, 6 6, 5 5)))")
+	f.Properties["id"] = 7.0
+
+	fc := FeatureCollection{Features: []Feature{f}}
+
+	parts, err := fc.Explode("")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	back, err := parts.Collect([]string{"id"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(back.Features) != 1 || !reflect.DeepEqual(back.Features[0].Coordinates, f.Coordinates) ||
+		back.Features[0].Properties["id"] != 7.0 {
+		t.Errorf("Collect(Explode()), want %v got %v", f, back.Features)
+	}
+
+	// a single-part MultiLineString, as read from a shapefile, becomes a LineString
+	line := mustParseWKT(t, "MULTILINESTRING ((0 0, 1 1, 2 0))")
+	single, err := (&FeatureCollection{Features: []Feature{line}}).Explode("")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if wkt, _ := single.Features[0].ToWKT(); !strings.HasPrefix(wkt, "LINESTRING (") {
+		t.Errorf("Explode() of a single-part MultiLineString, want a LINESTRING got %s", wkt)
+	}
+}
+
+// sameGeometry reports whether a feature has the type and coordinates of a WKT geometry
+func sameGeometry(t *testing.T, f Feature, wkt string) bool {
+	t.Helper()
+
+	want := mustParseWKT(t, wkt)
+
+	return f.Type == want.Type && reflect.DeepEqual(f.Coordinates, want.Coordinates)
+}
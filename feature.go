@@ -38,6 +38,9 @@ type Feature struct {
 	Type        string
 	Properties  map[string]any
 	Coordinates any
+	// Measures holds the M value of every point of each line of a MultiLineString read from a PolyLineM shapefile,
+	// and is nil for features without measures. Operations building new features do not keep it.
+	Measures [][]float64
 }
 
 // FeatureCollection represents a collection of geographical features and accompanying information
@@ -73,6 +76,15 @@ func NewFeatureCollection() FeatureCollection {
 	return fc
 }
 
+// emptyCopy returns a new FeatureCollection without features, with the name and coordinate reference system of fc
+func (fc *FeatureCollection) emptyCopy() FeatureCollection {
+	out := NewFeatureCollection()
+	out.Name = fc.Name
+	out.CoordinateReferenceSystem = fc.CoordinateReferenceSystem
+
+	return out
+}
+
 // AddFeature adds a feature to a FeatureCollection
 func (fc *FeatureCollection) AddFeature(f Feature) {
 	fc.Features = append(fc.Features, f)
@@ -0,0 +1,372 @@
+package gegography
+
+import "math"
+
+// Ellipsoid describes a reference ellipsoid by its semi-major axis A (in metres) and its flattening F
+type Ellipsoid struct {
+	Name string
+	A    float64
+	F    float64
+}
+
+// Common reference ellipsoids
+var (
+	WGS84Ellipsoid = Ellipsoid{Name: "WGS 84", A: 6378137, F: 1 / 298.257223563}
+	GRS80Ellipsoid = Ellipsoid{Name: "GRS 1980", A: 6378137, F: 1 / 298.257222101}
+)
+
+// meanEarthRadius is the mean radius of the WGS84 ellipsoid in metres, used for spherical approximations
+const meanEarthRadius = 6371008.8
+
+// B returns the semi-minor axis of the ellipsoid
+func (e Ellipsoid) B() float64 {
+	return e.A * (1 - e.F)
+}
+
+// E2 returns the square of the first eccentricity of the ellipsoid
+func (e Ellipsoid) E2() float64 {
+	return e.F * (2 - e.F)
+}
+
+func toRadians(d float64) float64 {
+	return d * math.Pi / 180
+}
+
+func toDegrees(r float64) float64 {
+	return r * 180 / math.Pi
+}
+
+// normalizeBearing returns a bearing in degrees within [0, 360)
+func normalizeBearing(b float64) float64 {
+	b = math.Mod(b, 360)
+	if b < 0 {
+		b += 360
+	}
+
+	return b
+}
+
+// Inverse solves the inverse geodesic problem between two longitude/latitude points (in degrees) using
+// Vincenty's formulae. It returns the distance in metres and the initial and final bearings in degrees.
+// For nearly antipodal points, where Vincenty's method does not converge, a spherical approximation is returned.
+func (e Ellipsoid) Inverse(p1, p2 Point) (dist float64, initialBearing float64, finalBearing float64) {
+	a, f, b := e.A, e.F, e.B()
+
+	L := toRadians(p2.X - p1.X)
+	U1 := math.Atan((1 - f) * math.Tan(toRadians(p1.Y)))
+	U2 := math.Atan((1 - f) * math.Tan(toRadians(p2.Y)))
+	sinU1, cosU1 := math.Sincos(U1)
+	sinU2, cosU2 := math.Sincos(U2)
+
+	lambda := L
+	var sinSigma, cosSigma, sigma, cos2Alpha, cos2SigmaM, sinLambda, cosLambda float64
+	converged := false
+
+	for range 200 {
+		sinLambda, cosLambda = math.Sincos(lambda)
+
+		sinSigma = math.Hypot(cosU2*sinLambda, cosU1*sinU2-sinU1*cosU2*cosLambda)
+		if sinSigma == 0 {
+			return 0, 0, 0
+		}
+
+		cosSigma = sinU1*sinU2 + cosU1*cosU2*cosLambda
+		sigma = math.Atan2(sinSigma, cosSigma)
+
+		sinAlpha := cosU1 * cosU2 * sinLambda / sinSigma
+		cos2Alpha = 1 - sinAlpha*sinAlpha
+
+		cos2SigmaM = 0
+		if cos2Alpha != 0 {
+			cos2SigmaM = cosSigma - 2*sinU1*sinU2/cos2Alpha
+		}
+
+		C := f / 16 * cos2Alpha * (4 + f*(4-3*cos2Alpha))
+		prev := lambda
+		lambda = L + (1-C)*f*sinAlpha*(sigma+C*sinSigma*(cos2SigmaM+C*cosSigma*(-1+2*cos2SigmaM*cos2SigmaM)))
+
+		if math.Abs(lambda) > math.Pi {
+			break
+		}
+
+		if math.Abs(lambda-prev) < 1e-12 {
+			converged = true
+			break
+		}
+	}
+
+	if !converged {
+		return HaversineDistance(p1, p2), sphericalBearing(p1, p2), normalizeBearing(sphericalBearing(p2, p1) + 180)
+	}
+
+	u2 := cos2Alpha * (a*a - b*b) / (b * b)
+	A := 1 + u2/16384*(4096+u2*(-768+u2*(320-175*u2)))
+	B := u2 / 1024 * (256 + u2*(-128+u2*(74-47*u2)))
+	deltaSigma := B * sinSigma * (cos2SigmaM + B/4*(cosSigma*(-1+2*cos2SigmaM*cos2SigmaM)-
+		B/6*cos2SigmaM*(-3+4*sinSigma*sinSigma)*(-3+4*cos2SigmaM*cos2SigmaM)))
+
+	dist = b * A * (sigma - deltaSigma)
+	initialBearing = toDegrees(math.Atan2(cosU2*sinLambda, cosU1*sinU2-sinU1*cosU2*cosLambda))
+	finalBearing = toDegrees(math.Atan2(cosU1*sinLambda, -sinU1*cosU2+cosU1*sinU2*cosLambda))
+
+	return dist, normalizeBearing(initialBearing), normalizeBearing(finalBearing)
+}
+
+// Direct solves the direct geodesic problem using Vincenty's formulae, returning the point reached by travelling
+// dist metres from p along the initial bearing (in degrees), and the final bearing at that point
+func (e Ellipsoid) Direct(p Point, bearing float64, dist float64) (Point, float64) {
+	a, f, b := e.A, e.F, e.B()
+
+	sinAlpha1, cosAlpha1 := math.Sincos(toRadians(bearing))
+	U1 := math.Atan((1 - f) * math.Tan(toRadians(p.Y)))
+	sinU1, cosU1 := math.Sincos(U1)
+	sigma1 := math.Atan2(math.Tan(U1), cosAlpha1)
+
+	sinAlpha := cosU1 * sinAlpha1
+	cos2Alpha := 1 - sinAlpha*sinAlpha
+	u2 := cos2Alpha * (a*a - b*b) / (b * b)
+	A := 1 + u2/16384*(4096+u2*(-768+u2*(320-175*u2)))
+	B := u2 / 1024 * (256 + u2*(-128+u2*(74-47*u2)))
+
+	sigma := dist / (b * A)
+	var sinSigma, cosSigma, cos2SigmaM float64
+
+	for range 200 {
+		cos2SigmaM = math.Cos(2*sigma1 + sigma)
+		sinSigma, cosSigma = math.Sincos(sigma)
+
+		deltaSigma := B * sinSigma * (cos2SigmaM + B/4*(cosSigma*(-1+2*cos2SigmaM*cos2SigmaM)-
+			B/6*cos2SigmaM*(-3+4*sinSigma*sinSigma)*(-3+4*cos2SigmaM*cos2SigmaM)))
+
+		prev := sigma
+		sigma = dist/(b*A) + deltaSigma
+
+		if math.Abs(sigma-prev) < 1e-12 {
+			break
+		}
+	}
+
+	cos2SigmaM = math.Cos(2*sigma1 + sigma)
+	sinSigma, cosSigma = math.Sincos(sigma)
+
+	tmp := sinU1*sinSigma - cosU1*cosSigma*cosAlpha1
+	lat := math.Atan2(sinU1*cosSigma+cosU1*sinSigma*cosAlpha1, (1-f)*math.Hypot(sinAlpha, tmp))
+	lambda := math.Atan2(sinSigma*sinAlpha1, cosU1*cosSigma-sinU1*sinSigma*cosAlpha1)
+	C := f / 16 * cos2Alpha * (4 + f*(4-3*cos2Alpha))
+	L := lambda - (1-C)*f*sinAlpha*(sigma+C*sinSigma*(cos2SigmaM+C*cosSigma*(-1+2*cos2SigmaM*cos2SigmaM)))
+
+	lon := p.X + toDegrees(L)
+	lon += wrapShift(lon)
+
+	return Point{X: lon, Y: toDegrees(lat)}, normalizeBearing(toDegrees(math.Atan2(sinAlpha, -tmp)))
+}
+
+// GeodesicDistance returns the distance in metres between two longitude/latitude points on the WGS84 ellipsoid
+func GeodesicDistance(p1, p2 Point) float64 {
+	d, _, _ := WGS84Ellipsoid.Inverse(p1, p2)
+	return d
+}
+
+// InitialBearing returns the bearing in degrees, clockwise from north, at which the geodesic from p1 to p2 starts
+func InitialBearing(p1, p2 Point) float64 {
+	_, b, _ := WGS84Ellipsoid.Inverse(p1, p2)
+	return b
+}
+
+// FinalBearing returns the bearing in degrees, clockwise from north, at which the geodesic from p1 arrives at p2
+func FinalBearing(p1, p2 Point) float64 {
+	_, _, b := WGS84Ellipsoid.Inverse(p1, p2)
+	return b
+}
+
+// DestinationPoint returns the point reached by travelling dist metres from p along the bearing (in degrees)
+// on the WGS84 ellipsoid
+func DestinationPoint(p Point, bearing float64, dist float64) Point {
+	d, _ := WGS84Ellipsoid.Direct(p, bearing, dist)
+	return d
+}
+
+// HaversineDistance returns the great-circle distance in metres between two longitude/latitude points on a
+// sphere with the mean radius of the earth. It is faster but less accurate (up to about 0.5%) than GeodesicDistance.
+func HaversineDistance(p1, p2 Point) float64 {
+	lat1 := toRadians(p1.Y)
+	lat2 := toRadians(p2.Y)
+	dLat := lat2 - lat1
+	dLon := toRadians(p2.X - p1.X)
+
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLon/2)*math.Sin(dLon/2)
+
+	return 2 * meanEarthRadius * math.Asin(math.Min(1, math.Sqrt(h)))
+}
+
+// sphericalBearing returns the initial great-circle bearing from p1 to p2 in degrees
+func sphericalBearing(p1, p2 Point) float64 {
+	lat1 := toRadians(p1.Y)
+	lat2 := toRadians(p2.Y)
+	dLon := toRadians(p2.X - p1.X)
+
+	y := math.Sin(dLon) * math.Cos(lat2)
+	x := math.Cos(lat1)*math.Sin(lat2) - math.Sin(lat1)*math.Cos(lat2)*math.Cos(dLon)
+
+	return normalizeBearing(toDegrees(math.Atan2(y, x)))
+}
+
+// GeodesicLength returns the length in metres of a longitude/latitude line on the WGS84 ellipsoid
+func (ls LineString) GeodesicLength() float64 {
+	var l float64
+
+	for x := 1; x < len(ls); x++ {
+		l += GeodesicDistance(ls[x-1], ls[x])
+	}
+
+	return l
+}
+
+// authalicLatitude returns the latitude (in radians) on the sphere with the same surface area as the ellipsoid
+// which divides the surface in the same proportions as the geodetic latitude phi
+func (e Ellipsoid) authalicLatitude(phi float64) float64 {
+	if e.F == 0 {
+		return phi
+	}
+
+	return math.Asin(math.Max(-1, math.Min(1, e.q(math.Sin(phi))/e.q(1))))
+}
+
+func (e Ellipsoid) q(sinPhi float64) float64 {
+	e2 := e.E2()
+	ec := math.Sqrt(e2)
+	es := ec * sinPhi
+
+	return (1 - e2) * (sinPhi/(1-es*es) - math.Log((1-es)/(1+es))/(2*ec))
+}
+
+// authalicRadius returns the radius of the sphere with the same surface area as the ellipsoid
+func (e Ellipsoid) authalicRadius() float64 {
+	if e.F == 0 {
+		return e.A
+	}
+
+	return e.A * math.Sqrt(e.q(1)/2)
+}
+
+// ringArea returns the signed area in square metres of a longitude/latitude ring, computed on the authalic sphere.
+// Counter-clockwise rings have positive area.
+func (e Ellipsoid) ringArea(r []Point) float64 {
+	n := len(r)
+	if n < 3 {
+		return 0
+	}
+
+	var excess float64
+
+	for x := range n {
+		p1 := r[x]
+		p2 := r[(x+1)%n]
+
+		dLon := toRadians(p2.X - p1.X)
+		dLon -= 2 * math.Pi * math.Round(dLon/(2*math.Pi))
+
+		t1 := math.Tan(e.authalicLatitude(toRadians(p1.Y)) / 2)
+		t2 := math.Tan(e.authalicLatitude(toRadians(p2.Y)) / 2)
+
+		excess += 2 * math.Atan2(math.Tan(dLon/2)*(t1+t2), 1+t1*t2)
+	}
+
+	rq := e.authalicRadius()
+
+	return excess * rq * rq
+}
+
+// GeodesicArea returns the area in square metres of a longitude/latitude polygon on the WGS84 ellipsoid, with
+// the area of its holes subtracted. Edges are treated as great circles on the sphere with the same area as the
+// ellipsoid, which is accurate to well within 0.1% for polygons of the size of countries.
+func (p Polygon) GeodesicArea() float64 {
+	var a float64
+
+	for x := range p {
+		ra := math.Abs(WGS84Ellipsoid.ringArea(p[x]))
+		if x == 0 {
+			a += ra
+		} else {
+			a -= ra
+		}
+	}
+
+	return a
+}
+
+// GeodesicPerimeter returns the total length in metres of all rings of a longitude/latitude polygon
+func (p Polygon) GeodesicPerimeter() float64 {
+	var l float64
+
+	for x := range p {
+		l += LineString(closeRing(p[x])).GeodesicLength()
+	}
+
+	return l
+}
+
+// GeodesicArea returns the area in square metres of all longitude/latitude polygons on the WGS84 ellipsoid
+func (mp MultiPolygon) GeodesicArea() float64 {
+	var a float64
+
+	for x := range mp {
+		a += mp[x].GeodesicArea()
+	}
+
+	return a
+}
+
+// GeodesicPerimeter returns the total length in metres of all rings of all longitude/latitude polygons
+func (mp MultiPolygon) GeodesicPerimeter() float64 {
+	var l float64
+
+	for x := range mp {
+		l += mp[x].GeodesicPerimeter()
+	}
+
+	return l
+}
+
+// GeodesicArea returns the area in square metres of a longitude/latitude Polygon or MultiPolygon feature, and 0
+// for other geometry types
+func (f *Feature) GeodesicArea() float64 {
+	switch f.Type {
+	case "Polygon":
+		return f.Coordinates.(Polygon).GeodesicArea()
+	case "MultiPolygon":
+		return f.Coordinates.(MultiPolygon).GeodesicArea()
+	}
+
+	return 0
+}
+
+// GeodesicLength returns the length in metres of a longitude/latitude LineString or MultiLineString feature, and 0
+// for other geometry types
+func (f *Feature) GeodesicLength() float64 {
+	switch f.Type {
+	case "LineString":
+		return LineString(f.Coordinates.(MultiPoint)).GeodesicLength()
+	case "MultiLineString":
+		var l float64
+		for _, ls := range f.Coordinates.(Polygon) {
+			l += LineString(ls).GeodesicLength()
+		}
+		return l
+	}
+
+	return 0
+}
+
+// GeodesicPerimeter returns the length in metres of the boundary of a longitude/latitude Polygon or MultiPolygon
+// feature, and 0 for other geometry types
+func (f *Feature) GeodesicPerimeter() float64 {
+	switch f.Type {
+	case "Polygon":
+		return f.Coordinates.(Polygon).GeodesicPerimeter()
+	case "MultiPolygon":
+		return f.Coordinates.(MultiPolygon).GeodesicPerimeter()
+	}
+
+	return 0
+}
@@ -0,0 +1,55 @@
+package gegography
+
+import (
+	"math"
+	"testing"
+)
+
+func dms(d, m, s float64) float64 {
+	if d < 0 {
+		return d - m/60 - s/3600
+	}
+
+	return d + m/60 + s/3600
+}
+
+func TestGeodesicInverseAndDirect(t *testing.T) {
+	// Flinders Peak to Buninyong, the classic test case from Vincenty (1975)
+	flinders := Point{X: dms(144, 25, 29.52440), Y: dms(-37, 57, 3.72030)}
+	buninyong := Point{X: dms(143, 55, 35.38390), Y: dms(-37, 39, 10.15610)}
+
+	d, initial, final := GRS80Ellipsoid.Inverse(flinders, buninyong)
+
+	if math.Abs(d-54972.271) > 0.001 {
+		t.Errorf("Inverse(), want distance 54972.271 got %.3f", d)
+	}
+
+	if math.Abs(initial-dms(306, 52, 5.37)) > 1e-5 || math.Abs(final-dms(307, 10, 25.07)) > 1e-5 {
+		t.Errorf("Inverse(), want bearings 306°52'05.37\" and 307°10'25.07\" got %v and %v", initial, final)
+	}
+
+	p, b := GRS80Ellipsoid.Direct(flinders, initial, d)
+	if math.Abs(p.X-buninyong.X) > 1e-8 || math.Abs(p.Y-buninyong.Y) > 1e-8 || math.Abs(b-final) > 1e-6 {
+		t.Errorf("Direct(), want %v (bearing %v) got %v (bearing %v)", buninyong, final, p, b)
+	}
+
+	if h := HaversineDistance(flinders, buninyong); math.Abs(h-d)/d > 0.005 {
+		t.Errorf("HaversineDistance(), want approximately %v got %v", d, h)
+	}
+}
+
+func TestGeodesicArea(t *testing.T) {
+	f, err := ParseWKT("POLYGON ((0 0, 1 0, 1 1, 0 1, 0 0))")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// reference value computed with GeographicLib
+	if a := f.GeodesicArea(); math.Abs(a-12308778361.469)/a > 1e-4 {
+		t.Errorf("GeodesicArea(), want approximately 12308778361 got %v", a)
+	}
+
+	if p := f.GeodesicPerimeter(); math.Abs(p-443770.917) > 1 {
+		t.Errorf("GeodesicPerimeter(), want approximately 443770.917 got %v", p)
+	}
+}
@@ -11,6 +11,7 @@ type geoJSONGeometry struct {
 
 type geoJSONFeature struct {
 	Type       string          `json:"type"`
+	BBox       []float64       `json:"bbox,omitempty"`
 	Properties map[string]any  `json:"properties,omitempty"`
 	Geometry   geoJSONGeometry `json:"geometry"`
 }
@@ -19,6 +20,7 @@ type geoJSON struct {
 	Type                      string           `json:"type"`
 	Name                      string           `json:"name,omitempty"`
 	CoordinateReferenceSystem *CRS             `json:"crs,omitempty"`
+	BBox                      []float64        `json:"bbox,omitempty"`
 	Features                  []geoJSONFeature `json:"features"`
 }
 
@@ -262,6 +264,7 @@ func LoadGeoJSON(input []byte) (FeatureCollection, error) {
 	var fc FeatureCollection
 	fc.Features = make([]Feature, 0)
 	fc.Name = gj.Name
+	fc.CoordinateReferenceSystem = gj.CoordinateReferenceSystem
 
 	var coordinates any
 	var err error
@@ -311,3 +314,132 @@ func LoadGeoJSON(input []byte) (FeatureCollection, error) {
 
 	return fc, nil
 }
+
+// RFC7946Options configures GeoJSON output conforming to RFC 7946
+type RFC7946Options struct {
+	// Reproject, if set, is applied to every coordinate before it is written. RFC 7946 requires WGS84
+	// longitude/latitude, so data in any other coordinate reference system must be reprojected.
+	Reproject func(Point) (Point, error)
+	// BBox adds bbox members to the collection and every feature
+	BBox bool
+}
+
+// toRFC7946 returns a copy of a feature reprojected, split at the antimeridian and with its polygon rings
+// wound according to the right-hand rule (counter-clockwise shells, clockwise holes)
+func (f *Feature) toRFC7946(opts RFC7946Options) (Feature, error) {
+	out := *f
+
+	if opts.Reproject != nil {
+		var err error
+		if out, err = f.transformed(opts.Reproject); err != nil {
+			return Feature{}, err
+		}
+	}
+
+	if err := out.SplitAntimeridian(); err != nil {
+		return Feature{}, err
+	}
+
+	switch out.Type {
+	case "Polygon":
+		out.Coordinates = out.Coordinates.(Polygon).rewind()
+	case "MultiPolygon":
+		out.Coordinates = out.Coordinates.(MultiPolygon).rewind()
+	}
+
+	return out, nil
+}
+
+// rewind returns a copy of the polygon with a counter-clockwise shell and clockwise holes
+func (p Polygon) rewind() Polygon {
+	out := make(Polygon, 0, len(p))
+
+	for x := range p {
+		out = append(out, orientRing(p[x], x == 0))
+	}
+
+	return out
+}
+
+// rewind returns a copy of the multipolygon with counter-clockwise shells and clockwise holes
+func (mp MultiPolygon) rewind() MultiPolygon {
+	out := make(MultiPolygon, 0, len(mp))
+
+	for x := range mp {
+		out = append(out, mp[x].rewind())
+	}
+
+	return out
+}
+
+// ToRFC7946GeoJSON exports a Feature to a byte array containing JSON conforming to RFC 7946
+func (f *Feature) ToRFC7946GeoJSON(opts RFC7946Options) ([]byte, error) {
+	rf, err := f.toRFC7946(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	gjf, err := rf.toGeoJSONFeature()
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.BBox {
+		gjf.BBox = rf.GeographicBounds().toGeoJSON()
+	}
+
+	return json.Marshal(gjf)
+}
+
+func (fc *FeatureCollection) toRFC7946GeoJSONStruct(opts RFC7946Options) (geoJSON, error) {
+	rfc := FeatureCollection{Name: fc.Name, Features: make([]Feature, 0, len(fc.Features))}
+
+	for x := range fc.Features {
+		rf, err := fc.Features[x].toRFC7946(opts)
+		if err != nil {
+			return geoJSON{}, err
+		}
+
+		rfc.AddFeature(rf)
+	}
+
+	gj, err := rfc.toGeoJSONStruct()
+	if err != nil {
+		return geoJSON{}, err
+	}
+
+	if gj.Features == nil {
+		gj.Features = make([]geoJSONFeature, 0)
+	}
+
+	if opts.BBox {
+		gj.BBox = rfc.GeographicBounds().toGeoJSON()
+
+		for x := range gj.Features {
+			gj.Features[x].BBox = rfc.Features[x].GeographicBounds().toGeoJSON()
+		}
+	}
+
+	return gj, nil
+}
+
+// ToRFC7946GeoJSON exports a FeatureCollection to a byte array containing JSON conforming to RFC 7946. Unlike
+// ToGeoJSON the obsolete crs member is never written.
+func (fc *FeatureCollection) ToRFC7946GeoJSON(opts RFC7946Options) ([]byte, error) {
+	gj, err := fc.toRFC7946GeoJSONStruct(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(gj)
+}
+
+// ToPrettyRFC7946GeoJSON exports a FeatureCollection to a byte array containing indented JSON conforming to RFC 7946
+func (fc *FeatureCollection) ToPrettyRFC7946GeoJSON(opts RFC7946Options) ([]byte, error) {
+	gj, err := fc.toRFC7946GeoJSONStruct(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.MarshalIndent(gj, "", "\t")
+}
@@ -0,0 +1,111 @@
+package gegography
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestToRFC7946GeoJSON(t *testing.T) {
+	fc := NewFeatureCollection()
+	fc.CoordinateReferenceSystem = &CRS{Type: "name", Properties: CRSProperties{Name: "urn:ogc:def:crs:OGC:1.3:CRS84"}}
+
+	fiji, err := ParseWKT("POLYGON ((177 -16, 177 -19, -179 -19, -179 -16, 177 -16))")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fc.AddFeature(fiji)
+
+	route, err := ParseWKT("LINESTRING (170 10, -170 20)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fc.AddFeature(route)
+
+	gj, err := fc.ToRFC7946GeoJSON(RFC7946Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if bytes.Contains(gj, []byte(`"crs"`)) {
+		t.Error("ToRFC7946GeoJSON(), output should not contain a crs member")
+	}
+
+	out, err := LoadGeoJSON(gj)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if out.Features[0].Type != "MultiPolygon" || len(out.Features[0].Coordinates.(MultiPolygon)) != 2 {
+		t.Fatalf("ToRFC7946GeoJSON(), want polygon crossing the antimeridian split into two parts got %v", out.Features[0].Coordinates)
+	}
+
+	for _, p := range out.Features[0].Coordinates.(MultiPolygon) {
+		if !isCCW(p[0]) {
+			t.Errorf("ToRFC7946GeoJSON(), want counter-clockwise shell got %v", p[0])
+		}
+
+		for _, pt := range p[0] {
+			if pt.X < -180 || pt.X > 180 {
+				t.Errorf("ToRFC7946GeoJSON(), want longitudes within [-180, 180] got %v", pt.X)
+			}
+		}
+	}
+
+	lines := out.Features[1].Coordinates.(Polygon)
+	if out.Features[1].Type != "MultiLineString" || len(lines) != 2 || lines[0][1] != (Point{X: 180, Y: 15}) || lines[1][0] != (Point{X: -180, Y: 15}) {
+		t.Errorf("ToRFC7946GeoJSON(), want line split at the antimeridian got %v", lines)
+	}
+}
+
+func TestRFC7946GeoJSONBBox(t *testing.T) {
+	fc, err := ReadShapefile("test_data/test_shapefile.shp")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p := fc.Features[0].Coordinates.(Point)
+	if b := fc.Bounds(); b.MinX != p.X || b.MaxX != p.X || b.MinY != p.Y || b.MaxY != p.Y {
+		t.Errorf("FeatureCollection.Bounds(), want %v got %v", p, b)
+	}
+
+	gj, err := fc.ToRFC7946GeoJSON(RFC7946Options{BBox: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if bytes.Count(gj, []byte(`"bbox"`)) != 2 {
+		t.Errorf("ToRFC7946GeoJSON(RFC7946Options{BBox: true}), want bbox members on collection and feature got %s", gj)
+	}
+}
+
+func TestLoadGeoJSONCRS(t *testing.T) {
+	tests := []struct {
+		name string
+		json string
+		want string
+	}{
+		{
+			name: "with crs",
+			json: `{"type": "FeatureCollection", "name": "wells", "crs": {"type": "name", "properties": {"name": "urn:ogc:def:crs:EPSG::3006"}}, "features": []}`,
+			want: "urn:ogc:def:crs:EPSG::3006",
+		},
+		{
+			name: "without crs",
+			json: `{"type": "FeatureCollection", "name": "wells", "features": []}`,
+		},
+	}
+
+	for _, tt := range tests {
+		fc, err := LoadGeoJSON([]byte(tt.json))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		switch {
+		case tt.want == "" && fc.CoordinateReferenceSystem != nil:
+			t.Errorf("LoadGeoJSON() %s, want no CRS got %v", tt.name, fc.CoordinateReferenceSystem)
+		case tt.want != "" && (fc.CoordinateReferenceSystem == nil || fc.CoordinateReferenceSystem.Properties.Name != tt.want):
+			t.Errorf("LoadGeoJSON() %s, want CRS %s got %v", tt.name, tt.want, fc.CoordinateReferenceSystem)
+		}
+	}
+}
@@ -0,0 +1,385 @@
+package gegography
+
+import (
+	"math"
+	"slices"
+	"sort"
+)
+
+// location describes where a point lies relative to a geometry
+type location int
+
+const (
+	locExterior location = iota
+	locBoundary
+	locInterior
+)
+
+// cross returns the z-component of the cross product of the vectors o->a and o->b
+func cross(o, a, b Point) float64 {
+	return (a.X-o.X)*(b.Y-o.Y) - (a.Y-o.Y)*(b.X-o.X)
+}
+
+// orientation returns 1 if a, b and c turn counter-clockwise, -1 if they turn clockwise and 0 if they are collinear
+func orientation(a, b, c Point) int {
+	v := cross(a, b, c)
+
+	if v > 0 {
+		return 1
+	} else if v < 0 {
+		return -1
+	}
+
+	return 0
+}
+
+func distance(a, b Point) float64 {
+	return math.Hypot(b.X-a.X, b.Y-a.Y)
+}
+
+func (p Point) equals(o Point) bool {
+	return p.X == o.X && p.Y == o.Y
+}
+
+func (p Point) isFinite() bool {
+	return !math.IsNaN(p.X) && !math.IsNaN(p.Y) && !math.IsInf(p.X, 0) && !math.IsInf(p.Y, 0)
+}
+
+// inSegmentBox reports whether p lies within the bounding box of the segment a-b
+func inSegmentBox(p, a, b Point) bool {
+	return p.X >= math.Min(a.X, b.X) && p.X <= math.Max(a.X, b.X) &&
+		p.Y >= math.Min(a.Y, b.Y) && p.Y <= math.Max(a.Y, b.Y)
+}
+
+// onSegment reports whether p lies on the closed segment a-b
+func onSegment(p, a, b Point) bool {
+	return orientation(a, b, p) == 0 && inSegmentBox(p, a, b)
+}
+
+// segmentIntersectionKind describes how two segments intersect
+type segmentIntersectionKind int
+
+const (
+	noIntersection segmentIntersectionKind = iota
+	pointIntersection
+	collinearIntersection
+)
+
+// segmentIntersection describes the intersection of two segments. For point intersections
+// Points holds one point, for collinear intersections it holds the two end points of the overlap.
+type segmentIntersection struct {
+	Kind   segmentIntersectionKind
+	Proper bool
+	Points []Point
+}
+
+// intersectSegments computes the intersection of the segments a1-a2 and b1-b2
+func intersectSegments(a1, a2, b1, b2 Point) segmentIntersection {
+	if math.Max(a1.X, a2.X) < math.Min(b1.X, b2.X) || math.Max(b1.X, b2.X) < math.Min(a1.X, a2.X) ||
+		math.Max(a1.Y, a2.Y) < math.Min(b1.Y, b2.Y) || math.Max(b1.Y, b2.Y) < math.Min(a1.Y, a2.Y) {
+		return segmentIntersection{}
+	}
+
+	o1 := orientation(a1, a2, b1)
+	o2 := orientation(a1, a2, b2)
+	o3 := orientation(b1, b2, a1)
+	o4 := orientation(b1, b2, a2)
+
+	if o1 == 0 && o2 == 0 {
+		return collinearSegmentIntersection(a1, a2, b1, b2)
+	}
+
+	if o1*o2 > 0 || o3*o4 > 0 {
+		return segmentIntersection{}
+	}
+
+	switch {
+	case o1 == 0:
+		return segmentIntersection{Kind: pointIntersection, Points: []Point{b1}}
+	case o2 == 0:
+		return segmentIntersection{Kind: pointIntersection, Points: []Point{b2}}
+	case o3 == 0:
+		return segmentIntersection{Kind: pointIntersection, Points: []Point{a1}}
+	case o4 == 0:
+		return segmentIntersection{Kind: pointIntersection, Points: []Point{a2}}
+	}
+
+	return segmentIntersection{Kind: pointIntersection, Proper: true, Points: []Point{lineIntersection(a1, a2, b1, b2)}}
+}
+
+func collinearSegmentIntersection(a1, a2, b1, b2 Point) segmentIntersection {
+	pts := make([]Point, 0, 2)
+
+	add := func(p Point) {
+		for x := range pts {
+			if pts[x].equals(p) {
+				return
+			}
+		}
+		pts = append(pts, p)
+	}
+
+	if inSegmentBox(b1, a1, a2) {
+		add(b1)
+	}
+	if inSegmentBox(b2, a1, a2) {
+		add(b2)
+	}
+	if inSegmentBox(a1, b1, b2) {
+		add(a1)
+	}
+	if inSegmentBox(a2, b1, b2) {
+		add(a2)
+	}
+
+	switch len(pts) {
+	case 0:
+		return segmentIntersection{}
+	case 1:
+		return segmentIntersection{Kind: pointIntersection, Points: pts}
+	}
+
+	return segmentIntersection{Kind: collinearIntersection, Points: pts[:2]}
+}
+
+// lineIntersection returns the intersection point of the infinite lines through a1-a2 and b1-b2
+func lineIntersection(a1, a2, b1, b2 Point) Point {
+	d := (a2.X-a1.X)*(b2.Y-b1.Y) - (a2.Y-a1.Y)*(b2.X-b1.X)
+	if d == 0 {
+		return a1
+	}
+
+	t := ((b1.X-a1.X)*(b2.Y-b1.Y) - (b1.Y-a1.Y)*(b2.X-b1.X)) / d
+
+	return Point{X: a1.X + t*(a2.X-a1.X), Y: a1.Y + t*(a2.Y-a1.Y)}
+}
+
+// isClosed reports whether the first and last points of a ring are equal
+func isClosed(r []Point) bool {
+	return len(r) > 0 && r[0].equals(r[len(r)-1])
+}
+
+// closeRing returns the ring with the first point appended to the end if it is not already closed
+func closeRing(r []Point) MultiPoint {
+	out := make(MultiPoint, 0, len(r)+1)
+	out = append(out, r...)
+
+	if len(r) > 0 && !isClosed(r) {
+		out = append(out, r[0])
+	}
+
+	return out
+}
+
+// signedRingArea returns the signed area of a ring, positive for counter-clockwise rings
+func signedRingArea(r []Point) float64 {
+	n := len(r)
+	if n < 3 {
+		return 0
+	}
+
+	var sum float64
+	o := r[0]
+
+	for x := range n {
+		a := r[x]
+		b := r[(x+1)%n]
+		sum += (a.X-o.X)*(b.Y-o.Y) - (b.X-o.X)*(a.Y-o.Y)
+	}
+
+	return sum / 2
+}
+
+// isCCW reports whether a ring is oriented counter-clockwise
+func isCCW(r []Point) bool {
+	return signedRingArea(r) > 0
+}
+
+// reverseRing returns a reversed copy of a ring
+func reverseRing(r []Point) MultiPoint {
+	out := make(MultiPoint, len(r))
+
+	for x := range r {
+		out[len(r)-1-x] = r[x]
+	}
+
+	return out
+}
+
+// locatePointInRing determines whether p lies inside, on the boundary of or outside a ring
+func locatePointInRing(p Point, r []Point) location {
+	n := len(r)
+	if n == 0 {
+		return locExterior
+	}
+
+	inside := false
+
+	for x := range n {
+		a := r[x]
+		b := r[(x+1)%n]
+
+		if onSegment(p, a, b) {
+			return locBoundary
+		}
+
+		if (a.Y > p.Y) != (b.Y > p.Y) {
+			if orientation(a, b, p) == sign(b.Y-a.Y) {
+				inside = !inside
+			}
+		}
+	}
+
+	if inside {
+		return locInterior
+	}
+
+	return locExterior
+}
+
+// locatePointInPolygon determines whether p lies inside, on the boundary of or outside a polygon
+func locatePointInPolygon(p Point, poly Polygon) location {
+	if len(poly) == 0 {
+		return locExterior
+	}
+
+	loc := locatePointInRing(p, poly[0])
+	if loc != locInterior {
+		return loc
+	}
+
+	for x := 1; x < len(poly); x++ {
+		switch locatePointInRing(p, poly[x]) {
+		case locBoundary:
+			return locBoundary
+		case locInterior:
+			return locExterior
+		}
+	}
+
+	return locInterior
+}
+
+// locatePointInMultiPolygon determines whether p lies inside, on the boundary of or outside a multipolygon
+func locatePointInMultiPolygon(p Point, mp MultiPolygon) location {
+	result := locExterior
+
+	for x := range mp {
+		switch locatePointInPolygon(p, mp[x]) {
+		case locInterior:
+			return locInterior
+		case locBoundary:
+			result = locBoundary
+		}
+	}
+
+	return result
+}
+
+func sign(v float64) int {
+	if v > 0 {
+		return 1
+	} else if v < 0 {
+		return -1
+	}
+
+	return 0
+}
+
+// pointLocator determines the location of points relative to a multipolygon. Its edges are bucketed into
+// horizontal bands, so that only the edges near a point are examined. Points are located in each polygon by the
+// even-odd rule over its rings, and are interior to the multipolygon if they are interior to any polygon, so that
+// points where the parts of an invalid multipolygon overlap are interior as well.
+type pointLocator struct {
+	minY       float64
+	bandHeight float64
+	bands      [][]locatorEdge
+}
+
+// locatorEdge is a ring edge of the polygon with index part
+type locatorEdge struct {
+	a, b Point
+	part int
+}
+
+func newPointLocator(mp MultiPolygon) *pointLocator {
+	edges := make([]locatorEdge, 0)
+	minY, maxY := math.Inf(1), math.Inf(-1)
+
+	for part, p := range mp {
+		for _, r := range p {
+			r = closeRing(r)
+
+			for x := 1; x < len(r); x++ {
+				edges = append(edges, locatorEdge{a: r[x-1], b: r[x], part: part})
+				minY = math.Min(minY, r[x].Y)
+				maxY = math.Max(maxY, r[x].Y)
+			}
+		}
+	}
+
+	n := max(1, int(math.Sqrt(float64(len(edges)))))
+	l := &pointLocator{minY: minY, bandHeight: (maxY - minY) / float64(n), bands: make([][]locatorEdge, n)}
+
+	for _, e := range edges {
+		lo, hi := l.band(math.Min(e.a.Y, e.b.Y)), l.band(math.Max(e.a.Y, e.b.Y))
+
+		for b := lo; b <= hi; b++ {
+			l.bands[b] = append(l.bands[b], e)
+		}
+	}
+
+	return l
+}
+
+func (l *pointLocator) band(y float64) int {
+	if l.bandHeight <= 0 || math.IsNaN(y) {
+		return 0
+	}
+
+	return max(0, min(len(l.bands)-1, int((y-l.minY)/l.bandHeight)))
+}
+
+func (l *pointLocator) locate(p Point) location {
+	if len(l.bands) == 0 || p.Y < l.minY || p.Y > l.minY+l.bandHeight*float64(len(l.bands)) {
+		return locExterior
+	}
+
+	// the polygons crossed by a ray from p, once per crossing, and those with p on their boundary. Only a few
+	// edges are near p, so these stay short.
+	crossed := make([]int, 0, 8)
+	boundary := make([]int, 0, 2)
+
+	for _, e := range l.bands[l.band(p.Y)] {
+		a, b := e.a, e.b
+
+		if onSegment(p, a, b) {
+			boundary = append(boundary, e.part)
+			continue
+		}
+
+		if (a.Y > p.Y) != (b.Y > p.Y) && orientation(a, b, p) == sign(b.Y-a.Y) {
+			crossed = append(crossed, e.part)
+		}
+	}
+
+	sort.Ints(crossed)
+	for x := 0; x < len(crossed); {
+		y := x
+		for y < len(crossed) && crossed[y] == crossed[x] {
+			y++
+		}
+
+		if (y-x)%2 == 1 && !slices.Contains(boundary, crossed[x]) {
+			return locInterior
+		}
+
+		x = y
+	}
+
+	if len(boundary) > 0 {
+		return locBoundary
+	}
+
+	return locExterior
+}
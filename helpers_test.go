@@ -0,0 +1,29 @@
+package gegography
+
+import "testing"
+
+// mustParseWKT parses a WKT geometry, failing the test if it is invalid
+func mustParseWKT(t *testing.T, wkt string) Feature {
+	t.Helper()
+
+	f, err := ParseWKT(wkt)
+	if err != nil {
+		t.Fatalf("ParseWKT(%s): %v", wkt, err)
+	}
+
+	return f
+}
+
+// wktCollection returns a collection with one feature for each WKT geometry, numbered by an id property
+func wktCollection(t *testing.T, wkts ...string) FeatureCollection {
+	t.Helper()
+
+	fc := NewFeatureCollection()
+	for x, wkt := range wkts {
+		f := mustParseWKT(t, wkt)
+		f.Properties["id"] = float64(x)
+		fc.AddFeature(f)
+	}
+
+	return fc
+}
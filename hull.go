@@ -0,0 +1,639 @@
+package gegography
+
+import (
+	"container/heap"
+	"math"
+	"math/rand"
+	"sort"
+)
+
+// convexHull returns the convex hull of points as a counter-clockwise polygon, computed with Andrew's monotone
+// chain algorithm. The polygon is empty when the points are all collinear.
+func convexHull(pts []Point) Polygon {
+	pts = append([]Point{}, pts...)
+	sort.Slice(pts, func(i, j int) bool { return lessPoint(pts[i], pts[j]) })
+	pts = dedupePoints(pts)
+
+	if len(pts) < 3 {
+		return Polygon{}
+	}
+
+	hull := make(MultiPoint, 0, len(pts)+1)
+
+	// the lower hull from left to right, then the upper hull from right to left
+	for pass := range 2 {
+		start := len(hull)
+
+		for x := range pts {
+			p := pts[x]
+			if pass == 1 {
+				p = pts[len(pts)-1-x]
+			}
+
+			for len(hull) >= start+2 && orientation(hull[len(hull)-2], hull[len(hull)-1], p) <= 0 {
+				hull = hull[:len(hull)-1]
+			}
+
+			hull = append(hull, p)
+		}
+
+		hull = hull[:len(hull)-1]
+	}
+
+	if len(hull) < 3 {
+		return Polygon{}
+	}
+
+	return Polygon{closeRing(hull)}
+}
+
+// delaunay is a Delaunay triangulation. Triangles list their vertices counter-clockwise, and adj holds the
+// triangle across the edge opposite each vertex, or -1 on the outside of the triangulation.
+type delaunay struct {
+	pts  []Point
+	tris [][3]int
+	adj  [][3]int
+	dead []bool
+}
+
+// triangulate computes the Delaunay triangulation of distinct points with the Bowyer-Watson algorithm, inserting
+// the points into a triangle enclosing them all, which is removed afterwards
+func triangulate(pts []Point) *delaunay {
+	n := len(pts)
+	b := MultiPoint(pts).Bounds()
+	c := b.Center()
+	m := math.Max(math.Max(b.Width(), b.Height()), 1)
+
+	d := &delaunay{pts: append(append([]Point{}, pts...),
+		Point{X: c.X - 20*m, Y: c.Y - m}, Point{X: c.X + 20*m, Y: c.Y - m}, Point{X: c.X, Y: c.Y + 20*m})}
+	d.tris = [][3]int{{n, n + 1, n + 2}}
+	d.adj = [][3]int{{-1, -1, -1}}
+	d.dead = []bool{false}
+
+	order := make([]int, n)
+	for x := range order {
+		order[x] = x
+	}
+	sort.Slice(order, func(i, j int) bool { return lessPoint(pts[order[i]], pts[order[j]]) })
+
+	last := 0
+	for _, x := range order {
+		last = d.insert(x, d.locate(pts[x], last))
+	}
+
+	for t := range d.tris {
+		for _, v := range d.tris[t] {
+			if v >= n {
+				d.dead[t] = true
+			}
+		}
+	}
+
+	return d
+}
+
+// locate returns a triangle containing p, walking towards it from triangle t
+func (d *delaunay) locate(p Point, t int) int {
+	for range len(d.tris) {
+		moved := false
+
+		for i := range 3 {
+			a, b := d.pts[d.tris[t][(i+1)%3]], d.pts[d.tris[t][(i+2)%3]]
+			if orientation(a, b, p) < 0 && d.adj[t][i] >= 0 {
+				t = d.adj[t][i]
+				moved = true
+				break
+			}
+		}
+
+		if !moved {
+			return t
+		}
+	}
+
+	// the walk should always arrive, but fall back on checking every triangle
+	for t := range d.tris {
+		if !d.dead[t] && d.contains(t, p) {
+			return t
+		}
+	}
+
+	return t
+}
+
+func (d *delaunay) contains(t int, p Point) bool {
+	for i := range 3 {
+		if orientation(d.pts[d.tris[t][i]], d.pts[d.tris[t][(i+1)%3]], p) < 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+// inCircumcircle reports whether p lies inside the circumcircle of triangle t
+func (d *delaunay) inCircumcircle(t int, p Point) bool {
+	a, b, c := d.pts[d.tris[t][0]], d.pts[d.tris[t][1]], d.pts[d.tris[t][2]]
+
+	ax, ay := a.X-p.X, a.Y-p.Y
+	bx, by := b.X-p.X, b.Y-p.Y
+	cx, cy := c.X-p.X, c.Y-p.Y
+
+	return (ax*ax+ay*ay)*(bx*cy-cx*by)-(bx*bx+by*by)*(ax*cy-cx*ay)+(cx*cx+cy*cy)*(ax*by-bx*ay) > 0
+}
+
+// insert adds point p to the triangulation, replacing the triangles whose circumcircle contains it, starting from
+// the triangle t containing it. It returns one of the new triangles.
+func (d *delaunay) insert(p, t int) int {
+	cavity := []int{t}
+	inCavity := map[int]bool{t: true}
+
+	for x := 0; x < len(cavity); x++ {
+		for _, o := range d.adj[cavity[x]] {
+			if o >= 0 && !inCavity[o] && d.inCircumcircle(o, d.pts[p]) {
+				inCavity[o] = true
+				cavity = append(cavity, o)
+			}
+		}
+	}
+
+	// fan new triangles from p to the edges around the cavity, linking those sharing an edge through p
+	startAt := make(map[int]int)
+	endAt := make(map[int]int)
+	created := make([]int, 0)
+
+	for _, c := range cavity {
+		d.dead[c] = true
+
+		for i := range 3 {
+			o := d.adj[c][i]
+			if o >= 0 && inCavity[o] {
+				continue
+			}
+
+			a, b := d.tris[c][(i+1)%3], d.tris[c][(i+2)%3]
+			k := len(d.tris)
+			d.tris = append(d.tris, [3]int{a, b, p})
+			d.adj = append(d.adj, [3]int{-1, -1, o})
+			d.dead = append(d.dead, false)
+
+			if o >= 0 {
+				for j := range 3 {
+					if d.adj[o][j] == c {
+						d.adj[o][j] = k
+					}
+				}
+			}
+
+			startAt[a] = k
+			endAt[b] = k
+			created = append(created, k)
+		}
+	}
+
+	for _, k := range created {
+		d.adj[k][0] = startAt[d.tris[k][1]]
+		d.adj[k][1] = endAt[d.tris[k][0]]
+	}
+
+	return created[0]
+}
+
+// triangulationEdge is an edge of a triangle, identified by the triangle and the vertex opposite the edge
+type triangulationEdge struct {
+	tri, opposite int
+	length        float64
+}
+
+type edgeHeap []triangulationEdge
+
+func (h edgeHeap) Len() int           { return len(h) }
+func (h edgeHeap) Less(i, j int) bool { return h[i].length > h[j].length }
+func (h edgeHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *edgeHeap) Push(x any)        { *h = append(*h, x.(triangulationEdge)) }
+func (h *edgeHeap) Pop() any {
+	old := *h
+	e := old[len(old)-1]
+	*h = old[:len(old)-1]
+
+	return e
+}
+
+// concaveHull returns a concave hull of points using the chi-shape algorithm: starting from the Delaunay
+// triangulation, the longest edges on the outside are removed along with their triangle, as long as they are longer
+// than a threshold and removing them keeps the hull a simple polygon. The threshold lies between the shortest and
+// longest edge of the triangulation according to ratio, so that 1 gives the convex hull.
+func concaveHull(pts []Point, ratio float64) Polygon {
+	pts = append([]Point{}, pts...)
+	sort.Slice(pts, func(i, j int) bool { return lessPoint(pts[i], pts[j]) })
+	pts = dedupePoints(pts)
+
+	if ratio >= 1 || len(pts) < 4 {
+		return convexHull(pts)
+	}
+
+	d := triangulate(pts)
+
+	length := func(t, i int) float64 {
+		return distance(d.pts[d.tris[t][(i+1)%3]], d.pts[d.tris[t][(i+2)%3]])
+	}
+	outside := func(t int) bool {
+		return t < 0 || d.dead[t]
+	}
+
+	minLength, maxLength := math.Inf(1), 0.0
+	boundary := make(map[int]bool)
+	h := make(edgeHeap, 0)
+
+	for t := range d.tris {
+		if d.dead[t] {
+			continue
+		}
+
+		for i := range 3 {
+			l := length(t, i)
+			minLength = math.Min(minLength, l)
+			maxLength = math.Max(maxLength, l)
+
+			if outside(d.adj[t][i]) {
+				h = append(h, triangulationEdge{tri: t, opposite: i, length: l})
+				boundary[d.tris[t][(i+1)%3]] = true
+				boundary[d.tris[t][(i+2)%3]] = true
+			}
+		}
+	}
+
+	if len(h) == 0 {
+		return Polygon{}
+	}
+
+	threshold := minLength + math.Max(ratio, 0)*(maxLength-minLength)
+	heap.Init(&h)
+
+	for h.Len() > 0 {
+		e := heap.Pop(&h).(triangulationEdge)
+		if e.length <= threshold {
+			break
+		}
+
+		v := d.tris[e.tri][e.opposite]
+		if d.dead[e.tri] || boundary[v] {
+			continue
+		}
+
+		d.dead[e.tri] = true
+		boundary[v] = true
+
+		for i := range 3 {
+			o := d.adj[e.tri][i]
+			if i == e.opposite || outside(o) {
+				continue
+			}
+
+			for j := range 3 {
+				if d.adj[o][j] == e.tri {
+					heap.Push(&h, triangulationEdge{tri: o, opposite: j, length: length(o, j)})
+				}
+			}
+		}
+	}
+
+	// the remaining outside edges, directed with the triangles on their left, form the hull
+	next := make(map[int]int)
+	start := -1
+
+	for t := range d.tris {
+		if d.dead[t] {
+			continue
+		}
+
+		for i := range 3 {
+			if outside(d.adj[t][i]) {
+				a, b := d.tris[t][(i+1)%3], d.tris[t][(i+2)%3]
+				next[a] = b
+				start = a
+			}
+		}
+	}
+
+	ring := MultiPoint{d.pts[start]}
+	for v := next[start]; v != start && len(ring) <= len(next); v = next[v] {
+		ring = append(ring, d.pts[v])
+	}
+
+	return Polygon{closeRing(removeCollinear(ring))}
+}
+
+// minimumRotatedRectangle returns the smallest rectangle enclosing points, which has a side lying on an edge of
+// their convex hull. The edges are checked with rotating calipers, so the rectangle is found in linear time once
+// the hull is known. Like the hull, it is empty for fewer than three distinct points or collinear points.
+func minimumRotatedRectangle(pts []Point) Polygon {
+	hull := convexHull(pts)
+	if len(hull) == 0 {
+		return Polygon{}
+	}
+
+	h := ringVertices(removeCollinear(hull[0]))
+	n := len(h)
+
+	dot := func(p, d Point) float64 { return p.X*d.X + p.Y*d.Y }
+
+	var best Polygon
+	bestArea := math.Inf(1)
+
+	// indices of the points farthest along the edge, farthest from it and farthest back along it
+	far, front, back := 0, 0, 0
+
+	for i := range n {
+		o := h[i]
+		l := distance(o, h[(i+1)%n])
+		u := Point{X: (h[(i+1)%n].X - o.X) / l, Y: (h[(i+1)%n].Y - o.Y) / l}
+		v := Point{X: -u.Y, Y: u.X}
+
+		along := func(x int) float64 { return dot(Point{X: h[x%n].X - o.X, Y: h[x%n].Y - o.Y}, u) }
+		across := func(x int) float64 { return dot(Point{X: h[x%n].X - o.X, Y: h[x%n].Y - o.Y}, v) }
+
+		if i == 0 {
+			for x := range n {
+				if across(x) > across(far) {
+					far = x
+				}
+				if along(x) > along(front) {
+					front = x
+				}
+				if along(x) < along(back) {
+					back = x
+				}
+			}
+		}
+
+		for range n {
+			if across(far+1) < across(far) {
+				break
+			}
+			far = (far + 1) % n
+		}
+		for range n {
+			if along(front+1) < along(front) {
+				break
+			}
+			front = (front + 1) % n
+		}
+		for range n {
+			if along(back+1) > along(back) {
+				break
+			}
+			back = (back + 1) % n
+		}
+
+		minU, maxU, maxV := along(back), along(front), across(far)
+
+		if area := (maxU - minU) * maxV; area < bestArea {
+			corner := func(a, b float64) Point {
+				return Point{X: o.X + a*u.X + b*v.X, Y: o.Y + a*u.Y + b*v.Y}
+			}
+
+			bestArea = area
+			best = Polygon{{corner(minU, 0), corner(maxU, 0), corner(maxU, maxV), corner(minU, maxV), corner(minU, 0)}}
+		}
+	}
+
+	return best
+}
+
+// enclosingCircle returns the centre and radius of the smallest circle enclosing points, using Welzl's algorithm
+// with the points in random order, which takes expected linear time
+func enclosingCircle(pts []Point) (Point, float64) {
+	if len(pts) == 0 {
+		return Point{}, 0
+	}
+
+	pts = append([]Point{}, pts...)
+	r := rand.New(rand.NewSource(1))
+	r.Shuffle(len(pts), func(i, j int) { pts[i], pts[j] = pts[j], pts[i] })
+
+	tol := nodingTolerance(pts)
+	c, radius := pts[0], 0.0
+	inside := func(p Point) bool { return distance(c, p) <= radius+tol }
+
+	for i := range pts {
+		if inside(pts[i]) {
+			continue
+		}
+
+		c, radius = pts[i], 0
+
+		for j := range i {
+			if inside(pts[j]) {
+				continue
+			}
+
+			c, radius = circleThrough2(pts[i], pts[j])
+
+			for k := range j {
+				if !inside(pts[k]) {
+					c, radius = circleThrough3(pts[i], pts[j], pts[k])
+				}
+			}
+		}
+	}
+
+	return c, radius
+}
+
+// circleThrough2 returns the smallest circle through two points
+func circleThrough2(a, b Point) (Point, float64) {
+	c := Point{X: (a.X + b.X) / 2, Y: (a.Y + b.Y) / 2}
+
+	return c, distance(a, c)
+}
+
+// circleThrough3 returns the circle through three points, or the smallest circle enclosing them if they are
+// collinear
+func circleThrough3(a, b, c Point) (Point, float64) {
+	bx, by := b.X-a.X, b.Y-a.Y
+	cx, cy := c.X-a.X, c.Y-a.Y
+	d := 2 * (bx*cy - by*cx)
+
+	if d == 0 {
+		center, radius := circleThrough2(a, b)
+		for _, pair := range [][2]Point{{a, c}, {b, c}} {
+			if cc, cr := circleThrough2(pair[0], pair[1]); cr > radius {
+				center, radius = cc, cr
+			}
+		}
+
+		return center, radius
+	}
+
+	ux := (cy*(bx*bx+by*by) - by*(cx*cx+cy*cy)) / d
+	uy := (bx*(cx*cx+cy*cy) - cx*(bx*bx+by*by)) / d
+	center := Point{X: a.X + ux, Y: a.Y + uy}
+
+	return center, distance(center, a)
+}
+
+// enclosingCirclePolygon approximates the smallest circle enclosing points with a polygon. The vertices lie slightly
+// outside the circle, so that the polygon encloses all of it.
+func enclosingCirclePolygon(pts []Point) Polygon {
+	if len(pts) == 0 {
+		return Polygon{}
+	}
+
+	c, r := enclosingCircle(pts)
+	if r == 0 {
+		return Polygon{}
+	}
+
+	const segments = 16
+
+	return bufferCircle(c, r/math.Cos(math.Pi/(4*segments)), BufferOptions{Segments: segments})
+}
+
+// ConvexHull returns the smallest convex polygon containing all the points. The polygon is empty when there are
+// fewer than three points or they are all collinear.
+func (mp MultiPoint) ConvexHull() Polygon {
+	return convexHull(mp)
+}
+
+// ConvexHull returns the smallest convex polygon containing the line, which is empty for straight lines
+func (ls LineString) ConvexHull() Polygon {
+	return convexHull(ls)
+}
+
+// ConvexHull returns the smallest convex polygon containing the polygon
+func (p Polygon) ConvexHull() Polygon {
+	if len(p) == 0 {
+		return Polygon{}
+	}
+
+	return convexHull(p[0])
+}
+
+// ConvexHull returns the smallest convex polygon containing all polygons
+func (mp MultiPolygon) ConvexHull() Polygon {
+	pts := make([]Point, 0)
+	for _, p := range mp {
+		if len(p) > 0 {
+			pts = append(pts, p[0]...)
+		}
+	}
+
+	return convexHull(pts)
+}
+
+// ConcaveHull returns a polygon containing all the points which follows their outline more closely than the
+// convex hull. Ratio sets the tightness, from 0 for the most concave hull to 1 for the convex hull: outer edges
+// longer than the shortest edge between neighbouring points plus ratio times the difference between the longest and
+// the shortest are cut away, as long as the hull stays a simple polygon containing every point.
+func (mp MultiPoint) ConcaveHull(ratio float64) Polygon {
+	return concaveHull(mp, ratio)
+}
+
+// MinimumRotatedRectangle returns the smallest rectangle, in any orientation, containing all the points. The
+// polygon is empty when there are fewer than three distinct points or they are all collinear.
+func (mp MultiPoint) MinimumRotatedRectangle() Polygon {
+	return minimumRotatedRectangle(mp)
+}
+
+// MinimumEnclosingCircle returns a polygon approximating the smallest circle containing all the points. Its vertices
+// lie just outside the circle, so that it contains the points. The polygon is empty when there are no points or they
+// are all equal, see EnclosingCircle for the exact circle.
+func (mp MultiPoint) MinimumEnclosingCircle() Polygon {
+	return enclosingCirclePolygon(mp)
+}
+
+// EnclosingCircle returns the centre and radius of the smallest circle containing all the points, which is the zero
+// point with radius 0 when there are none
+func (mp MultiPoint) EnclosingCircle() (Point, float64) {
+	return enclosingCircle(mp)
+}
+
+// points returns every coordinate of the feature
+func (f *Feature) points() (MultiPoint, error) {
+	pts := make(MultiPoint, 0)
+
+	_, err := f.transformed(func(p Point) (Point, error) {
+		pts = append(pts, p)
+		return p, nil
+	})
+
+	return pts, err
+}
+
+// points returns every coordinate of every feature in the collection
+func (fc *FeatureCollection) points() (MultiPoint, error) {
+	pts := make(MultiPoint, 0)
+
+	for x := range fc.Features {
+		fp, err := fc.Features[x].points()
+		if err != nil {
+			return nil, err
+		}
+
+		pts = append(pts, fp...)
+	}
+
+	return pts, nil
+}
+
+// ConvexHull returns the smallest convex polygon containing the feature
+func (f *Feature) ConvexHull() (Polygon, error) {
+	pts, err := f.points()
+
+	return convexHull(pts), err
+}
+
+// ConcaveHull returns a concave hull of the coordinates of the feature, see MultiPoint.ConcaveHull
+func (f *Feature) ConcaveHull(ratio float64) (Polygon, error) {
+	pts, err := f.points()
+
+	return concaveHull(pts, ratio), err
+}
+
+// MinimumRotatedRectangle returns the smallest rectangle, in any orientation, containing the feature. The polygon
+// is empty for points and straight lines, which enclose no area.
+func (f *Feature) MinimumRotatedRectangle() (Polygon, error) {
+	pts, err := f.points()
+
+	return minimumRotatedRectangle(pts), err
+}
+
+// MinimumEnclosingCircle returns a polygon approximating the smallest circle containing the feature. Its vertices
+// lie just outside the circle, so that it contains the feature.
+func (f *Feature) MinimumEnclosingCircle() (Polygon, error) {
+	pts, err := f.points()
+
+	return enclosingCirclePolygon(pts), err
+}
+
+// ConvexHull returns the smallest convex polygon containing every feature in the collection
+func (fc *FeatureCollection) ConvexHull() (Polygon, error) {
+	pts, err := fc.points()
+
+	return convexHull(pts), err
+}
+
+// ConcaveHull returns a concave hull of the coordinates of every feature in the collection, see
+// MultiPoint.ConcaveHull
+func (fc *FeatureCollection) ConcaveHull(ratio float64) (Polygon, error) {
+	pts, err := fc.points()
+
+	return concaveHull(pts, ratio), err
+}
+
+// MinimumRotatedRectangle returns the smallest rectangle, in any orientation, containing every feature in the
+// collection. The polygon is empty when their points are all collinear.
+func (fc *FeatureCollection) MinimumRotatedRectangle() (Polygon, error) {
+	pts, err := fc.points()
+
+	return minimumRotatedRectangle(pts), err
+}
+
+// MinimumEnclosingCircle returns a polygon approximating the smallest circle containing every feature in the
+// collection. Its vertices lie just outside the circle, so that it contains the features.
+func (fc *FeatureCollection) MinimumEnclosingCircle() (Polygon, error) {
+	pts, err := fc.points()
+
+	return enclosingCirclePolygon(pts), err
+}
@@ -0,0 +1,264 @@
+package gegography
+
+import (
+	"math"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestConvexHull(t *testing.T) {
+	tests := []struct {
+		name string
+		wkt  string
+		area float64
+		size int
+	}{
+		{"points", "MULTIPOINT ((0 0), (10 0), (5 5), (10 10), (0 10), (3 7), (5 0))", 100, 5},
+		{"line", "LINESTRING (0 0, 10 0, 10 10, 5 2)", 50, 4},
+		{"polygon", "POLYGON ((0 0, 10 0, 10 10, 5 2, 0 10, 0 0))", 100, 5},
+		{"multipolygon", "MULTIPOLYGON (((0 0, 1 0, 1 1, 0 0)), ((9 9, 10 9, 10 10, 9 9)))", 9.5, 5},
+		{"collinear", "MULTIPOINT ((0 0), (1 1), (2 2))", 0, 0},
+		{"point", "POINT (1 1)", 0, 0},
+	}
+
+	for _, tt := range tests {
+		f := mustParseWKT(t, tt.wkt)
+
+		got, err := f.ConvexHull()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if math.Abs(got.Area()-tt.area) > 1e-9 || len(got) > 0 && len(got[0]) != tt.size || len(got) == 0 && tt.size != 0 {
+			t.Errorf("ConvexHull() of %s, want area %v with %d vertices got %v", tt.name, tt.area, tt.size, got)
+		}
+
+		if len(got) > 0 && !isCCW(got[0]) {
+			t.Errorf("ConvexHull() of %s, want a counter-clockwise shell got %v", tt.name, got)
+		}
+	}
+
+	fc := FeatureCollection{Features: []Feature{
+		mustParseWKT(t, "POINT (0 0)"),
+		mustParseWKT(t, "LINESTRING (10 0, 10 10)"),
+		mustParseWKT(t, "POINT (0 10)"),
+	}}
+
+	if got, err := fc.ConvexHull(); err != nil || got.Area() != 100 {
+		t.Errorf("FeatureCollection.ConvexHull(), want area 100 got %v (%v)", got, err)
+	}
+}
+
+// uShape returns a grid of points in the shape of a U, 10 wide and tall with a 6 wide and 8 deep notch, with an
+// area of 52
+func uShape() MultiPoint {
+	pts := make(MultiPoint, 0)
+
+	for x := 0; x <= 20; x++ {
+		for y := 0; y <= 20; y++ {
+			if x > 4 && x < 16 && y > 4 {
+				continue
+			}
+
+			pts = append(pts, Point{X: float64(x) / 2, Y: float64(y) / 2})
+		}
+	}
+
+	return pts
+}
+
+func TestConcaveHull(t *testing.T) {
+	pts := uShape()
+
+	if got := pts.ConcaveHull(1); got.Area() != 100 {
+		t.Errorf("ConcaveHull(1), want the convex hull with area 100 got %v", got)
+	}
+
+	for _, ratio := range []float64{0, 0.05, 0.2} {
+		got := pts.ConcaveHull(ratio)
+
+		if err := got.Validate(); err != nil {
+			t.Fatalf("ConcaveHull(%v), want a valid polygon got %v", ratio, err)
+		}
+
+		// the sharp corners at the bottom of the notch may be cut off
+		if got.Area() < 52 || got.Area() > 53 {
+			t.Errorf("ConcaveHull(%v), want area 52 got %v", ratio, got.Area())
+		}
+
+		for _, p := range pts {
+			if locatePointInPolygon(p, got) == locExterior {
+				t.Fatalf("ConcaveHull(%v), want %v inside got %v", ratio, p, got)
+			}
+		}
+	}
+
+	r := rand.New(rand.NewSource(1))
+	random := make(MultiPoint, 0)
+	for range 2000 {
+		a, d := r.Float64()*2*math.Pi, math.Sqrt(r.Float64())*10
+		random = append(random, Point{X: d * math.Cos(a), Y: d * math.Sin(a)})
+	}
+
+	previous := 0.0
+	for _, ratio := range []float64{0, 0.1, 0.3, 0.6, 1} {
+		got := random.ConcaveHull(ratio)
+
+		if err := got.Validate(); err != nil {
+			t.Fatalf("ConcaveHull(%v), want a valid polygon got %v", ratio, err)
+		}
+		if got.Area() < previous {
+			t.Errorf("ConcaveHull(%v), want at least the area %v of a smaller ratio got %v", ratio, previous, got.Area())
+		}
+
+		for _, p := range random {
+			if locatePointInPolygon(p, got) == locExterior {
+				t.Fatalf("ConcaveHull(%v), want %v inside got %v", ratio, p, got)
+			}
+		}
+
+		previous = got.Area()
+	}
+
+	if convex := random.ConvexHull().Area(); previous != convex {
+		t.Errorf("ConcaveHull(1), want the convex hull area %v got %v", convex, previous)
+	}
+}
+
+func TestMinimumRotatedRectangle(t *testing.T) {
+	a := math.Pi / 6
+	rotated := make(MultiPoint, 0)
+
+	for _, p := range []Point{{0, 0}, {4, 0}, {4, 2}, {0, 2}, {1, 1}, {3, 0.5}, {2, 2}} {
+		rotated = append(rotated, Point{X: 3 + p.X*math.Cos(a) - p.Y*math.Sin(a), Y: -2 + p.X*math.Sin(a) + p.Y*math.Cos(a)})
+	}
+
+	got := rotated.MinimumRotatedRectangle()
+	if math.Abs(got.Area()-8) > 1e-9 {
+		t.Errorf("MinimumRotatedRectangle(), want area 8 got %v with area %v", got, got.Area())
+	}
+
+	for _, p := range rotated {
+		if locatePointInRing(p, got[0]) == locExterior && ringDistance(p, got[0]) > 1e-9 {
+			t.Errorf("MinimumRotatedRectangle(), want %v inside got %v", p, got)
+		}
+	}
+
+	for _, mp := range []MultiPoint{{}, {{0, 0}, {1, 1}}, {{0, 0}, {1, 1}, {3, 3}, {2, 2}}, {{1, 2}, {1, 2}, {1, 2}}} {
+		if got := mp.MinimumRotatedRectangle(); len(got) != 0 {
+			t.Errorf("MinimumRotatedRectangle() of %v, want an empty polygon got %v", mp, got)
+		}
+	}
+
+	line := mustParseWKT(t, "LINESTRING (0 0, 1 1, 2 2)")
+	if got, err := line.MinimumRotatedRectangle(); err != nil || len(got) != 0 {
+		t.Errorf("MinimumRotatedRectangle() of a straight line, want an empty polygon got %v (%v)", got, err)
+	}
+}
+
+// ringDistance returns the distance from p to the boundary of a ring
+func ringDistance(p Point, r MultiPoint) float64 {
+	d := math.Inf(1)
+	for x := 1; x < len(r); x++ {
+		d = math.Min(d, segmentDistance(p, r[x-1], r[x]))
+	}
+
+	return d
+}
+
+// bruteForceCircle returns the radius of the smallest circle containing the points, trying the circles through
+// every pair and triple of them
+func bruteForceCircle(pts []Point) float64 {
+	best := math.Inf(1)
+
+	try := func(c Point, r float64) {
+		for _, p := range pts {
+			if distance(c, p) > r+1e-9 {
+				return
+			}
+		}
+
+		best = math.Min(best, r)
+	}
+
+	for i := range pts {
+		for j := range i {
+			try(circleThrough2(pts[i], pts[j]))
+
+			for k := range j {
+				try(circleThrough3(pts[i], pts[j], pts[k]))
+			}
+		}
+	}
+
+	return best
+}
+
+func TestEnclosingCircle(t *testing.T) {
+	r := rand.New(rand.NewSource(3))
+
+	for n := 2; n < 40; n++ {
+		pts := make(MultiPoint, n)
+		for x := range pts {
+			pts[x] = Point{X: 100 * r.Float64(), Y: 50 * r.Float64()}
+		}
+
+		c, radius := pts.EnclosingCircle()
+		if want := bruteForceCircle(pts); math.Abs(radius-want) > 1e-9 {
+			t.Errorf("EnclosingCircle() of %d points, want radius %v got %v", n, want, radius)
+		}
+
+		for _, p := range pts {
+			if distance(c, p) > radius+1e-9 {
+				t.Errorf("EnclosingCircle() of %d points, want %v inside got centre %v and radius %v", n, p, c, radius)
+			}
+		}
+	}
+
+	pts := MultiPoint{{1, 1}, {2, -1}, {-3, 0.5}}
+	for x := range 12 {
+		a := float64(x) * math.Pi / 5
+		pts = append(pts, Point{X: 10 + 5*math.Cos(a), Y: 20 + 5*math.Sin(a)})
+	}
+
+	if _, radius := pts.EnclosingCircle(); math.Abs(radius-bruteForceCircle(pts)) > 1e-9 {
+		t.Errorf("EnclosingCircle(), want radius %v got %v", bruteForceCircle(pts), radius)
+	}
+
+	if c, radius := (MultiPoint{{0, 0}, {4, 0}, {2, 2}}).EnclosingCircle(); c != (Point{X: 2, Y: 0}) || radius != 2 {
+		t.Errorf("EnclosingCircle() of a right triangle, want centre (2, 0) and radius 2 got %v and %v", c, radius)
+	}
+
+	if c, radius := (MultiPoint{}).EnclosingCircle(); c != (Point{}) || radius != 0 {
+		t.Errorf("EnclosingCircle() of no points, want the zero point and radius 0 got %v and %v", c, radius)
+	}
+}
+
+func TestMinimumEnclosingCircle(t *testing.T) {
+	pts := MultiPoint{{0, 0}, {10, 0}, {5, 1}}
+	f := Feature{Type: "MultiPoint", Coordinates: pts}
+
+	got, err := f.MinimumEnclosingCircle()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if mp := pts.MinimumEnclosingCircle(); !reflect.DeepEqual(mp, got) {
+		t.Errorf("MultiPoint.MinimumEnclosingCircle(), want the circle of the feature %v got %v", got, mp)
+	}
+
+	for _, p := range pts {
+		if locatePointInPolygon(p, got) != locInterior {
+			t.Errorf("MinimumEnclosingCircle(), want %v inside got %v", p, got)
+		}
+	}
+
+	if b := got.Bounds(); math.Abs(b.Width()-10) > 0.1 || math.Abs(b.Center().X-5) > 1e-9 {
+		t.Errorf("MinimumEnclosingCircle().Bounds(), want a width of 10 centred on x = 5 got %v", b)
+	}
+
+	if p := (MultiPoint{{3, 3}, {3, 3}}).MinimumEnclosingCircle(); len(p) != 0 {
+		t.Errorf("MinimumEnclosingCircle() of equal points, want an empty polygon got %v", p)
+	}
+}
@@ -0,0 +1,277 @@
+package gegography
+
+import (
+	"fmt"
+	"math"
+	"slices"
+)
+
+// LinearMeasure selects how positions along a line are given to the linear referencing methods. Lines carrying
+// their own measures, such as those read from PolyLineM shapefiles, are referenced by them with MeasuredLineString.
+type LinearMeasure int
+
+const (
+	// MeasureDistance gives positions as the planar distance along the line from its first point
+	MeasureDistance LinearMeasure = iota
+	// MeasureFraction gives positions as a fraction of the length of the line, from 0 at the first point to 1 at
+	// the last
+	MeasureFraction
+)
+
+// distanceAlong converts a position along the line to a distance from its first point, clamped to the line
+func (ls LineString) distanceAlong(m float64, measure LinearMeasure) float64 {
+	length := ls.Length()
+	if measure == MeasureFraction {
+		m *= length
+	}
+
+	return math.Max(0, math.Min(length, m))
+}
+
+// pointAlong returns the point at a distance along the line, which must not be empty, and the index of the segment
+// it lies on. Points on a vertex are taken to lie on the segment ending there.
+func (ls LineString) pointAlong(d float64) (Point, int) {
+	var travelled float64
+
+	for x := 1; x < len(ls); x++ {
+		l := distance(ls[x-1], ls[x])
+
+		if travelled+l >= d && l > 0 {
+			t := math.Max(0, math.Min(1, (d-travelled)/l))
+			return Point{X: ls[x-1].X + t*(ls[x].X-ls[x-1].X), Y: ls[x-1].Y + t*(ls[x].Y-ls[x-1].Y)}, x - 1
+		}
+
+		travelled += l
+	}
+
+	return ls[len(ls)-1], max(len(ls)-2, 0)
+}
+
+// InterpolatePoint returns the point at a position along the line. Positions before the start or beyond the end
+// are clamped to the first or last point. An empty line gives the zero point.
+func (ls LineString) InterpolatePoint(m float64, measure LinearMeasure) Point {
+	if len(ls) == 0 {
+		return Point{}
+	}
+
+	p, _ := ls.pointAlong(ls.distanceAlong(m, measure))
+
+	return p
+}
+
+// LocatePoint returns the position along the line of the point on it closest to p. Where several points are
+// equally close, the one closest to the start is used. An empty or zero-length line gives 0.
+func (ls LineString) LocatePoint(p Point, measure LinearMeasure) float64 {
+	best := math.Inf(1)
+	var travelled, at float64
+
+	for x := 1; x < len(ls); x++ {
+		q := closestPointOnSegment(p, ls[x-1], ls[x])
+		if d := distance(p, q); d < best {
+			best = d
+			at = travelled + distance(ls[x-1], q)
+		}
+
+		travelled += distance(ls[x-1], ls[x])
+	}
+
+	if measure == MeasureFraction {
+		if travelled == 0 {
+			return 0
+		}
+
+		return at / travelled
+	}
+
+	return at
+}
+
+// LineSubstring returns the part of the line between two positions along it, keeping the vertices in between.
+// Positions are clamped to the line. If from is greater than to, the part is reversed so that it starts at from.
+// Equal positions give a line of two equal points, and an empty line gives an empty line.
+func (ls LineString) LineSubstring(from, to float64, measure LinearMeasure) LineString {
+	if len(ls) == 0 {
+		return LineString{}
+	}
+
+	a, b := ls.distanceAlong(from, measure), ls.distanceAlong(to, measure)
+	if a > b {
+		return reverseLine(ls.LineSubstring(to, from, measure))
+	}
+
+	pa, sa := ls.pointAlong(a)
+	pb, sb := ls.pointAlong(b)
+
+	out := LineString{pa}
+	for _, p := range append(append([]Point{}, ls[sa+1:sb+1]...), pb) {
+		if !p.equals(out[len(out)-1]) {
+			out = append(out, p)
+		}
+	}
+
+	if len(out) == 1 {
+		out = append(out, pa)
+	}
+
+	return out
+}
+
+// reverseLine returns a reversed copy of a line
+func reverseLine(ls LineString) LineString {
+	return LineString(reverseRing(ls))
+}
+
+// MeasuredLineString is a line with a measure, or M value, for each of its points, such as the lines of PolyLineM
+// shapefiles holding road kilometres. Measures are interpolated linearly along each segment. They usually increase
+// along the line but need not, and where several positions have the same measure the one closest to the start of
+// the line is used. Points with a NaN measure, meaning no data, are never matched.
+type MeasuredLineString struct {
+	Line     LineString
+	Measures []float64
+}
+
+// MeasuredLines returns the lines of a LineString or MultiLineString feature with their measures, which must have
+// been read with the feature
+func (f *Feature) MeasuredLines() ([]MeasuredLineString, error) {
+	lines, err := f.lines()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(f.Measures) != len(lines) {
+		return nil, GeoFormatError{Msg: "feature has no measures for its lines"}
+	}
+
+	out := make([]MeasuredLineString, len(lines))
+	for x := range lines {
+		if len(f.Measures[x]) != len(lines[x]) {
+			return nil, GeoFormatError{Msg: fmt.Sprintf("line %d has %d points but %d measures", x, len(lines[x]), len(f.Measures[x]))}
+		}
+
+		out[x] = MeasuredLineString{Line: LineString(lines[x]), Measures: f.Measures[x]}
+	}
+
+	return out, nil
+}
+
+// valid reports whether the line has points and a measure for each of them
+func (ml MeasuredLineString) valid() bool {
+	return len(ml.Line) > 0 && len(ml.Line) == len(ml.Measures)
+}
+
+// distanceAt returns the distance along the line from its first point to the first position with measure m, or
+// false if no segment spans m
+func (ml MeasuredLineString) distanceAt(m float64) (float64, bool) {
+	if !ml.valid() {
+		return 0, false
+	}
+
+	if len(ml.Line) == 1 || ml.Measures[0] == m {
+		return 0, ml.Measures[0] == m
+	}
+
+	var travelled float64
+
+	for x := 1; x < len(ml.Line); x++ {
+		ma, mb := ml.Measures[x-1], ml.Measures[x]
+		l := distance(ml.Line[x-1], ml.Line[x])
+
+		if math.Min(ma, mb) <= m && m <= math.Max(ma, mb) {
+			if ma == mb {
+				return travelled, true
+			}
+
+			return travelled + l*(m-ma)/(mb-ma), true
+		}
+
+		travelled += l
+	}
+
+	return 0, false
+}
+
+// measureOn returns the measure of a point lying on the segment starting at the point with index s
+func (ml MeasuredLineString) measureOn(s int, p Point) float64 {
+	if s+1 >= len(ml.Line) {
+		return ml.Measures[s]
+	}
+
+	l := distance(ml.Line[s], ml.Line[s+1])
+	if l == 0 {
+		return ml.Measures[s]
+	}
+
+	return ml.Measures[s] + distance(ml.Line[s], p)/l*(ml.Measures[s+1]-ml.Measures[s])
+}
+
+// InterpolatePoint returns the first point of the line with measure m, or false if no part of the line has it
+func (ml MeasuredLineString) InterpolatePoint(m float64) (Point, bool) {
+	d, ok := ml.distanceAt(m)
+	if !ok {
+		return Point{}, false
+	}
+
+	p, _ := ml.Line.pointAlong(d)
+
+	return p, true
+}
+
+// LocatePoint returns the measure of the point on the line closest to p, as LineString.LocatePoint finds it. It
+// reports false for lines without measures for every point, and where the measure at the closest point is unknown.
+func (ml MeasuredLineString) LocatePoint(p Point) (float64, bool) {
+	if !ml.valid() {
+		return 0, false
+	}
+
+	q, s := ml.Line.pointAlong(ml.Line.LocatePoint(p, MeasureDistance))
+	m := ml.measureOn(s, q)
+
+	return m, !math.IsNaN(m)
+}
+
+// LineSubstring returns the part of the line between the first positions with the measures from and to, keeping
+// the vertices in between and their measures. If from lies after to along the line, the part is reversed so that
+// it starts at from. It reports false if the line has no position with either measure.
+func (ml MeasuredLineString) LineSubstring(from, to float64) (MeasuredLineString, bool) {
+	a, okA := ml.distanceAt(from)
+	b, okB := ml.distanceAt(to)
+	if !okA || !okB {
+		return MeasuredLineString{}, false
+	}
+
+	if a > b {
+		sub := ml.substring(b, a)
+		slices.Reverse(sub.Line)
+		slices.Reverse(sub.Measures)
+
+		return sub, true
+	}
+
+	return ml.substring(a, b), true
+}
+
+// substring returns the part of the line between two distances along it, with a <= b
+func (ml MeasuredLineString) substring(a, b float64) MeasuredLineString {
+	pa, sa := ml.Line.pointAlong(a)
+	pb, sb := ml.Line.pointAlong(b)
+
+	out := MeasuredLineString{Line: LineString{pa}, Measures: []float64{ml.measureOn(sa, pa)}}
+	add := func(p Point, m float64) {
+		if !p.equals(out.Line[len(out.Line)-1]) {
+			out.Line = append(out.Line, p)
+			out.Measures = append(out.Measures, m)
+		}
+	}
+
+	for x := sa + 1; x <= sb; x++ {
+		add(ml.Line[x], ml.Measures[x])
+	}
+	add(pb, ml.measureOn(sb, pb))
+
+	if len(out.Line) == 1 {
+		out.Line = append(out.Line, pa)
+		out.Measures = append(out.Measures, out.Measures[0])
+	}
+
+	return out
+}
@@ -0,0 +1,214 @@
+package gegography
+
+import (
+	"math"
+	"slices"
+	"testing"
+)
+
+func TestInterpolatePoint(t *testing.T) {
+	line := LineString{{X: 0, Y: 0}, {X: 10, Y: 0}, {X: 10, Y: 10}, {X: 10, Y: 10}, {X: 0, Y: 10}}
+
+	tests := []struct {
+		m       float64
+		measure LinearMeasure
+		want    Point
+	}{
+		{0, MeasureDistance, Point{X: 0, Y: 0}},
+		{5, MeasureDistance, Point{X: 5, Y: 0}},
+		{10, MeasureDistance, Point{X: 10, Y: 0}},
+		{15, MeasureDistance, Point{X: 10, Y: 5}},
+		{25, MeasureDistance, Point{X: 5, Y: 10}},
+		{-3, MeasureDistance, Point{X: 0, Y: 0}},
+		{40, MeasureDistance, Point{X: 0, Y: 10}},
+		{0.5, MeasureFraction, Point{X: 10, Y: 5}},
+		{1, MeasureFraction, Point{X: 0, Y: 10}},
+		{1.5, MeasureFraction, Point{X: 0, Y: 10}},
+	}
+
+	for _, tt := range tests {
+		if got := line.InterpolatePoint(tt.m, tt.measure); !got.equals(tt.want) {
+			t.Errorf("InterpolatePoint(%v, %v), want %v got %v", tt.m, tt.measure, tt.want, got)
+		}
+	}
+
+	if got := (LineString{}).InterpolatePoint(1, MeasureDistance); got != (Point{}) {
+		t.Errorf("InterpolatePoint(1, MeasureDistance) on an empty line, want {0 0} got %v", got)
+	}
+
+	if got := (LineString{{X: 1, Y: 1}, {X: 1, Y: 1}}).InterpolatePoint(0.5, MeasureFraction); got != (Point{X: 1, Y: 1}) {
+		t.Errorf("InterpolatePoint(0.5, MeasureFraction) on a zero-length line, want {1 1} got %v", got)
+	}
+}
+
+func TestLocatePoint(t *testing.T) {
+	line := LineString{{X: 0, Y: 0}, {X: 10, Y: 0}, {X: 10, Y: 10}, {X: 0, Y: 10}}
+
+	tests := []struct {
+		p       Point
+		measure LinearMeasure
+		want    float64
+	}{
+		{Point{X: 3, Y: -2}, MeasureDistance, 3},
+		{Point{X: 12, Y: 4}, MeasureDistance, 14},
+		{Point{X: -5, Y: -5}, MeasureDistance, 0},
+		{Point{X: -5, Y: 11}, MeasureDistance, 30},
+		// equally close to the first and last segments
+		{Point{X: 5, Y: 5}, MeasureDistance, 5},
+		{Point{X: 12, Y: 4}, MeasureFraction, 14.0 / 30},
+	}
+
+	for _, tt := range tests {
+		if got := line.LocatePoint(tt.p, tt.measure); math.Abs(got-tt.want) > 1e-12 {
+			t.Errorf("LocatePoint(%v, %v), want %v got %v", tt.p, tt.measure, tt.want, got)
+		}
+	}
+
+	// locating an interpolated point gives back its position
+	for m := 0.0; m <= 30; m += 2.5 {
+		if got := line.LocatePoint(line.InterpolatePoint(m, MeasureDistance), MeasureDistance); math.Abs(got-m) > 1e-12 {
+			t.Errorf("LocatePoint(InterpolatePoint(%v)), want %v got %v", m, m, got)
+		}
+	}
+
+	if got := (LineString{{X: 1, Y: 1}, {X: 1, Y: 1}}).LocatePoint(Point{}, MeasureFraction); got != 0 {
+		t.Errorf("LocatePoint({0 0}, MeasureFraction) on a zero-length line, want 0 got %v", got)
+	}
+}
+
+func TestLineSubstring(t *testing.T) {
+	line := LineString{{X: 0, Y: 0}, {X: 10, Y: 0}, {X: 10, Y: 10}, {X: 0, Y: 10}}
+
+	tests := []struct {
+		name     string
+		from, to float64
+		measure  LinearMeasure
+		want     LineString
+	}{
+		{"within a segment", 2, 7, MeasureDistance, LineString{{X: 2, Y: 0}, {X: 7, Y: 0}}},
+		{"across vertices", 5, 25, MeasureDistance, LineString{{X: 5, Y: 0}, {X: 10, Y: 0}, {X: 10, Y: 10}, {X: 5, Y: 10}}},
+		{"from a vertex to a vertex", 10, 20, MeasureDistance, LineString{{X: 10, Y: 0}, {X: 10, Y: 10}}},
+		{"whole line", -5, 50, MeasureDistance, line},
+		{"reversed", 25, 5, MeasureDistance, LineString{{X: 5, Y: 10}, {X: 10, Y: 10}, {X: 10, Y: 0}, {X: 5, Y: 0}}},
+		{"fractions", 0.5, 1, MeasureFraction, LineString{{X: 10, Y: 5}, {X: 10, Y: 10}, {X: 0, Y: 10}}},
+		{"equal positions", 15, 15, MeasureDistance, LineString{{X: 10, Y: 5}, {X: 10, Y: 5}}},
+	}
+
+	for _, tt := range tests {
+		got := line.LineSubstring(tt.from, tt.to, tt.measure)
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("LineSubstring(%v, %v) %s, want %v got %v", tt.from, tt.to, tt.name, tt.want, got)
+		}
+	}
+
+	if got := (LineString{}).LineSubstring(0, 1, MeasureFraction); len(got) != 0 {
+		t.Errorf("LineSubstring(0, 1, MeasureFraction) on an empty line, want an empty line got %v", got)
+	}
+}
+
+func TestMeasuredLineString(t *testing.T) {
+	// kilometre posts counting up along the first two segments and down along the last one
+	ml := MeasuredLineString{
+		Line:     LineString{{X: 0, Y: 0}, {X: 10, Y: 0}, {X: 10, Y: 10}, {X: 0, Y: 10}},
+		Measures: []float64{100, 110, 130, 120},
+	}
+
+	interpolations := []struct {
+		m    float64
+		want Point
+		ok   bool
+	}{
+		{100, Point{X: 0, Y: 0}, true},
+		{105, Point{X: 5, Y: 0}, true},
+		{120, Point{X: 10, Y: 5}, true},
+		{125, Point{X: 10, Y: 7.5}, true},
+		{130, Point{X: 10, Y: 10}, true},
+		{99, Point{}, false},
+		{131, Point{}, false},
+	}
+
+	for _, tt := range interpolations {
+		if got, ok := ml.InterpolatePoint(tt.m); ok != tt.ok || !got.equals(tt.want) {
+			t.Errorf("InterpolatePoint(%v), want %v (%v) got %v (%v)", tt.m, tt.want, tt.ok, got, ok)
+		}
+	}
+
+	locations := []struct {
+		p    Point
+		want float64
+	}{
+		{Point{X: 3, Y: -2}, 103},
+		{Point{X: 12, Y: 4}, 118},
+		{Point{X: 5, Y: 12}, 125},
+		{Point{X: -5, Y: 11}, 120},
+	}
+
+	for _, tt := range locations {
+		if got, ok := ml.LocatePoint(tt.p); !ok || math.Abs(got-tt.want) > 1e-12 {
+			t.Errorf("LocatePoint(%v), want %v got %v (%v)", tt.p, tt.want, got, ok)
+		}
+	}
+
+	substrings := []struct {
+		from, to float64
+		want     MeasuredLineString
+	}{
+		{105, 125, MeasuredLineString{LineString{{X: 5, Y: 0}, {X: 10, Y: 0}, {X: 10, Y: 7.5}}, []float64{105, 110, 125}}},
+		{125, 105, MeasuredLineString{LineString{{X: 10, Y: 7.5}, {X: 10, Y: 0}, {X: 5, Y: 0}}, []float64{125, 110, 105}}},
+		{110, 110, MeasuredLineString{LineString{{X: 10, Y: 0}, {X: 10, Y: 0}}, []float64{110, 110}}},
+	}
+
+	for _, tt := range substrings {
+		got, ok := ml.LineSubstring(tt.from, tt.to)
+		if !ok || !slices.Equal(got.Line, tt.want.Line) || !slices.Equal(got.Measures, tt.want.Measures) {
+			t.Errorf("LineSubstring(%v, %v), want %v got %v (%v)", tt.from, tt.to, tt.want, got, ok)
+		}
+	}
+
+	if _, ok := ml.LineSubstring(90, 110); ok {
+		t.Error("LineSubstring(90, 110), want no substring for a measure missing from the line")
+	}
+
+	unknown := MeasuredLineString{Line: ml.Line[:2], Measures: []float64{100, math.NaN()}}
+	if _, ok := unknown.InterpolatePoint(100); !ok {
+		t.Error("InterpolatePoint(100), want the start of a line with an unknown end measure")
+	}
+	if _, ok := unknown.LocatePoint(Point{X: 5, Y: 0}); ok {
+		t.Error("LocatePoint({5 0}), want no measure on a segment with an unknown measure")
+	}
+
+	if _, ok := (MeasuredLineString{Line: ml.Line, Measures: ml.Measures[:2]}).InterpolatePoint(105); ok {
+		t.Error("InterpolatePoint(105), want no point on a line missing measures")
+	}
+}
+
+func TestFeatureMeasuredLines(t *testing.T) {
+	f := Feature{
+		Type:        "MultiLineString",
+		Coordinates: Polygon{{{X: 0, Y: 0}, {X: 10, Y: 0}}, {{X: 0, Y: 5}, {X: 0, Y: 10}}},
+		Measures:    [][]float64{{0, 10}, {20, 25}},
+	}
+
+	lines, err := f.MeasuredLines()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(lines) != 2 {
+		t.Fatalf("MeasuredLines(), want 2 lines got %d", len(lines))
+	}
+
+	if p, ok := lines[1].InterpolatePoint(22.5); !ok || !p.equals(Point{X: 0, Y: 7.5}) {
+		t.Errorf("InterpolatePoint(22.5) on the second line, want {0 7.5} got %v (%v)", p, ok)
+	}
+
+	f.Measures = f.Measures[:1]
+	if _, err = f.MeasuredLines(); err == nil {
+		t.Error("MeasuredLines(), want an error for a line without measures")
+	}
+
+	f.Measures = [][]float64{{0, 10}, {20}}
+	if _, err = f.MeasuredLines(); err == nil {
+		t.Error("MeasuredLines(), want an error for a line missing a measure")
+	}
+}
@@ -0,0 +1,338 @@
+package gegography
+
+import (
+	"math"
+	"sort"
+)
+
+// lines returns the coordinates of a LineString or MultiLineString feature as a list of lines
+func (f *Feature) lines() ([]MultiPoint, error) {
+	switch f.Type {
+	case "LineString":
+		return []MultiPoint{f.Coordinates.(MultiPoint)}, nil
+	case "MultiLineString":
+		return f.Coordinates.(Polygon), nil
+	}
+
+	return nil, GeoTypeError{Type: f.Type}
+}
+
+// isZeroLength reports whether every point of a line is equal to the first
+func isZeroLength(l MultiPoint) bool {
+	for x := range l {
+		if !l[x].equals(l[0]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// mergeLines joins lines meeting end to end at points where exactly two line ends meet, reversing lines where
+// needed. Lines are never joined where three or more ends meet. Each merged line keeps the direction of the first
+// of its parts in the input.
+func mergeLines(lines []MultiPoint) []MultiPoint {
+	// ends maps a point to the line ends found there, as the index of the line times two, plus one for the last point
+	ends := make(map[Point][]int)
+	for x, l := range lines {
+		ends[l[0]] = append(ends[l[0]], 2*x)
+		ends[l[len(l)-1]] = append(ends[l[len(l)-1]], 2*x+1)
+	}
+
+	used := make([]bool, len(lines))
+
+	// continuation returns the unused line continuing from p, as a reference to the line end at p
+	continuation := func(p Point) (int, bool) {
+		refs := ends[p]
+		if len(refs) != 2 {
+			return 0, false
+		}
+
+		for _, r := range refs {
+			if !used[r/2] {
+				return r, true
+			}
+		}
+
+		return 0, false
+	}
+
+	out := make([]MultiPoint, 0)
+
+	for x := range lines {
+		if used[x] {
+			continue
+		}
+
+		used[x] = true
+		merged := append(MultiPoint{}, lines[x]...)
+
+		for {
+			r, ok := continuation(merged[len(merged)-1])
+			if !ok {
+				break
+			}
+
+			used[r/2] = true
+			l := lines[r/2]
+			if r%2 == 1 {
+				l = reverseRing(l)
+			}
+
+			merged = append(merged, l[1:]...)
+		}
+
+		for {
+			r, ok := continuation(merged[0])
+			if !ok {
+				break
+			}
+
+			used[r/2] = true
+			l := lines[r/2]
+			if r%2 == 0 {
+				l = reverseRing(l)
+			}
+
+			merged = append(append(MultiPoint{}, l[:len(l)-1]...), merged...)
+		}
+
+		out = append(out, merged)
+	}
+
+	return out
+}
+
+// LineMerge joins the lines of all features sharing the same values for the given properties into maximal lines,
+// sewing together lines whose ends meet where no other line ends. Lines are neither split nor joined where they
+// cross, see Node for that. Each group becomes one LineString or MultiLineString feature, in the order in which the
+// groups first appear, holding the key properties and the aggregates computed over the features of the group.
+// Without keys, all lines are merged together. Zero-length lines are dropped and only linear features can be merged.
+func (fc *FeatureCollection) LineMerge(keys []string, aggregates []PropertyAggregate) (FeatureCollection, error) {
+	out := fc.emptyCopy()
+
+	if err := validateAggregates(aggregates); err != nil {
+		return FeatureCollection{}, err
+	}
+
+	for _, g := range groupFeatures(fc, keys) {
+		parts := make([]MultiPoint, 0)
+		for _, f := range g {
+			lines, err := f.lines()
+			if err != nil {
+				return FeatureCollection{}, err
+			}
+
+			for _, l := range lines {
+				if !isZeroLength(l) {
+					parts = append(parts, l)
+				}
+			}
+		}
+
+		if len(parts) > 0 {
+			out.AddFeature(lineFeature(mergeLines(parts), g.properties(keys, aggregates)))
+		}
+	}
+
+	return out, nil
+}
+
+// linework holds the lines of a feature collection as noding segments, remembering which segments make up each line
+type linework struct {
+	segs []nodingSegment
+	// lines holds the range of segments of each line, per feature
+	lines [][][2]int
+	tol   float64
+}
+
+// newLinework collects the segments of the lines of every feature, which must all be linear
+func newLinework(fc *FeatureCollection) (*linework, error) {
+	lw := &linework{segs: make([]nodingSegment, 0), lines: make([][][2]int, len(fc.Features))}
+	pts := make([]Point, 0)
+
+	for x := range fc.Features {
+		lines, err := fc.Features[x].lines()
+		if err != nil {
+			return nil, err
+		}
+
+		for _, l := range lines {
+			first := len(lw.segs)
+			for y := 1; y < len(l); y++ {
+				if !l[y-1].equals(l[y]) {
+					lw.segs = append(lw.segs, nodingSegment{a: l[y-1], b: l[y], geom: len(lw.segs)})
+				}
+			}
+
+			if len(lw.segs) > first {
+				lw.lines[x] = append(lw.lines[x], [2]int{first, len(lw.segs)})
+			}
+
+			pts = append(pts, l...)
+		}
+	}
+
+	lw.tol = nodingTolerance(pts)
+
+	return lw, nil
+}
+
+// Node splits the lines of every feature at each point where they cross or touch a line of the collection,
+// including their own, so that lines only meet at their ends. Lines overlapping each other are split where the
+// overlap starts and ends. Each feature becomes a LineString or MultiLineString holding its pieces in order, with
+// the properties of the input. Intersection points are added as vertices and points closer than a small tolerance,
+// relative to the magnitude of the coordinates, are merged. Zero-length lines are dropped and only linear features
+// can be noded.
+func (fc *FeatureCollection) Node() (FeatureCollection, error) {
+	out := fc.emptyCopy()
+
+	lw, err := newLinework(fc)
+	if err != nil {
+		return FeatureCollection{}, err
+	}
+
+	edges, _ := nodeSegments(lw.segs, nil, lw.tol)
+
+	// every segment is its own geometry, so the owners of an edge tell which segments it is a part of
+	segEdges := make([][]*nodedEdge, len(lw.segs))
+	degree := make(map[Point]int)
+
+	for _, e := range edges {
+		degree[e.a]++
+		degree[e.b]++
+
+		for _, o := range e.owners {
+			segEdges[o.geom] = append(segEdges[o.geom], e)
+		}
+	}
+
+	nodes := make(map[Point]bool)
+	for p, d := range degree {
+		if d != 2 {
+			nodes[p] = true
+		}
+	}
+
+	for _, lines := range lw.lines {
+		for _, r := range lines {
+			nodes[lw.segs[r[0]].a] = true
+			nodes[lw.segs[r[1]-1].b] = true
+		}
+	}
+
+	for x := range fc.Features {
+		pieces := make([]MultiPoint, 0)
+
+		for _, r := range lw.lines[x] {
+			piece := MultiPoint{lw.segs[r[0]].a}
+
+			for y := r[0]; y < r[1]; y++ {
+				s := lw.segs[y]
+
+				pts := make([]Point, 0, 2*len(segEdges[y]))
+				for _, e := range segEdges[y] {
+					pts = append(pts, e.a, e.b)
+				}
+
+				dx, dy := s.b.X-s.a.X, s.b.Y-s.a.Y
+				sort.Slice(pts, func(i, j int) bool {
+					return (pts[i].X-s.a.X)*dx+(pts[i].Y-s.a.Y)*dy < (pts[j].X-s.a.X)*dx+(pts[j].Y-s.a.Y)*dy
+				})
+
+				for _, p := range pts {
+					if p.equals(piece[len(piece)-1]) {
+						continue
+					}
+
+					piece = append(piece, p)
+					if nodes[p] {
+						pieces = append(pieces, piece)
+						piece = MultiPoint{p}
+					}
+				}
+			}
+
+			if len(piece) > 1 {
+				pieces = append(pieces, piece)
+			}
+		}
+
+		if len(pieces) > 0 {
+			out.AddFeature(lineFeature(pieces, fc.Features[x].Properties))
+		}
+	}
+
+	return out, nil
+}
+
+// Polygonize builds the polygons enclosed by the lines of the collection, one Polygon feature without properties
+// for each area the lines divide the plane into, apart from the unbounded area outside them. Lines which do not
+// enclose any area, such as dangling lines and lines bridging two enclosed areas, are left out. Linework enclosed by
+// a polygon forms a hole in it. The lines are noded first, as by Node, so they may cross each other. Only linear
+// features can be polygonized.
+func (fc *FeatureCollection) Polygonize() (FeatureCollection, error) {
+	out := fc.emptyCopy()
+
+	lw, err := newLinework(fc)
+	if err != nil {
+		return FeatureCollection{}, err
+	}
+
+	edges, _ := nodeSegments(lw.segs, nil, lw.tol)
+
+	// walking every edge in both directions traces the areas on the left of the edges counter-clockwise and the
+	// outer boundary of each group of connected lines clockwise. Dangling lines and bridges are walked in both
+	// directions within one ring, and are removed as it is split where it touches itself.
+	directed := make([]*overlayEdge, 0, 2*len(edges))
+	for _, e := range edges {
+		directed = append(directed, &overlayEdge{from: e.a, to: e.b}, &overlayEdge{from: e.b, to: e.a})
+	}
+
+	shells := make([]MultiPoint, 0)
+	holes := make([]MultiPoint, 0)
+
+	for _, r := range linkOverlayEdges(directed) {
+		for _, l := range splitRingAtTouches(r) {
+			if isDegenerateRing(ringVertices(l)) {
+				continue
+			}
+
+			if isCCW(l) {
+				shells = append(shells, l)
+			} else {
+				holes = append(holes, l)
+			}
+		}
+	}
+
+	polygons := make([]Polygon, len(shells))
+	areas := make([]float64, len(shells))
+	for x := range shells {
+		polygons[x] = Polygon{shells[x]}
+		areas[x] = signedRingArea(shells[x])
+	}
+
+	// the outer boundary of lines lying inside an area is a hole of the smallest area containing it, apart from the
+	// area enclosed by the boundary itself
+	for _, h := range holes {
+		area := math.Abs(signedRingArea(h))
+
+		best := -1
+		for x := range shells {
+			if areas[x] > area*(1+1e-9) && (best < 0 || areas[x] < areas[best]) && ringInside(h, shells[x]) {
+				best = x
+			}
+		}
+
+		if best >= 0 {
+			polygons[best] = append(polygons[best], h)
+		}
+	}
+
+	for _, p := range polygons {
+		out.AddFeature(Feature{Type: "Polygon", Properties: make(map[string]any), Coordinates: p})
+	}
+
+	return out, nil
+}
@@ -0,0 +1,296 @@
+package gegography
+
+import (
+	"math"
+	"sort"
+	"testing"
+)
+
+func TestLineMerge(t *testing.T) {
+	tests := []struct {
+		name string
+		wkts []string
+		want []string
+	}{
+		{
+			name: "chain in order",
+			wkts: []string{"LINESTRING (0 0, 1 0)", "LINESTRING (1 0, 2 0)", "LINESTRING (2 0, 3 1)"},
+			want: []string{"LINESTRING (0 0, 1 0, 2 0, 3 1)"},
+		},
+		{
+			name: "chain reversed and shuffled",
+			wkts: []string{"LINESTRING (1 0, 2 0)", "LINESTRING (1 0, 0 0)", "LINESTRING (3 1, 2 0)"},
+			want: []string{"LINESTRING (0 0, 1 0, 2 0, 3 1)"},
+		},
+		{
+			name: "ring",
+			wkts: []string{"LINESTRING (0 0, 1 0, 1 1)", "MULTILINESTRING ((1 1, 0 1), (0 0, 0 1))"},
+			want: []string{"LINESTRING (0 0, 1 0, 1 1, 0 1, 0 0)"},
+		},
+		{
+			name: "not joined where three lines meet",
+			wkts: []string{"LINESTRING (0 0, 1 0)", "LINESTRING (1 0, 2 0)", "LINESTRING (1 0, 1 1)", "LINESTRING (1 1, 1 2)"},
+			want: []string{"MULTILINESTRING ((0 0, 1 0), (1 0, 2 0), (1 0, 1 1, 1 2))"},
+		},
+		{
+			name: "not joined where lines cross",
+			wkts: []string{"LINESTRING (0 0, 2 0)", "LINESTRING (1 -1, 1 1)"},
+			want: []string{"MULTILINESTRING ((0 0, 2 0), (1 -1, 1 1))"},
+		},
+		{
+			name: "zero-length line dropped",
+			wkts: []string{"LINESTRING (0 0, 1 0)", "LINESTRING (1 0, 1 0)", "LINESTRING (1 0, 2 0)"},
+			want: []string{"LINESTRING (0 0, 1 0, 2 0)"},
+		},
+	}
+
+	for _, tt := range tests {
+		fc := wktCollection(t, tt.wkts...)
+		fc.Name = "lines"
+
+		got, err := fc.LineMerge(nil, []PropertyAggregate{{Func: AggregateCount}})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if got.Name != "lines" || len(got.Features) != len(tt.want) {
+			t.Errorf("LineMerge() %s, want %d features in lines got %d in %q", tt.name, len(tt.want), len(got.Features), got.Name)
+			continue
+		}
+
+		for x, want := range tt.want {
+			g := got.Features[x]
+			if !sameGeometry(t, g, want) {
+				t.Errorf("LineMerge() %s, want feature %d %s got %s %v", tt.name, x, want, g.Type, g.Coordinates)
+			}
+
+			if g.Properties["count"] != float64(len(tt.wkts)) {
+				t.Errorf("LineMerge() %s, want feature %d with count %d got %v", tt.name, x, len(tt.wkts), g.Properties)
+			}
+		}
+	}
+}
+
+func TestLineMergeGroups(t *testing.T) {
+	fc := wktCollection(t,
+		"LINESTRING (0 0, 1 0)",
+		"LINESTRING (1 0, 2 0)",
+		"LINESTRING (2 0, 3 0)",
+		"LINESTRING (3 0, 4 0)",
+	)
+	for x, road := range []string{"E4", "E6", "E6", "E4"} {
+		fc.Features[x].Properties["road"] = road
+	}
+
+	got, err := fc.LineMerge([]string{"road"}, []PropertyAggregate{{Property: "id", Func: AggregateList, Name: "ids"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []struct {
+		wkt  string
+		road string
+	}{
+		{"MULTILINESTRING ((0 0, 1 0), (3 0, 4 0))", "E4"},
+		{"LINESTRING (1 0, 2 0, 3 0)", "E6"},
+	}
+
+	if len(got.Features) != len(want) {
+		t.Fatalf("LineMerge(road), want %d features got %d", len(want), len(got.Features))
+	}
+
+	for x, w := range want {
+		g := got.Features[x]
+		if !sameGeometry(t, g, w.wkt) || g.Properties["road"] != w.road || len(g.Properties["ids"].([]any)) != 2 {
+			t.Errorf("LineMerge(road), want feature %d %s on %s with 2 ids got %s %v %v", x, w.wkt, w.road, g.Type, g.Coordinates, g.Properties)
+		}
+	}
+
+	bad := wktCollection(t, "LINESTRING (0 0, 1 0)", "POINT (1 1)")
+	if _, err := bad.LineMerge(nil, nil); err == nil {
+		t.Error("LineMerge() with a Point, want an error")
+	}
+}
+
+func TestNode(t *testing.T) {
+	tests := []struct {
+		name string
+		wkts []string
+		want []string
+	}{
+		{
+			name: "crossing",
+			wkts: []string{"LINESTRING (0 0, 2 2)", "LINESTRING (0 2, 2 0)"},
+			want: []string{"MULTILINESTRING ((0 0, 1 1), (1 1, 2 2))", "MULTILINESTRING ((0 2, 1 1), (1 1, 2 0))"},
+		},
+		{
+			name: "touching",
+			wkts: []string{"LINESTRING (0 0, 1 0, 2 0)", "LINESTRING (1 0, 1 1)"},
+			want: []string{"MULTILINESTRING ((0 0, 1 0), (1 0, 2 0))", "LINESTRING (1 0, 1 1)"},
+		},
+		{
+			name: "disjoint",
+			wkts: []string{"LINESTRING (0 0, 1 0, 2 1)", "LINESTRING (0 1, 1 2)"},
+			want: []string{"LINESTRING (0 0, 1 0, 2 1)", "LINESTRING (0 1, 1 2)"},
+		},
+		{
+			name: "overlapping",
+			wkts: []string{"LINESTRING (0 0, 3 0)", "LINESTRING (1 0, 2 0, 2 1)"},
+			want: []string{"MULTILINESTRING ((0 0, 1 0), (1 0, 2 0), (2 0, 3 0))", "MULTILINESTRING ((1 0, 2 0), (2 0, 2 1))"},
+		},
+		{
+			name: "self-crossing",
+			wkts: []string{"LINESTRING (0 0, 2 2, 2 0, 0 2)"},
+			want: []string{"MULTILINESTRING ((0 0, 1 1), (1 1, 2 2, 2 0, 1 1), (1 1, 0 2))"},
+		},
+		{
+			name: "closed ring",
+			wkts: []string{"LINESTRING (0 0, 1 0, 1 1, 0 0)"},
+			want: []string{"LINESTRING (0 0, 1 0, 1 1, 0 0)"},
+		},
+	}
+
+	for _, tt := range tests {
+		fc := wktCollection(t, tt.wkts...)
+
+		got, err := fc.Node()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(got.Features) != len(tt.want) {
+			t.Errorf("Node() %s, want %d features got %d", tt.name, len(tt.want), len(got.Features))
+			continue
+		}
+
+		for x, want := range tt.want {
+			g := got.Features[x]
+			if !sameGeometry(t, g, want) || g.Properties["id"] != float64(x) {
+				t.Errorf("Node() %s, want feature %d %s got %s %v %v", tt.name, x, want, g.Type, g.Coordinates, g.Properties)
+			}
+		}
+	}
+
+	bad := wktCollection(t, "POLYGON ((0 0, 1 0, 1 1, 0 0))")
+	if _, err := bad.Node(); err == nil {
+		t.Error("Node() with a Polygon, want an error")
+	}
+}
+
+func TestPolygonize(t *testing.T) {
+	tests := []struct {
+		name  string
+		wkts  []string
+		areas []float64
+		holes int
+	}{
+		{
+			name:  "square",
+			wkts:  []string{"LINESTRING (0 0, 10 0, 10 10, 0 10, 0 0)"},
+			areas: []float64{100},
+		},
+		{
+			name:  "square from pieces",
+			wkts:  []string{"MULTILINESTRING ((0 0, 10 0), (10 0, 10 10))", "LINESTRING (0 0, 0 10, 10 10)"},
+			areas: []float64{100},
+		},
+		{
+			name:  "shared edge",
+			wkts:  []string{"LINESTRING (0 0, 10 0, 10 10, 0 10, 0 0)", "LINESTRING (5 0, 5 10)"},
+			areas: []float64{50, 50},
+		},
+		{
+			name:  "grid of crossing lines",
+			wkts:  []string{"LINESTRING (-1 1, 4 1)", "LINESTRING (-1 2, 4 2)", "LINESTRING (1 -1, 1 4)", "LINESTRING (2 -1, 2 4)"},
+			areas: []float64{1},
+		},
+		{
+			name:  "dangles and a bridge",
+			wkts:  []string{"LINESTRING (0 0, 4 0, 4 4, 0 4, 0 0)", "LINESTRING (4 2, 6 2)", "LINESTRING (6 0, 8 0, 8 4, 6 4, 6 0)", "LINESTRING (2 2, 3 3)"},
+			areas: []float64{8, 16},
+		},
+		{
+			name:  "nested",
+			wkts:  []string{"LINESTRING (0 0, 10 0, 10 10, 0 10, 0 0)", "LINESTRING (2 2, 4 2, 4 4, 2 4, 2 2)"},
+			areas: []float64{4, 96},
+			holes: 1,
+		},
+		{
+			name:  "nested touching",
+			wkts:  []string{"LINESTRING (0 0, 10 0, 10 10, 0 10, 0 0)", "LINESTRING (0 0, 4 2, 4 4, 2 4, 0 0)"},
+			areas: []float64{8, 92},
+			holes: 1,
+		},
+		{name: "open", wkts: []string{"LINESTRING (0 0, 10 0, 10 10)"}},
+	}
+
+	for _, tt := range tests {
+		fc := wktCollection(t, tt.wkts...)
+
+		got, err := fc.Polygonize()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		areas := make([]float64, 0)
+		holes := 0
+		for _, f := range got.Features {
+			if f.Type != "Polygon" || len(f.Properties) != 0 {
+				t.Errorf("Polygonize() %s, want polygons without properties got a %s with %v", tt.name, f.Type, f.Properties)
+			}
+
+			if err := f.Coordinates.(Polygon).Validate(); err != nil {
+				t.Errorf("Polygonize() %s, want valid polygons got %v: %v", tt.name, f.Coordinates, err)
+			}
+
+			areas = append(areas, f.Area())
+			holes += len(f.Coordinates.(Polygon)) - 1
+		}
+		sort.Float64s(areas)
+
+		if len(areas) != len(tt.areas) || holes != tt.holes {
+			t.Errorf("Polygonize() %s, want areas %v with %d holes got %v with %d", tt.name, tt.areas, tt.holes, areas, holes)
+			continue
+		}
+
+		for x := range areas {
+			if math.Abs(areas[x]-tt.areas[x]) > 1e-9 {
+				t.Errorf("Polygonize() %s, want areas %v got %v", tt.name, tt.areas, areas)
+				break
+			}
+		}
+	}
+
+	bad := wktCollection(t, "POINT (1 1)")
+	if _, err := bad.Polygonize(); err == nil {
+		t.Error("Polygonize() with a Point, want an error")
+	}
+}
+
+func TestPolygonizeNodedBoundaries(t *testing.T) {
+	// the boundaries of overlapping squares, noded and polygonized, cover their union
+	fc := wktCollection(t,
+		"LINESTRING (0 0, 10 0, 10 10, 0 10, 0 0)",
+		"LINESTRING (5 5, 15 5, 15 15, 5 15, 5 5)",
+		"LINESTRING (8 -2, 12 -2, 12 2, 8 2, 8 -2)",
+	)
+
+	noded, err := fc.Node()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := noded.Polygonize()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var area float64
+	for _, f := range got.Features {
+		area += f.Area()
+	}
+
+	if len(got.Features) != 5 || math.Abs(area-187) > 1e-9 {
+		t.Errorf("Polygonize(Node()), want 5 polygons with a total area of 187 got %d with %v", len(got.Features), area)
+	}
+}
@@ -0,0 +1,300 @@
+package gegography
+
+import (
+	"math"
+	"sort"
+)
+
+// MakeValid repairs common defects in a polygon: open rings are closed, repeated points and degenerate
+// rings are removed, self-intersecting (bow-tie) rings are split into simple rings, rings are oriented
+// counter-clockwise for shells and clockwise for holes, and holes outside their shell are moved to the
+// shell containing them (or turned into shells if no shell contains them). Holes crossing their shell or
+// each other are subtracted from the shell, and overlapping parts are merged. Since splitting a ring may
+// produce several polygons the result is always a MultiPolygon.
+func (p Polygon) MakeValid() MultiPolygon {
+	return MultiPolygon{p}.MakeValid()
+}
+
+// MakeValid repairs common defects in a multipolygon, see Polygon.MakeValid
+func (mp MultiPolygon) MakeValid() MultiPolygon {
+	shells := make([]MultiPoint, 0)
+	owners := make([]int, 0)
+	holes := make([]makeValidHole, 0)
+
+	for x := range mp {
+		for y := range mp[x] {
+			loops := repairRing(mp[x][y])
+
+			for z := range loops {
+				if y == 0 {
+					shells = append(shells, loops[z])
+					owners = append(owners, x)
+				} else {
+					holes = append(holes, makeValidHole{ring: loops[z], owner: x})
+				}
+			}
+		}
+	}
+
+	out := make(MultiPolygon, 0, len(shells))
+	for x := range shells {
+		out = append(out, Polygon{orientRing(shells[x], true)})
+	}
+
+	for x := range holes {
+		h := holes[x]
+		best := -1
+		bestArea := math.Inf(1)
+
+		for y := range shells {
+			if !ringInside(h.ring, shells[y]) {
+				continue
+			}
+
+			area := math.Abs(signedRingArea(shells[y]))
+			if owners[y] == h.owner {
+				area = -1 / (1 + area) // always prefer the shell the hole was given with
+			}
+
+			if area < bestArea {
+				best = y
+				bestArea = area
+			}
+		}
+
+		if best < 0 {
+			out = append(out, Polygon{orientRing(h.ring, true)})
+		} else {
+			out[best] = append(out[best], orientRing(h.ring, false))
+		}
+	}
+
+	if len(out.validate()) == 0 {
+		return out
+	}
+
+	// the rings overlap each other, which moving them around cannot fix
+	return overlayRepair(shells, owners, holes)
+}
+
+// makeValidHole is a repaired hole ring and the index of the polygon it was given with
+type makeValidHole struct {
+	ring  MultiPoint
+	owner int
+}
+
+// overlayRepair builds a valid multipolygon from simple rings with the overlay operations. The holes of each
+// polygon are merged and subtracted from the merged shells of the polygon, and the polygons are merged. A hole
+// overlapping none of the shells it was given with is subtracted from the smallest shell containing it instead, or
+// becomes a shell of its own if there is none.
+func overlayRepair(shells []MultiPoint, owners []int, holes []makeValidHole) MultiPolygon {
+	ring := func(r MultiPoint) MultiPolygon { return MultiPolygon{Polygon{orientRing(r, true)}} }
+
+	groupShells := make(map[int][]MultiPolygon)
+	order := make([]int, 0)
+	for x := range shells {
+		if _, ok := groupShells[owners[x]]; !ok {
+			order = append(order, owners[x])
+		}
+
+		groupShells[owners[x]] = append(groupShells[owners[x]], ring(shells[x]))
+	}
+
+	groupHoles := make(map[int][]MultiPolygon)
+	for _, h := range holes {
+		hole := ring(h.ring)
+
+		if own, ok := groupShells[h.owner]; ok && unionAll(own).Intersection(hole).Area() > 0 {
+			groupHoles[h.owner] = append(groupHoles[h.owner], hole)
+			continue
+		}
+
+		best := -1
+		for y := range shells {
+			if ringInside(h.ring, shells[y]) && (best < 0 || math.Abs(signedRingArea(shells[y])) < math.Abs(signedRingArea(shells[best]))) {
+				best = y
+			}
+		}
+
+		if best < 0 {
+			// the hole becomes a polygon of its own, under a group no input polygon uses
+			owner := -1 - len(order)
+			order = append(order, owner)
+			groupShells[owner] = []MultiPolygon{hole}
+			continue
+		}
+
+		groupHoles[owners[best]] = append(groupHoles[owners[best]], hole)
+	}
+
+	parts := make([]MultiPolygon, 0, len(order))
+	for _, g := range order {
+		part := unionAll(groupShells[g])
+		if len(groupHoles[g]) > 0 {
+			part = part.Difference(unionAll(groupHoles[g]))
+		}
+
+		parts = append(parts, part)
+	}
+
+	return unionAll(parts)
+}
+
+// MakeValid repairs the geometry of a Polygon or MultiPolygon feature in place, see Polygon.MakeValid.
+// A repaired polygon which ends up with more than one part becomes a MultiPolygon. If the repaired
+// geometry is still invalid, the feature is left unchanged and the validity errors are returned.
+func (f *Feature) MakeValid() error {
+	var mp MultiPolygon
+
+	switch f.Type {
+	case "Polygon":
+		mp = f.Coordinates.(Polygon).MakeValid()
+	case "MultiPolygon":
+		mp = f.Coordinates.(MultiPolygon).MakeValid()
+	default:
+		return GeoTypeError{Type: f.Type}
+	}
+
+	if err := mp.Validate(); err != nil {
+		return err
+	}
+
+	if f.Type == "Polygon" && len(mp) == 1 {
+		f.Coordinates = mp[0]
+		return nil
+	}
+
+	f.Type = "MultiPolygon"
+	f.Coordinates = mp
+
+	return nil
+}
+
+// orientRing returns a closed copy of the ring, counter-clockwise if ccw is true and clockwise otherwise
+func orientRing(r MultiPoint, ccw bool) MultiPoint {
+	r = closeRing(r)
+
+	if isCCW(r) != ccw {
+		return reverseRing(r)
+	}
+
+	return r
+}
+
+// repairRing removes invalid and repeated points from a ring and splits it into simple, closed rings
+// at every point where it touches or crosses itself. Rings without area are dropped.
+func repairRing(r MultiPoint) []MultiPoint {
+	pts := make(MultiPoint, 0, len(r))
+	for x := range r {
+		if r[x].isFinite() {
+			pts = append(pts, r[x])
+		}
+	}
+
+	pts = ringVertices(pts)
+	if len(pts) < 3 {
+		return nil
+	}
+
+	loops := splitRingLoops(nodeRing(pts))
+	out := make([]MultiPoint, 0, len(loops))
+
+	for x := range loops {
+		l := ringVertices(loops[x])
+		if len(l) < 3 || isDegenerateRing(l) {
+			continue
+		}
+
+		out = append(out, closeRing(l))
+	}
+
+	return out
+}
+
+// isDegenerateRing reports whether a ring has no meaningful area relative to its size
+func isDegenerateRing(r MultiPoint) bool {
+	var perimeter float64
+	for x := range r {
+		perimeter += distance(r[x], r[(x+1)%len(r)])
+	}
+
+	return math.Abs(signedRingArea(r)) <= 1e-12*perimeter*perimeter
+}
+
+// nodeRing inserts every point where the edges of an implicitly closed ring intersect each other as a vertex
+func nodeRing(ring MultiPoint) MultiPoint {
+	n := len(ring)
+	splits := make([][]Point, n)
+
+	for i := range n {
+		a1 := ring[i]
+		a2 := ring[(i+1)%n]
+
+		for j := i + 1; j < n; j++ {
+			b1 := ring[j]
+			b2 := ring[(j+1)%n]
+
+			is := intersectSegments(a1, a2, b1, b2)
+			if is.Kind == noIntersection {
+				continue
+			}
+
+			for _, p := range is.Points {
+				splits[i] = append(splits[i], p)
+				splits[j] = append(splits[j], p)
+			}
+		}
+	}
+
+	out := make(MultiPoint, 0, n)
+
+	for i := range n {
+		a := ring[i]
+		out = append(out, a)
+
+		s := splits[i]
+		sort.Slice(s, func(x, y int) bool {
+			return distance(a, s[x]) < distance(a, s[y])
+		})
+
+		for x := range s {
+			if !s[x].equals(out[len(out)-1]) && !s[x].equals(ring[(i+1)%n]) {
+				out = append(out, s[x])
+			}
+		}
+	}
+
+	return out
+}
+
+// splitRingLoops decomposes a noded ring into loops which do not revisit any vertex
+func splitRingLoops(ring MultiPoint) []MultiPoint {
+	loops := make([]MultiPoint, 0)
+	path := make(MultiPoint, 0, len(ring))
+	seen := make(map[Point]int)
+
+	visit := func(p Point) {
+		if i, ok := seen[p]; ok {
+			loop := make(MultiPoint, len(path)-i)
+			copy(loop, path[i:])
+			loops = append(loops, loop)
+
+			for x := i + 1; x < len(path); x++ {
+				delete(seen, path[x])
+			}
+			path = path[:i+1]
+
+			return
+		}
+
+		seen[p] = len(path)
+		path = append(path, p)
+	}
+
+	for x := range ring {
+		visit(ring[x])
+	}
+	visit(ring[0])
+
+	return loops
+}
@@ -0,0 +1,58 @@
+package gegography
+
+import (
+	"math"
+	"testing"
+)
+
+func TestMakeValid(t *testing.T) {
+	tests := []struct {
+		wkt   string
+		parts int
+		area  float64
+	}{
+		{"POLYGON ((0 0, 10 0, 10 10, 0 10))", 1, 100},
+		// bow-tie
+		{"POLYGON ((0 0, 10 10, 10 0, 0 10, 0 0))", 2, 50},
+		{"POLYGON ((0 0, 0 10, 10 10, 10 10, 10 0, 0 0), (2 2, 2 4, 4 4, 4 2, 2 2))", 1, 96},
+		// hole outside its shell, moved to a shell of its own
+		{"POLYGON ((0 0, 10 0, 10 10, 0 10, 0 0), (20 20, 24 20, 24 24, 20 24, 20 20))", 2, 116},
+		{"MULTIPOLYGON (((0 0, 10 0, 10 10, 0 10, 0 0), (22 22, 23 22, 23 23, 22 22)), ((20 20, 24 20, 24 24, 20 24, 20 20)), ((5 5, 6 6, 7 7, 5 5)))", 2, 115.5},
+		// hole crossing its shell
+		{"POLYGON ((0 0, 10 0, 10 10, 0 10, 0 0), (8 8, 12 8, 12 12, 8 12, 8 8))", 1, 96},
+		// overlapping holes
+		{"POLYGON ((0 0, 10 0, 10 10, 0 10, 0 0), (2 2, 6 2, 6 6, 2 6, 2 2), (4 4, 8 4, 8 8, 4 8, 4 4))", 1, 72},
+		// overlapping parts
+		{"MULTIPOLYGON (((0 0, 10 0, 10 10, 0 10, 0 0)), ((5 5, 15 5, 15 15, 5 15, 5 5)))", 1, 175},
+	}
+
+	for _, test := range tests {
+		f := mustParseWKT(t, test.wkt)
+
+		if err := f.MakeValid(); err != nil {
+			t.Fatalf("MakeValid(%s), want no error got %v", test.wkt, err)
+		}
+
+		if err := f.Validate(); err != nil {
+			t.Errorf("MakeValid(%s), want valid result got %v", test.wkt, err)
+		}
+
+		parts := 1
+		if mp, ok := f.Coordinates.(MultiPolygon); ok {
+			parts = len(mp)
+		}
+
+		if parts != test.parts {
+			t.Errorf("MakeValid(%s), want %d parts got %d", test.wkt, test.parts, parts)
+		}
+
+		if a := f.Area(); math.Abs(a-test.area) > 1e-9 {
+			t.Errorf("MakeValid(%s), want area %v got %v", test.wkt, test.area, a)
+		}
+	}
+
+	line := mustParseWKT(t, "LINESTRING (0 0, 1 1)")
+	if err := line.MakeValid(); err == nil {
+		t.Error("MakeValid(LINESTRING), want an error got nil")
+	}
+}
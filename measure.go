@@ -0,0 +1,385 @@
+package gegography
+
+import (
+	"math"
+	"sort"
+)
+
+// Length returns the planar length of the line
+func (ls LineString) Length() float64 {
+	var l float64
+
+	for x := 1; x < len(ls); x++ {
+		l += distance(ls[x-1], ls[x])
+	}
+
+	return l
+}
+
+// Centroid returns the length-weighted centroid of the line
+func (ls LineString) Centroid() Point {
+	return linesCentroid([]MultiPoint{MultiPoint(ls)})
+}
+
+// PointOnSurface returns the vertex of the line closest to its centroid
+func (ls LineString) PointOnSurface() Point {
+	return closestVertex(MultiPoint(ls), ls.Centroid())
+}
+
+// Centroid returns the average of all points
+func (mp MultiPoint) Centroid() Point {
+	var c Point
+
+	if len(mp) == 0 {
+		return c
+	}
+
+	for x := range mp {
+		c.X += mp[x].X
+		c.Y += mp[x].Y
+	}
+
+	c.X /= float64(len(mp))
+	c.Y /= float64(len(mp))
+
+	return c
+}
+
+// PointOnSurface returns the point closest to the centroid of all points
+func (mp MultiPoint) PointOnSurface() Point {
+	return closestVertex(mp, mp.Centroid())
+}
+
+// Area returns the planar area of the polygon, with the area of its holes subtracted
+func (p Polygon) Area() float64 {
+	var a float64
+
+	for x := range p {
+		ra := math.Abs(signedRingArea(p[x]))
+		if x == 0 {
+			a += ra
+		} else {
+			a -= ra
+		}
+	}
+
+	return a
+}
+
+// Perimeter returns the total length of all rings of the polygon
+func (p Polygon) Perimeter() float64 {
+	var l float64
+
+	for x := range p {
+		l += LineString(closeRing(p[x])).Length()
+	}
+
+	return l
+}
+
+// Centroid returns the center of mass of the polygon. Polygons without area fall back to the centroid of their rings.
+func (p Polygon) Centroid() Point {
+	return MultiPolygon{p}.Centroid()
+}
+
+// PointOnSurface returns a point guaranteed to lie inside the polygon, suitable for placing a label
+func (p Polygon) PointOnSurface() Point {
+	if len(p) == 0 {
+		return Point{}
+	}
+
+	b := p.Bounds()
+	scanY := scanLineY(p, b.Center().Y)
+	xs := make([]float64, 0)
+
+	for x := range p {
+		r := closeRing(p[x])
+
+		for y := 1; y < len(r); y++ {
+			a := r[y-1]
+			c := r[y]
+
+			if (a.Y > scanY) != (c.Y > scanY) {
+				xs = append(xs, a.X+(scanY-a.Y)*(c.X-a.X)/(c.Y-a.Y))
+			}
+		}
+	}
+
+	sort.Float64s(xs)
+
+	best := -1.0
+	var result Point
+
+	for x := 0; x+1 < len(xs); x += 2 {
+		if w := xs[x+1] - xs[x]; w > best {
+			best = w
+			result = Point{X: (xs[x] + xs[x+1]) / 2, Y: scanY}
+		}
+	}
+
+	if best < 0 {
+		return closestVertex(p[0], p.Centroid())
+	}
+
+	return result
+}
+
+// scanLineY returns a y-coordinate close to y which does not pass through any vertex of the polygon
+func scanLineY(p Polygon, y float64) float64 {
+	lo, hi := math.Inf(-1), math.Inf(1)
+
+	for x := range p {
+		for _, v := range p[x] {
+			if v.Y <= y && v.Y > lo {
+				lo = v.Y
+			}
+			if v.Y > y && v.Y < hi {
+				hi = v.Y
+			}
+		}
+	}
+
+	if math.IsInf(lo, 0) || math.IsInf(hi, 0) {
+		return y
+	}
+
+	return (lo + hi) / 2
+}
+
+// Area returns the planar area of all polygons
+func (mp MultiPolygon) Area() float64 {
+	var a float64
+
+	for x := range mp {
+		a += mp[x].Area()
+	}
+
+	return a
+}
+
+// Perimeter returns the total length of all rings of all polygons
+func (mp MultiPolygon) Perimeter() float64 {
+	var l float64
+
+	for x := range mp {
+		l += mp[x].Perimeter()
+	}
+
+	return l
+}
+
+// Centroid returns the center of mass of all polygons. Multipolygons without area fall back to the centroid of their rings.
+func (mp MultiPolygon) Centroid() Point {
+	var cx, cy, area float64
+	rings := make([]MultiPoint, 0)
+
+	for x := range mp {
+		for y := range mp[x] {
+			r := closeRing(mp[x][y])
+			rings = append(rings, r)
+
+			a := signedRingArea(r)
+			if a == 0 {
+				continue
+			}
+
+			// holes are subtracted regardless of the orientation they were given in
+			s := 1.0
+			if (y == 0) != (a > 0) {
+				s = -1
+			}
+
+			o := r[0]
+			for z := 1; z < len(r)-1; z++ {
+				ta := s * cross(o, r[z], r[z+1]) / 2
+				cx += ta * (o.X + r[z].X + r[z+1].X) / 3
+				cy += ta * (o.Y + r[z].Y + r[z+1].Y) / 3
+				area += ta
+			}
+		}
+	}
+
+	if area == 0 {
+		return linesCentroid(rings)
+	}
+
+	return Point{X: cx / area, Y: cy / area}
+}
+
+// PointOnSurface returns a point guaranteed to lie inside the largest polygon
+func (mp MultiPolygon) PointOnSurface() Point {
+	best := -1
+	var largest float64
+
+	for x := range mp {
+		if a := mp[x].Area(); best < 0 || a > largest {
+			best = x
+			largest = a
+		}
+	}
+
+	if best < 0 {
+		return Point{}
+	}
+
+	return mp[best].PointOnSurface()
+}
+
+// linesCentroid returns the length-weighted centroid of a set of lines, falling back to the average of their
+// vertices if they have no length
+func linesCentroid(lines []MultiPoint) Point {
+	var cx, cy, length float64
+	all := make(MultiPoint, 0)
+
+	for x := range lines {
+		l := lines[x]
+		all = append(all, l...)
+
+		for y := 1; y < len(l); y++ {
+			d := distance(l[y-1], l[y])
+			cx += d * (l[y-1].X + l[y].X) / 2
+			cy += d * (l[y-1].Y + l[y].Y) / 2
+			length += d
+		}
+	}
+
+	if length == 0 {
+		return all.Centroid()
+	}
+
+	return Point{X: cx / length, Y: cy / length}
+}
+
+// closestVertex returns the point closest to p
+func closestVertex(pts MultiPoint, p Point) Point {
+	best := math.Inf(1)
+	var result Point
+
+	for x := range pts {
+		if d := distance(pts[x], p); d < best {
+			best = d
+			result = pts[x]
+		}
+	}
+
+	return result
+}
+
+// Area returns the planar area of a Polygon or MultiPolygon feature, and 0 for other geometry types
+func (f *Feature) Area() float64 {
+	switch f.Type {
+	case "Polygon":
+		return f.Coordinates.(Polygon).Area()
+	case "MultiPolygon":
+		return f.Coordinates.(MultiPolygon).Area()
+	}
+
+	return 0
+}
+
+// Length returns the planar length of a LineString or MultiLineString feature, and 0 for other geometry types
+func (f *Feature) Length() float64 {
+	switch f.Type {
+	case "LineString":
+		return LineString(f.Coordinates.(MultiPoint)).Length()
+	case "MultiLineString":
+		var l float64
+		for _, ls := range f.Coordinates.(Polygon) {
+			l += LineString(ls).Length()
+		}
+		return l
+	}
+
+	return 0
+}
+
+// Perimeter returns the length of the boundary of a Polygon or MultiPolygon feature, and 0 for other geometry types
+func (f *Feature) Perimeter() float64 {
+	switch f.Type {
+	case "Polygon":
+		return f.Coordinates.(Polygon).Perimeter()
+	case "MultiPolygon":
+		return f.Coordinates.(MultiPolygon).Perimeter()
+	}
+
+	return 0
+}
+
+// isEmpty reports whether the feature geometry has no coordinates
+func (f *Feature) isEmpty() bool {
+	switch f.Type {
+	case "MultiPoint", "LineString":
+		return len(f.Coordinates.(MultiPoint)) == 0
+	case "Polygon", "MultiLineString":
+		for _, r := range f.Coordinates.(Polygon) {
+			if len(r) > 0 {
+				return false
+			}
+		}
+	case "MultiPolygon":
+		for _, p := range f.Coordinates.(MultiPolygon) {
+			for _, r := range p {
+				if len(r) > 0 {
+					return false
+				}
+			}
+		}
+	default:
+		return false
+	}
+
+	return true
+}
+
+// Centroid returns the centroid of the feature geometry
+func (f *Feature) Centroid() (Point, error) {
+	if f.isEmpty() {
+		return Point{}, GeoFormatError{Msg: "empty geometry has no centroid"}
+	}
+
+	switch f.Type {
+	case "Point":
+		return f.Coordinates.(Point), nil
+	case "MultiPoint":
+		return f.Coordinates.(MultiPoint).Centroid(), nil
+	case "LineString":
+		return LineString(f.Coordinates.(MultiPoint)).Centroid(), nil
+	case "Polygon":
+		return f.Coordinates.(Polygon).Centroid(), nil
+	case "MultiLineString":
+		return linesCentroid(f.Coordinates.(Polygon)), nil
+	case "MultiPolygon":
+		return f.Coordinates.(MultiPolygon).Centroid(), nil
+	}
+
+	return Point{}, GeoTypeError{Type: f.Type}
+}
+
+// PointOnSurface returns a point guaranteed to lie on the feature geometry, suitable for placing a label
+func (f *Feature) PointOnSurface() (Point, error) {
+	if f.isEmpty() {
+		return Point{}, GeoFormatError{Msg: "empty geometry has no point on surface"}
+	}
+
+	switch f.Type {
+	case "Point":
+		return f.Coordinates.(Point), nil
+	case "MultiPoint":
+		return f.Coordinates.(MultiPoint).PointOnSurface(), nil
+	case "LineString":
+		return LineString(f.Coordinates.(MultiPoint)).PointOnSurface(), nil
+	case "Polygon":
+		return f.Coordinates.(Polygon).PointOnSurface(), nil
+	case "MultiLineString":
+		lines := f.Coordinates.(Polygon)
+		all := make(MultiPoint, 0)
+		for x := range lines {
+			all = append(all, lines[x]...)
+		}
+		return closestVertex(all, linesCentroid(lines)), nil
+	case "MultiPolygon":
+		return f.Coordinates.(MultiPolygon).PointOnSurface(), nil
+	}
+
+	return Point{}, GeoTypeError{Type: f.Type}
+}
@@ -0,0 +1,63 @@
+package gegography
+
+import (
+	"math"
+	"testing"
+)
+
+func TestPolygonMeasurements(t *testing.T) {
+	f, err := ParseWKT("POLYGON ((0 0, 10 0, 10 10, 0 10, 0 0), (2 2, 2 4, 4 4, 4 2, 2 2))")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if a := f.Area(); a != 96 {
+		t.Errorf("Area(), want 96 got %v", a)
+	}
+
+	if p := f.Perimeter(); p != 48 {
+		t.Errorf("Perimeter(), want 48 got %v", p)
+	}
+
+	c, err := f.Centroid()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := Point{X: (100*5 - 4*3) / 96.0, Y: (100*5 - 4*3) / 96.0}
+	if math.Abs(c.X-want.X) > 1e-9 || math.Abs(c.Y-want.Y) > 1e-9 {
+		t.Errorf("Centroid(), want %v got %v", want, c)
+	}
+}
+
+func TestPointOnSurface(t *testing.T) {
+	f, err := ParseWKT("POLYGON ((0 0, 10 0, 10 2, 2 2, 2 8, 10 8, 10 10, 0 10, 0 0))")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c, _ := f.Centroid()
+	if locatePointInPolygon(c, f.Coordinates.(Polygon)) == locInterior {
+		t.Fatalf("Centroid(), test polygon should have its centroid outside the polygon")
+	}
+
+	p, err := f.PointOnSurface()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if locatePointInPolygon(p, f.Coordinates.(Polygon)) != locInterior {
+		t.Errorf("PointOnSurface(), want point inside polygon got %v", p)
+	}
+}
+
+func TestLineLength(t *testing.T) {
+	f, err := ParseWKT("MULTILINESTRING ((0 0, 3 4), (10 10, 10 20))")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if l := f.Length(); l != 15 {
+		t.Errorf("Length(), want 15 got %v", l)
+	}
+}
@@ -0,0 +1,380 @@
+package gegography
+
+import (
+	"container/heap"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// NetworkOptions controls how a network is built from linear features
+type NetworkOptions struct {
+	// Tolerance is the distance, in coordinate units, within which line ends are joined into one node. Zero joins
+	// only ends which are equal, up to a small tolerance relative to the magnitude of the coordinates.
+	Tolerance float64
+	// WeightProperty is the name of a numeric property holding the cost of traversing each feature, shared between
+	// the parts of multi-part features in proportion to their length. If empty, the cost is the length.
+	WeightProperty string
+	// Geodesic takes the coordinates to be WGS84 longitude/latitude and measures lengths in metres along the
+	// ellipsoid
+	Geodesic bool
+	// Directed makes every edge traversable only from the first to the last point of its line
+	Directed bool
+}
+
+// NetworkEdge is an edge of a network, following one line of a feature between two nodes
+type NetworkEdge struct {
+	From, To int
+	// Feature is the index of the feature the edge was made from
+	Feature int
+	Weight  float64
+	Line    MultiPoint
+}
+
+// Network is a graph built from linear features for routing. Nodes are made where line ends meet, and every line
+// becomes an edge between the nodes at its ends; lines crossing or touching elsewhere are not connected, so they
+// should be split with Node first when that is wanted.
+type Network struct {
+	Nodes []Point
+	Edges []NetworkEdge
+	// adjacent holds the edges leaving each node
+	adjacent [][]int
+	geodesic bool
+	// heuristic scales the straight-line distance between two nodes to a lower bound for the cost of any path
+	// between them
+	heuristic float64
+	index     *SpatialIndex
+}
+
+// NetworkPath is a route through a network
+type NetworkPath struct {
+	Nodes []int
+	Edges []int
+	Cost  float64
+}
+
+// NewNetwork builds a routable network from LineString and MultiLineString features. Zero-length lines are
+// skipped. An error is returned for other geometry types and, when weights are taken from a property, for features
+// where it is missing, not a number or negative.
+func NewNetwork(fc *FeatureCollection, opts NetworkOptions) (*Network, error) {
+	n := &Network{
+		Nodes:    make([]Point, 0),
+		Edges:    make([]NetworkEdge, 0),
+		adjacent: make([][]int, 0),
+		geodesic: opts.Geodesic,
+	}
+
+	features := make([][]MultiPoint, len(fc.Features))
+	ends := make([]Point, 0)
+
+	for x := range fc.Features {
+		lines, err := fc.Features[x].lines()
+		if err != nil {
+			return nil, err
+		}
+
+		for _, l := range lines {
+			if !isZeroLength(l) {
+				features[x] = append(features[x], l)
+				ends = append(ends, l[0], l[len(l)-1])
+			}
+		}
+	}
+
+	snap := newSnapper(max(opts.Tolerance, nodingTolerance(ends)))
+	nodes := make(map[Point]int)
+
+	node := func(p Point) int {
+		p = snap.snap(p)
+
+		id, ok := nodes[p]
+		if !ok {
+			id = len(n.Nodes)
+			nodes[p] = id
+			n.Nodes = append(n.Nodes, p)
+			n.adjacent = append(n.adjacent, nil)
+		}
+
+		return id
+	}
+
+	for x, lines := range features {
+		if len(lines) == 0 {
+			continue
+		}
+
+		lengths := make([]float64, len(lines))
+		var total float64
+		for y, l := range lines {
+			lengths[y] = n.length(l)
+			total += lengths[y]
+		}
+
+		weights := lengths
+		if opts.WeightProperty != "" {
+			w, ok := numericValue(fc.Features[x].Properties[opts.WeightProperty])
+			if !ok || w < 0 || math.IsNaN(w) {
+				return nil, GeoFormatError{Msg: fmt.Sprintf("feature %d has no valid %s weight", x, opts.WeightProperty)}
+			}
+
+			weights = make([]float64, len(lines))
+			for y := range lines {
+				weights[y] = w * lengths[y] / total
+			}
+		}
+
+		for y, l := range lines {
+			e := NetworkEdge{From: node(l[0]), To: node(l[len(l)-1]), Feature: x, Weight: weights[y], Line: l}
+			id := len(n.Edges)
+			n.Edges = append(n.Edges, e)
+
+			n.adjacent[e.From] = append(n.adjacent[e.From], id)
+			if !opts.Directed && e.To != e.From {
+				n.adjacent[e.To] = append(n.adjacent[e.To], id)
+			}
+		}
+	}
+
+	// the cost of an edge is at least this multiple of the distance between its nodes, and by the triangle
+	// inequality so is the cost of any path
+	n.heuristic = math.Inf(1)
+	for _, e := range n.Edges {
+		if d := n.distance(n.Nodes[e.From], n.Nodes[e.To]); d > 0 {
+			n.heuristic = math.Min(n.heuristic, e.Weight/d)
+		}
+	}
+
+	if math.IsInf(n.heuristic, 1) {
+		n.heuristic = 0
+	}
+
+	n.index = &SpatialIndex{bounds: make(map[int]BBox, len(n.Nodes))}
+	entries := make([]rtreeEntry, len(n.Nodes))
+	for x, p := range n.Nodes {
+		entries[x] = rtreeEntry{id: x, bounds: BBox{MinX: p.X, MinY: p.Y, MaxX: p.X, MaxY: p.Y}}
+		n.index.bounds[x] = entries[x].bounds
+	}
+	n.index.root = bulkLoad(entries)
+
+	return n, nil
+}
+
+// length returns the length of a line in the units of the network
+func (n *Network) length(l MultiPoint) float64 {
+	if n.geodesic {
+		return LineString(l).GeodesicLength()
+	}
+
+	return LineString(l).Length()
+}
+
+// distance returns the distance between two points in the units of the network
+func (n *Network) distance(a, b Point) float64 {
+	if n.geodesic {
+		return GeodesicDistance(a, b)
+	}
+
+	return distance(a, b)
+}
+
+// NearestNode returns the node closest to p by planar distance, or -1 if the network has no nodes
+func (n *Network) NearestNode(p Point) int {
+	if ids := n.index.Nearest(p, 1); len(ids) > 0 {
+		return ids[0]
+	}
+
+	return -1
+}
+
+// other returns the node at the other end of an edge leaving node
+func (n *Network) other(edge, node int) int {
+	if e := n.Edges[edge]; e.From != node {
+		return e.From
+	}
+
+	return n.Edges[edge].To
+}
+
+type networkItem struct {
+	node     int
+	cost     float64
+	priority float64
+}
+
+type networkQueue []networkItem
+
+func (q networkQueue) Len() int           { return len(q) }
+func (q networkQueue) Less(i, j int) bool { return q[i].priority < q[j].priority }
+func (q networkQueue) Swap(i, j int)      { q[i], q[j] = q[j], q[i] }
+func (q *networkQueue) Push(x any)        { *q = append(*q, x.(networkItem)) }
+func (q *networkQueue) Pop() any {
+	old := *q
+	item := old[len(old)-1]
+	*q = old[:len(old)-1]
+
+	return item
+}
+
+// search visits the nodes reachable from the sources in order of increasing cost plus estimate, until visit
+// returns false. It returns the cost of reaching every visited node and the edge it was reached along, -1 for the
+// sources. Nodes costing more than limit are not visited.
+func (n *Network) search(sources []int, limit float64, estimate func(node int) float64, visit func(node int) bool) (map[int]float64, map[int]int) {
+	costs := make(map[int]float64)
+	via := make(map[int]int)
+	done := make(map[int]bool)
+	q := make(networkQueue, 0)
+
+	for _, s := range sources {
+		if s >= 0 && s < len(n.Nodes) {
+			costs[s] = 0
+			via[s] = -1
+			heap.Push(&q, networkItem{node: s, priority: estimate(s)})
+		}
+	}
+
+	for q.Len() > 0 {
+		item := heap.Pop(&q).(networkItem)
+		if done[item.node] {
+			continue
+		}
+
+		done[item.node] = true
+		if !visit(item.node) {
+			break
+		}
+
+		for _, id := range n.adjacent[item.node] {
+			next := n.other(id, item.node)
+			cost := item.cost + n.Edges[id].Weight
+
+			if c, ok := costs[next]; cost > limit || done[next] || (ok && c <= cost) {
+				continue
+			}
+
+			costs[next] = cost
+			via[next] = id
+			heap.Push(&q, networkItem{node: next, cost: cost, priority: cost + estimate(next)})
+		}
+	}
+
+	for node := range costs {
+		if !done[node] {
+			delete(costs, node)
+			delete(via, node)
+		}
+	}
+
+	return costs, via
+}
+
+// ShortestPath returns the cheapest path between two nodes, found with the A* algorithm guided by the distance to
+// the destination. It reports false if there is no path, or either node does not exist.
+func (n *Network) ShortestPath(from, to int) (NetworkPath, bool) {
+	if to < 0 || to >= len(n.Nodes) {
+		return NetworkPath{}, false
+	}
+
+	target := n.Nodes[to]
+	estimate := func(node int) float64 {
+		if n.heuristic == 0 {
+			return 0
+		}
+
+		return n.heuristic * n.distance(n.Nodes[node], target)
+	}
+
+	costs, via := n.search([]int{from}, math.Inf(1), estimate, func(node int) bool { return node != to })
+
+	cost, ok := costs[to]
+	if !ok {
+		return NetworkPath{}, false
+	}
+
+	path := NetworkPath{Nodes: []int{to}, Edges: make([]int, 0), Cost: cost}
+	for node := to; via[node] >= 0; {
+		path.Edges = append(path.Edges, via[node])
+		node = n.other(via[node], node)
+		path.Nodes = append(path.Nodes, node)
+	}
+
+	for x, y := 0, len(path.Nodes)-1; x < y; x, y = x+1, y-1 {
+		path.Nodes[x], path.Nodes[y] = path.Nodes[y], path.Nodes[x]
+	}
+	for x, y := 0, len(path.Edges)-1; x < y; x, y = x+1, y-1 {
+		path.Edges[x], path.Edges[y] = path.Edges[y], path.Edges[x]
+	}
+
+	return path, true
+}
+
+// PathLine returns the geometry of a path, following the lines of its edges in the direction of travel
+func (n *Network) PathLine(p NetworkPath) MultiPoint {
+	out := make(MultiPoint, 0)
+	if len(p.Nodes) > 0 {
+		out = append(out, n.Nodes[p.Nodes[0]])
+	}
+
+	for x, id := range p.Edges {
+		e := n.Edges[id]
+		l := e.Line
+		if e.From != p.Nodes[x] {
+			l = reverseRing(l)
+		}
+
+		// the line ends may have been snapped to the nodes, which are used in their place
+		out = append(out, l[1:len(l)-1]...)
+		out = append(out, n.Nodes[p.Nodes[x+1]])
+	}
+
+	return out
+}
+
+// ServiceArea returns the cost of reaching every node that can be reached from the nearest of the sources at a
+// cost of at most maxCost, keyed by node
+func (n *Network) ServiceArea(sources []int, maxCost float64) map[int]float64 {
+	costs, _ := n.search(sources, maxCost, func(int) float64 { return 0 }, func(int) bool { return true })
+
+	return costs
+}
+
+// ConnectedComponents returns the nodes of each group of nodes connected to each other, ignoring the direction of
+// edges. The nodes of each component are in ascending order, and components are ordered by their first node.
+func (n *Network) ConnectedComponents() [][]int {
+	component := make([]int, len(n.Nodes))
+	for x := range component {
+		component[x] = -1
+	}
+
+	// neighbours lists the edges at each node in both directions
+	neighbours := make([][]int, len(n.Nodes))
+	for id, e := range n.Edges {
+		neighbours[e.From] = append(neighbours[e.From], id)
+		neighbours[e.To] = append(neighbours[e.To], id)
+	}
+
+	out := make([][]int, 0)
+
+	for start := range n.Nodes {
+		if component[start] >= 0 {
+			continue
+		}
+
+		c := len(out)
+		nodes := []int{start}
+		component[start] = c
+
+		for x := 0; x < len(nodes); x++ {
+			for _, id := range neighbours[nodes[x]] {
+				if next := n.other(id, nodes[x]); component[next] < 0 {
+					component[next] = c
+					nodes = append(nodes, next)
+				}
+			}
+		}
+
+		sort.Ints(nodes)
+		out = append(out, nodes)
+	}
+
+	return out
+}
@@ -0,0 +1,246 @@
+package gegography
+
+import (
+	"math"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+// gridNetwork returns the lines of a size by size grid of unit squares, one line per side of each square
+func gridNetwork(t *testing.T, size int) FeatureCollection {
+	t.Helper()
+
+	fc := NewFeatureCollection()
+	for x := range size + 1 {
+		for y := range size + 1 {
+			p := Point{X: float64(x), Y: float64(y)}
+			if x < size {
+				fc.AddFeature(Feature{Type: "LineString", Properties: map[string]any{"cost": 1.0}, Coordinates: MultiPoint{p, {X: p.X + 1, Y: p.Y}}})
+			}
+			if y < size {
+				fc.AddFeature(Feature{Type: "LineString", Properties: map[string]any{"cost": 1.0}, Coordinates: MultiPoint{p, {X: p.X, Y: p.Y + 1}}})
+			}
+		}
+	}
+
+	return fc
+}
+
+func TestNetworkShortestPath(t *testing.T) {
+	fc := wktCollection(t,
+		"LINESTRING (0 0, 5 0)",
+		"LINESTRING (5 0, 5 5)",
+		"LINESTRING (0 0, 0 5, 5 5)",
+		"LINESTRING (5 5, 2 2, 0 0)",
+		"LINESTRING (5 5, 10 5)",
+		"MULTILINESTRING ((20 0, 21 0), (21 0, 22 0))",
+	)
+
+	n, err := NewNetwork(&fc, NetworkOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(n.Nodes) != 7 || len(n.Edges) != 7 {
+		t.Fatalf("NewNetwork(), want 7 nodes and 7 edges got %d and %d", len(n.Nodes), len(n.Edges))
+	}
+
+	from, to := n.NearestNode(Point{X: -1, Y: -1}), n.NearestNode(Point{X: 11, Y: 5})
+
+	path, ok := n.ShortestPath(from, to)
+	if !ok {
+		t.Fatalf("ShortestPath(%d, %d), want a path got none", from, to)
+	}
+
+	want := math.Sqrt(8) + math.Sqrt(18) + 5
+	if math.Abs(path.Cost-want) > 1e-9 || !slices.Equal(path.Edges, []int{3, 4}) {
+		t.Errorf("ShortestPath(%d, %d), want a path along [3 4] costing %v got %v costing %v", from, to, want, path.Edges, path.Cost)
+	}
+
+	line := n.PathLine(path)
+	if !slices.Equal(line, MultiPoint{{X: 0, Y: 0}, {X: 2, Y: 2}, {X: 5, Y: 5}, {X: 10, Y: 5}}) {
+		t.Errorf("PathLine(), want [{0 0} {2 2} {5 5} {10 5}] got %v", line)
+	}
+
+	if math.Abs(LineString(line).Length()-path.Cost) > 1e-9 {
+		t.Errorf("PathLine(), want length %v got %v", path.Cost, LineString(line).Length())
+	}
+
+	if _, ok := n.ShortestPath(from, n.NearestNode(Point{X: 20, Y: 0})); ok {
+		t.Error("ShortestPath() to a disconnected node, want no path got one")
+	}
+
+	if p, ok := n.ShortestPath(from, from); !ok || p.Cost != 0 || len(p.Edges) != 0 {
+		t.Errorf("ShortestPath(%d, %d), want an empty path costing 0 got %v (%v)", from, from, p, ok)
+	}
+
+	if _, ok := n.ShortestPath(from, 100); ok {
+		t.Error("ShortestPath() to node 100, want no path to a node that does not exist got one")
+	}
+}
+
+func TestNetworkOptions(t *testing.T) {
+	fc := wktCollection(t,
+		"LINESTRING (0 0, 10 0)",
+		"LINESTRING (10 0.001, 10 10)",
+		"LINESTRING (0 0, 0 10)",
+		"LINESTRING (0 10, 10 10)",
+	)
+	for x, c := range []float64{1, 1, 5, 5} {
+		fc.Features[x].Properties["minutes"] = c
+	}
+
+	tests := []struct {
+		name  string
+		opts  NetworkOptions
+		nodes int
+		cost  float64
+	}{
+		{name: "exact ends", opts: NetworkOptions{}, nodes: 5, cost: 20},
+		// the cost is the length of the lines, which are not moved to the nodes
+		{name: "snapped ends", opts: NetworkOptions{Tolerance: 0.01}, nodes: 4, cost: 19.999},
+		{name: "weights", opts: NetworkOptions{Tolerance: 0.01, WeightProperty: "minutes"}, nodes: 4, cost: 2},
+		{name: "directed", opts: NetworkOptions{Tolerance: 0.01, Directed: true}, nodes: 4, cost: 19.999},
+	}
+
+	for _, tt := range tests {
+		n, err := NewNetwork(&fc, tt.opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(n.Nodes) != tt.nodes {
+			t.Errorf("NewNetwork() %s, want %d nodes got %d", tt.name, tt.nodes, len(n.Nodes))
+		}
+
+		path, ok := n.ShortestPath(n.NearestNode(Point{}), n.NearestNode(Point{X: 10, Y: 10}))
+		if !ok || math.Abs(path.Cost-tt.cost) > 1e-6 {
+			t.Errorf("ShortestPath() %s, want a path costing %v got %v (%v)", tt.name, tt.cost, path.Cost, ok)
+		}
+
+		if tt.opts.Directed {
+			if _, ok := n.ShortestPath(n.NearestNode(Point{X: 10, Y: 10}), n.NearestNode(Point{})); ok {
+				t.Errorf("ShortestPath() %s, want no path against the direction of the lines got one", tt.name)
+			}
+		}
+	}
+
+	fc.Features[2].Properties["minutes"] = "slow"
+	if _, err := NewNetwork(&fc, NetworkOptions{WeightProperty: "minutes"}); err == nil {
+		t.Error("NewNetwork(minutes), want an error for a weight which is not a number")
+	}
+
+	bad := wktCollection(t, "POINT (1 1)")
+	if _, err := NewNetwork(&bad, NetworkOptions{}); err == nil {
+		t.Error("NewNetwork() with a Point, want an error")
+	}
+}
+
+func TestNetworkShortestPathAgainstDijkstra(t *testing.T) {
+	fc := gridNetwork(t, 12)
+
+	// remove some streets and make others slower
+	r := rand.New(rand.NewSource(1))
+	lines := NewFeatureCollection()
+	for _, f := range fc.Features {
+		if r.Intn(4) > 0 {
+			f.Properties = map[string]any{"cost": 1 + 3*r.Float64()}
+			lines.AddFeature(f)
+		}
+	}
+
+	for _, prop := range []string{"", "cost"} {
+		n, err := NewNetwork(&lines, NetworkOptions{WeightProperty: prop})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for range 50 {
+			from, to := r.Intn(len(n.Nodes)), r.Intn(len(n.Nodes))
+			costs := n.ServiceArea([]int{from}, math.Inf(1))
+
+			path, ok := n.ShortestPath(from, to)
+			want, reachable := costs[to]
+
+			if ok != reachable || math.Abs(path.Cost-want) > 1e-9 {
+				t.Errorf("ShortestPath(%d, %d), want cost %v (%v) got %v (%v)", from, to, want, reachable, path.Cost, ok)
+			}
+
+			var sum float64
+			for _, e := range path.Edges {
+				sum += n.Edges[e].Weight
+			}
+
+			if math.Abs(sum-path.Cost) > 1e-9 {
+				t.Errorf("ShortestPath(%d, %d), want edges costing %v got %v", from, to, path.Cost, sum)
+			}
+		}
+	}
+}
+
+func TestNetworkServiceArea(t *testing.T) {
+	fc := gridNetwork(t, 4)
+
+	n, err := NewNetwork(&fc, NetworkOptions{WeightProperty: "cost"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	centre := n.NearestNode(Point{X: 2, Y: 2})
+	costs := n.ServiceArea([]int{centre}, 2)
+
+	if len(costs) != 13 {
+		t.Errorf("ServiceArea(%d, 2), want 13 nodes got %d", centre, len(costs))
+	}
+
+	for node, c := range costs {
+		p := n.Nodes[node]
+		if want := math.Abs(p.X-2) + math.Abs(p.Y-2); c != want {
+			t.Errorf("ServiceArea(%d, 2), want node %v costing %v got %v", centre, p, want, c)
+		}
+	}
+
+	corners := []int{n.NearestNode(Point{}), n.NearestNode(Point{X: 4, Y: 4})}
+	if costs := n.ServiceArea(corners, 1); len(costs) != 6 {
+		t.Errorf("ServiceArea(%v, 1), want 6 nodes got %d", corners, len(costs))
+	}
+}
+
+func TestNetworkConnectedComponents(t *testing.T) {
+	fc := wktCollection(t,
+		"LINESTRING (0 0, 1 0)",
+		"LINESTRING (5 5, 6 5)",
+		"LINESTRING (1 0, 1 1)",
+		"LINESTRING (6 5, 5 5)",
+		"LINESTRING (9 9, 9 8, 9 9)",
+	)
+
+	n, err := NewNetwork(&fc, NetworkOptions{Directed: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := n.ConnectedComponents()
+	want := [][]int{{0, 1, 4}, {2, 3}, {5}}
+
+	if !slices.EqualFunc(got, want, slices.Equal) {
+		t.Errorf("ConnectedComponents(), want %v got %v", want, got)
+	}
+}
+
+func TestNetworkGeodesic(t *testing.T) {
+	fc := wktCollection(t, "LINESTRING (18 59, 18 60)", "LINESTRING (18 60, 19 60)")
+
+	n, err := NewNetwork(&fc, NetworkOptions{Geodesic: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	path, ok := n.ShortestPath(0, 2)
+	want := GeodesicDistance(Point{X: 18, Y: 59}, Point{X: 18, Y: 60}) + GeodesicDistance(Point{X: 18, Y: 60}, Point{X: 19, Y: 60})
+
+	if !ok || math.Abs(path.Cost-want) > 1e-6 {
+		t.Errorf("ShortestPath(0, 2) geodesic, want cost %v got %v (%v)", want, path.Cost, ok)
+	}
+}
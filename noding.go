@@ -0,0 +1,247 @@
+package gegography
+
+import (
+	"math"
+	"sort"
+)
+
+// snapper merges points closer than a tolerance, so that intersection points computed for different pairs of
+// segments passing through the same position become a single node. The first point added at a position is kept, so
+// adding the input vertices first keeps them exact.
+type snapper struct {
+	tol   float64
+	cells map[[2]int64][]Point
+}
+
+func newSnapper(tol float64) *snapper {
+	return &snapper{tol: tol, cells: make(map[[2]int64][]Point)}
+}
+
+// snapCellSize is the size of the grid cells used to look up nodes, as a multiple of the tolerance. Cells larger
+// than the tolerance mean most points only need to be compared with the nodes of their own cell.
+const snapCellSize = 16
+
+func (s *snapper) cell(p Point) [2]int64 {
+	return [2]int64{int64(math.Floor(p.X / (s.tol * snapCellSize))), int64(math.Floor(p.Y / (s.tol * snapCellSize)))}
+}
+
+// snap returns the node at the position of p, adding p as a new node if there is none
+func (s *snapper) snap(p Point) Point {
+	c := s.cell(p)
+
+	for _, q := range s.cells[c] {
+		if distance(p, q) <= s.tol {
+			return q
+		}
+	}
+
+	// only look in the neighbouring cells when p is near their border
+	lo, hi := s.cell(Point{X: p.X - s.tol, Y: p.Y - s.tol}), s.cell(Point{X: p.X + s.tol, Y: p.Y + s.tol})
+
+	for cx := lo[0]; cx <= hi[0]; cx++ {
+		for cy := lo[1]; cy <= hi[1]; cy++ {
+			if cx == c[0] && cy == c[1] {
+				continue
+			}
+
+			for _, q := range s.cells[[2]int64{cx, cy}] {
+				if distance(p, q) <= s.tol {
+					return q
+				}
+			}
+		}
+	}
+
+	s.cells[c] = append(s.cells[c], p)
+
+	return p
+}
+
+// nodingTolerance returns the snapping tolerance for points, relative to the magnitude of their coordinates
+func nodingTolerance(pts []Point) float64 {
+	scale := 1.0
+
+	for _, p := range pts {
+		scale = math.Max(scale, math.Max(math.Abs(p.X), math.Abs(p.Y)))
+	}
+
+	return scale * 1e-10
+}
+
+// closestPointOnSegment returns the point of the segment a-b closest to p
+func closestPointOnSegment(p, a, b Point) Point {
+	dx, dy := b.X-a.X, b.Y-a.Y
+	l2 := dx*dx + dy*dy
+	if l2 == 0 {
+		return a
+	}
+
+	t := math.Max(0, math.Min(1, ((p.X-a.X)*dx+(p.Y-a.Y)*dy)/l2))
+
+	return Point{X: a.X + t*dx, Y: a.Y + t*dy}
+}
+
+// segmentDistance returns the distance from p to the segment a-b
+func segmentDistance(p, a, b Point) float64 {
+	return distance(p, closestPointOnSegment(p, a, b))
+}
+
+// nodingSegment is an input segment of nodeSegments, belonging to geometry geom. Area segments are parts of
+// polygon rings, which are expected to be oriented with the interior on their left.
+type nodingSegment struct {
+	a, b Point
+	geom int
+	area bool
+}
+
+// edgeOwner records a segment lying on a noded edge
+type edgeOwner struct {
+	geom    int
+	area    bool
+	forward bool
+}
+
+// nodedEdge is an edge of the planar graph formed by noding segments. Its endpoints are nodes and its interior
+// neither crosses nor touches any other edge. Segments which overlap share the edge, each recorded as an owner.
+// The endpoints are ordered so that a is less than b by X, then Y.
+type nodedEdge struct {
+	a, b   Point
+	owners []edgeOwner
+}
+
+// owned reports whether a segment of geometry geom lies on the edge, and whether one runs from a to b and one from
+// b to a
+func (e *nodedEdge) owned(geom int) (owned, forward, backward bool) {
+	for _, o := range e.owners {
+		if o.geom == geom {
+			owned = true
+			forward = forward || o.forward
+			backward = backward || !o.forward
+		}
+	}
+
+	return owned, forward, backward
+}
+
+func (e *nodedEdge) midpoint() Point {
+	return Point{X: (e.a.X + e.b.X) / 2, Y: (e.a.Y + e.b.Y) / 2}
+}
+
+func lessPoint(a, b Point) bool {
+	return a.X < b.X || (a.X == b.X && a.Y < b.Y)
+}
+
+// nodeSegments splits segments at every point where they intersect each other or pass through one of the given
+// points, returning the resulting planar graph as a list of edges. Intersection points closer than tol are merged.
+func nodeSegments(segs []nodingSegment, points []Point, tol float64) ([]*nodedEdge, *snapper) {
+	snap := newSnapper(tol)
+
+	for x := range segs {
+		segs[x].a = snap.snap(segs[x].a)
+		segs[x].b = snap.snap(segs[x].b)
+	}
+
+	for x := range points {
+		points[x] = snap.snap(points[x])
+	}
+
+	splits := make([][]Point, len(segs))
+
+	// sweep the segments and points in order of their smallest X to find pairs with overlapping extents
+	type item struct {
+		minX, maxX, minY, maxY float64
+		seg                    int
+		point                  Point
+	}
+
+	items := make([]item, 0, len(segs)+len(points))
+	for x, s := range segs {
+		items = append(items, item{math.Min(s.a.X, s.b.X), math.Max(s.a.X, s.b.X), math.Min(s.a.Y, s.b.Y), math.Max(s.a.Y, s.b.Y), x, Point{}})
+	}
+	for _, p := range points {
+		items = append(items, item{p.X, p.X, p.Y, p.Y, -1, p})
+	}
+
+	sort.Slice(items, func(i, j int) bool { return items[i].minX < items[j].minX })
+
+	for i := range items {
+		a := items[i]
+
+		for j := i + 1; j < len(items) && items[j].minX <= a.maxX+tol; j++ {
+			b := items[j]
+			if b.minY > a.maxY+tol || a.minY > b.maxY+tol || (a.seg < 0 && b.seg < 0) {
+				continue
+			}
+
+			if a.seg < 0 || b.seg < 0 {
+				p, s := a.point, b.seg
+				if a.seg >= 0 {
+					p, s = b.point, a.seg
+				}
+
+				if segmentDistance(p, segs[s].a, segs[s].b) <= tol {
+					splits[s] = append(splits[s], p)
+				}
+
+				continue
+			}
+
+			sa, sb := segs[a.seg], segs[b.seg]
+
+			for _, p := range intersectSegments(sa.a, sa.b, sb.a, sb.b).Points {
+				splits[a.seg] = append(splits[a.seg], p)
+				splits[b.seg] = append(splits[b.seg], p)
+			}
+
+			// endpoints which nearly touch the other segment are nodes as well
+			for _, p := range []Point{sb.a, sb.b} {
+				if segmentDistance(p, sa.a, sa.b) <= tol {
+					splits[a.seg] = append(splits[a.seg], p)
+				}
+			}
+			for _, p := range []Point{sa.a, sa.b} {
+				if segmentDistance(p, sb.a, sb.b) <= tol {
+					splits[b.seg] = append(splits[b.seg], p)
+				}
+			}
+		}
+	}
+
+	edges := make([]*nodedEdge, 0, len(segs))
+	index := make(map[[2]Point]*nodedEdge)
+
+	for x, s := range segs {
+		pts := append([]Point{s.a, s.b}, splits[x]...)
+		for y := range pts {
+			pts[y] = snap.snap(pts[y])
+		}
+
+		dx, dy := s.b.X-s.a.X, s.b.Y-s.a.Y
+		sort.Slice(pts, func(i, j int) bool {
+			return (pts[i].X-s.a.X)*dx+(pts[i].Y-s.a.Y)*dy < (pts[j].X-s.a.X)*dx+(pts[j].Y-s.a.Y)*dy
+		})
+
+		for y := 1; y < len(pts); y++ {
+			p, q := pts[y-1], pts[y]
+			if p.equals(q) {
+				continue
+			}
+
+			forward := lessPoint(p, q)
+			if !forward {
+				p, q = q, p
+			}
+
+			e, ok := index[[2]Point{p, q}]
+			if !ok {
+				e = &nodedEdge{a: p, b: q}
+				index[[2]Point{p, q}] = e
+				edges = append(edges, e)
+			}
+
+			e.owners = append(e.owners, edgeOwner{geom: s.geom, area: s.area, forward: forward})
+		}
+	}
+
+	return edges, snap
+}
@@ -0,0 +1,358 @@
+package gegography
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strings"
+	"sync"
+)
+
+// NTv2Grid is a grid shift read from an NTv2 (.gsb) file, converting longitude/latitude from one datum to another
+type NTv2Grid struct {
+	From     string
+	To       string
+	subgrids []ntv2Subgrid
+}
+
+// ntv2Subgrid holds the nodes of a single NTv2 subgrid. Longitudes are positive west and all values are in
+// arc-seconds, as in the file.
+type ntv2Subgrid struct {
+	name       string
+	southLat   float64
+	northLat   float64
+	eastLon    float64
+	westLon    float64
+	latInc     float64
+	lonInc     float64
+	rows       int
+	columns    int
+	latShifts  []float32
+	lonShifts  []float32
+	resolution float64
+}
+
+const ntv2RecordSize = 16
+
+type ntv2Reader struct {
+	data  []byte
+	pos   int
+	order binary.ByteOrder
+}
+
+func (r *ntv2Reader) record(key string) ([]byte, error) {
+	if r.pos+ntv2RecordSize > len(r.data) {
+		return nil, GeoFormatError{Msg: "NTv2 grid is truncated"}
+	}
+
+	rec := r.data[r.pos : r.pos+ntv2RecordSize]
+	r.pos += ntv2RecordSize
+
+	if k := strings.TrimRight(string(rec[:8]), " \u0000"); k != key {
+		return nil, GeoFormatError{Msg: fmt.Sprintf("NTv2 grid is malformed, expected record '%s' got '%s'", key, k)}
+	}
+
+	return rec[8:], nil
+}
+
+func (r *ntv2Reader) int(key string) (int, error) {
+	v, err := r.record(key)
+	if err != nil {
+		return 0, err
+	}
+
+	var i int32
+	err = parseValue(v[:4], r.order, &i)
+
+	return int(i), err
+}
+
+func (r *ntv2Reader) float(key string) (float64, error) {
+	v, err := r.record(key)
+	if err != nil {
+		return 0, err
+	}
+
+	var f float64
+	err = parseValue(v, r.order, &f)
+
+	return f, err
+}
+
+func (r *ntv2Reader) string(key string) (string, error) {
+	v, err := r.record(key)
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(strings.Trim(string(v), "\u0000")), nil
+}
+
+// ReadNTv2 reads an NTv2 grid shift file
+func ReadNTv2(r io.Reader) (*NTv2Grid, error) {
+	buf := &bytes.Buffer{}
+	if _, err := io.Copy(buf, r); err != nil {
+		return nil, err
+	}
+
+	data := buf.Bytes()
+	if len(data) < 11*ntv2RecordSize {
+		return nil, GeoFormatError{Msg: "NTv2 grid is truncated"}
+	}
+
+	nr := &ntv2Reader{data: data, order: binary.LittleEndian}
+	if binary.LittleEndian.Uint32(data[8:12]) != 11 {
+		nr.order = binary.BigEndian
+	}
+
+	if _, err := nr.int("NUM_OREC"); err != nil {
+		return nil, err
+	}
+
+	if _, err := nr.int("NUM_SREC"); err != nil {
+		return nil, err
+	}
+
+	numFiles, err := nr.int("NUM_FILE")
+	if err != nil {
+		return nil, err
+	}
+
+	unit, err := nr.string("GS_TYPE")
+	if err != nil {
+		return nil, err
+	}
+
+	if unit != "SECONDS" {
+		return nil, GeoFormatError{Msg: fmt.Sprintf("NTv2 grid uses unsupported unit '%s'", unit)}
+	}
+
+	grid := &NTv2Grid{}
+
+	if _, err = nr.string("VERSION"); err != nil {
+		return nil, err
+	}
+	if grid.From, err = nr.string("SYSTEM_F"); err != nil {
+		return nil, err
+	}
+	if grid.To, err = nr.string("SYSTEM_T"); err != nil {
+		return nil, err
+	}
+
+	for _, key := range []string{"MAJOR_F", "MINOR_F", "MAJOR_T", "MINOR_T"} {
+		if _, err = nr.float(key); err != nil {
+			return nil, err
+		}
+	}
+
+	for range numFiles {
+		sg, err := nr.subgrid()
+		if err != nil {
+			return nil, err
+		}
+
+		grid.subgrids = append(grid.subgrids, sg)
+	}
+
+	return grid, nil
+}
+
+func (r *ntv2Reader) subgrid() (ntv2Subgrid, error) {
+	var sg ntv2Subgrid
+	var err error
+
+	if sg.name, err = r.string("SUB_NAME"); err != nil {
+		return sg, err
+	}
+
+	for _, key := range []string{"PARENT", "CREATED", "UPDATED"} {
+		if _, err = r.string(key); err != nil {
+			return sg, err
+		}
+	}
+
+	for _, v := range []struct {
+		key string
+		out *float64
+	}{
+		{"S_LAT", &sg.southLat},
+		{"N_LAT", &sg.northLat},
+		{"E_LONG", &sg.eastLon},
+		{"W_LONG", &sg.westLon},
+		{"LAT_INC", &sg.latInc},
+		{"LONG_INC", &sg.lonInc},
+	} {
+		if *v.out, err = r.float(v.key); err != nil {
+			return sg, err
+		}
+	}
+
+	count, err := r.int("GS_COUNT")
+	if err != nil {
+		return sg, err
+	}
+
+	if sg.latInc <= 0 || sg.lonInc <= 0 {
+		return sg, GeoFormatError{Msg: fmt.Sprintf("NTv2 subgrid '%s' has invalid increments", sg.name)}
+	}
+
+	sg.rows = int(math.Round((sg.northLat-sg.southLat)/sg.latInc)) + 1
+	sg.columns = int(math.Round((sg.westLon-sg.eastLon)/sg.lonInc)) + 1
+	sg.resolution = sg.latInc * sg.lonInc
+
+	if sg.rows*sg.columns != count || r.pos+count*ntv2RecordSize > len(r.data) {
+		return sg, GeoFormatError{Msg: fmt.Sprintf("NTv2 subgrid '%s' is malformed", sg.name)}
+	}
+
+	sg.latShifts = make([]float32, count)
+	sg.lonShifts = make([]float32, count)
+
+	for x := range count {
+		rec := r.data[r.pos : r.pos+ntv2RecordSize]
+		r.pos += ntv2RecordSize
+
+		sg.latShifts[x] = math.Float32frombits(r.order.Uint32(rec[0:4]))
+		sg.lonShifts[x] = math.Float32frombits(r.order.Uint32(rec[4:8]))
+	}
+
+	return sg, nil
+}
+
+// LoadNTv2 reads an NTv2 grid shift file from disk
+func LoadNTv2(file string) (*NTv2Grid, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return ReadNTv2(f)
+}
+
+var (
+	gridShiftsMu sync.RWMutex
+	gridShifts   = make(map[string]*NTv2Grid)
+)
+
+// RegisterGridShift sets an NTv2 grid to use for the named datum, shifting longitude/latitude on it to a datum
+// coinciding with WGS84. Definitions on the datum returned afterwards by LookupEPSG, CRS.Definition and
+// ParseCRSWKT carry the grid, so FeatureCollection.Transform uses it in place of the Helmert parameters. Datum names
+// are matched ignoring case, spaces and punctuation, and the known datums by any of their usual names, so a grid
+// registered for "ED50" is also used for "European_Datum_1950". Registering nil removes the grid.
+func RegisterGridShift(datum string, grid *NTv2Grid) {
+	gridShiftsMu.Lock()
+	defer gridShiftsMu.Unlock()
+
+	if grid == nil {
+		delete(gridShifts, gridShiftKey(datum))
+	} else {
+		gridShifts[gridShiftKey(datum)] = grid
+	}
+}
+
+// gridShiftKey returns the name a grid shift is registered under for a datum
+func gridShiftKey(datum string) string {
+	if known, ok := lookupDatum(datum); ok {
+		datum = known.Name
+	}
+
+	return normaliseWKTName(datum)
+}
+
+// withRegisteredGridShift returns the datum with the grid registered for it, if there is one
+func withRegisteredGridShift(d Datum) Datum {
+	gridShiftsMu.RLock()
+	defer gridShiftsMu.RUnlock()
+
+	if grid, ok := gridShifts[gridShiftKey(d.Name)]; ok {
+		d.GridShift = grid
+	}
+
+	return d
+}
+
+func (sg *ntv2Subgrid) contains(lat, lonW float64) bool {
+	return lat >= sg.southLat && lat <= sg.northLat && lonW >= sg.eastLon && lonW <= sg.westLon
+}
+
+// shift returns the bilinearly interpolated latitude and (positive west) longitude shifts in arc-seconds
+func (sg *ntv2Subgrid) shift(lat, lonW float64) (float64, float64) {
+	col := math.Min(math.Floor((lonW-sg.eastLon)/sg.lonInc), float64(sg.columns-2))
+	row := math.Min(math.Floor((lat-sg.southLat)/sg.latInc), float64(sg.rows-2))
+	col = math.Max(col, 0)
+	row = math.Max(row, 0)
+
+	x := (lonW - sg.eastLon - col*sg.lonInc) / sg.lonInc
+	y := (lat - sg.southLat - row*sg.latInc) / sg.latInc
+
+	i := int(row)*sg.columns + int(col)
+	interpolate := func(v []float32) float64 {
+		if sg.columns == 1 || sg.rows == 1 {
+			return float64(v[i])
+		}
+
+		v00 := float64(v[i])
+		v10 := float64(v[i+1])
+		v01 := float64(v[i+sg.columns])
+		v11 := float64(v[i+sg.columns+1])
+
+		return v00 + (v10-v00)*x + (v01-v00)*y + (v11-v10-v01+v00)*x*y
+	}
+
+	return interpolate(sg.latShifts), interpolate(sg.lonShifts)
+}
+
+// find returns the finest subgrid covering a point, or nil if the grid does not cover it
+func (g *NTv2Grid) find(lat, lonW float64) *ntv2Subgrid {
+	var best *ntv2Subgrid
+
+	for x := range g.subgrids {
+		sg := &g.subgrids[x]
+
+		if sg.contains(lat, lonW) && (best == nil || sg.resolution < best.resolution) {
+			best = sg
+		}
+	}
+
+	return best
+}
+
+// Forward shifts a longitude/latitude point (in degrees) from the source datum of the grid to its target datum
+func (g *NTv2Grid) Forward(p Point) (Point, error) {
+	lat := p.Y * 3600
+	lonW := -p.X * 3600
+
+	sg := g.find(lat, lonW)
+	if sg == nil {
+		return Point{}, ProjectionError{Projection: fmt.Sprintf("NTv2 grid %s to %s", g.From, g.To), Point: p}
+	}
+
+	dLat, dLonW := sg.shift(lat, lonW)
+
+	return Point{X: p.X - dLonW/3600, Y: p.Y + dLat/3600}, nil
+}
+
+// Inverse shifts a longitude/latitude point (in degrees) from the target datum of the grid to its source datum
+func (g *NTv2Grid) Inverse(p Point) (Point, error) {
+	guess := p
+
+	for range 10 {
+		f, err := g.Forward(guess)
+		if err != nil {
+			return Point{}, err
+		}
+
+		dx := p.X - f.X
+		dy := p.Y - f.Y
+		guess = Point{X: guess.X + dx, Y: guess.Y + dy}
+
+		if math.Abs(dx) < 1e-12 && math.Abs(dy) < 1e-12 {
+			break
+		}
+	}
+
+	return guess, nil
+}
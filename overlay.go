@@ -0,0 +1,335 @@
+package gegography
+
+import (
+	"math"
+	"sort"
+)
+
+// overlayOp is a boolean operation combining the areas of two polygonal geometries
+type overlayOp int
+
+const (
+	overlayIntersection overlayOp = iota
+	overlayUnion
+	overlayDifference
+	overlaySymDifference
+)
+
+// contains reports whether a point in the interior of a, b, both or neither is part of the result of the operation
+func (op overlayOp) contains(a, b bool) bool {
+	switch op {
+	case overlayIntersection:
+		return a && b
+	case overlayUnion:
+		return a || b
+	case overlayDifference:
+		return a && !b
+	}
+
+	return a != b
+}
+
+// overlayEdge is a directed edge of the boundary of an overlay result, with the result on its left
+type overlayEdge struct {
+	from, to Point
+	used     bool
+}
+
+// overlay computes a boolean operation on two multipolygons. The boundaries of both are noded together into a
+// planar graph, every edge is classified by whether the areas on its left and right sides are part of the result,
+// and the edges separating the result from the rest are linked into rings. Edges shared by both inputs, touching
+// rings and zero-width parts therefore need no special handling.
+func overlay(a, b MultiPolygon, op overlayOp) MultiPolygon {
+	if result, ok := overlayTrivial(a, b, op); ok {
+		return result
+	}
+
+	ga, gb := areaGeometry(a), areaGeometry(b)
+
+	segs := append(ga.segments(0), gb.segments(1)...)
+	tol := nodingTolerance(append(ga.allPoints(), gb.allPoints()...))
+	edges, _ := nodeSegments(segs, nil, tol)
+
+	out := make([]*overlayEdge, 0)
+
+	for _, e := range edges {
+		_, la, ra := ga.locateEdge(e, 0)
+		_, lb, rb := gb.locateEdge(e, 1)
+
+		left := op.contains(la == locInterior, lb == locInterior)
+		right := op.contains(ra == locInterior, rb == locInterior)
+
+		switch {
+		case left && !right:
+			out = append(out, &overlayEdge{from: e.a, to: e.b})
+		case right && !left:
+			out = append(out, &overlayEdge{from: e.b, to: e.a})
+		}
+	}
+
+	rings := make([]MultiPoint, 0)
+	for _, r := range linkOverlayEdges(out) {
+		rings = append(rings, splitRingAtTouches(r)...)
+	}
+
+	for x := range rings {
+		rings[x] = removeCollinear(rings[x])
+	}
+
+	return assembleRings(rings)
+}
+
+// overlayTrivial computes the result of operations with an empty input or inputs whose bounds are disjoint, which
+// need no noding
+func overlayTrivial(a, b MultiPolygon, op overlayOp) (MultiPolygon, bool) {
+	ba, bb := a.Bounds(), b.Bounds()
+
+	if !ba.IsEmpty() && !bb.IsEmpty() && ba.Intersects(bb) {
+		return nil, false
+	}
+
+	switch op {
+	case overlayIntersection:
+		return MultiPolygon{}, true
+	case overlayDifference:
+		return a.rewind(), true
+	}
+
+	return append(a.rewind(), b.rewind()...), true
+}
+
+// linkOverlayEdges links directed edges into closed rings. Arriving at a node, the ring continues along the first
+// outgoing edge clockwise from the edge it arrived along, which keeps every ring tight around the area on its left.
+func linkOverlayEdges(edges []*overlayEdge) []MultiPoint {
+	outgoing := make(map[Point][]*overlayEdge)
+	for _, e := range edges {
+		outgoing[e.from] = append(outgoing[e.from], e)
+	}
+
+	angle := func(from, to Point) float64 {
+		return math.Atan2(to.Y-from.Y, to.X-from.X)
+	}
+
+	next := func(e *overlayEdge) *overlayEdge {
+		back := angle(e.to, e.from)
+
+		var best *overlayEdge
+		bestTurn := math.Inf(1)
+
+		for _, o := range outgoing[e.to] {
+			turn := math.Mod(back-angle(o.from, o.to)+4*math.Pi, 2*math.Pi)
+			if turn == 0 {
+				turn = 2 * math.Pi
+			}
+
+			if !o.used && turn < bestTurn {
+				best, bestTurn = o, turn
+			}
+		}
+
+		return best
+	}
+
+	rings := make([]MultiPoint, 0)
+
+	for _, start := range edges {
+		if start.used {
+			continue
+		}
+
+		ring := MultiPoint{start.from}
+		e := start
+
+		for e != nil {
+			e.used = true
+			ring = append(ring, e.to)
+
+			if e.to.equals(start.from) {
+				rings = append(rings, ring)
+				break
+			}
+
+			e = next(e)
+		}
+	}
+
+	return rings
+}
+
+// splitRingAtTouches splits a closed ring which passes through the same vertex more than once into simple rings
+func splitRingAtTouches(r MultiPoint) []MultiPoint {
+	out := make([]MultiPoint, 0, 1)
+	stack := make(MultiPoint, 0, len(r))
+	seen := make(map[Point]int)
+
+	for _, p := range r {
+		if x, ok := seen[p]; ok {
+			loop := append(append(MultiPoint{}, stack[x:]...), p)
+			if len(loop) > 3 {
+				out = append(out, loop)
+			}
+
+			for _, q := range stack[x+1:] {
+				delete(seen, q)
+			}
+			stack = stack[:x+1]
+
+			continue
+		}
+
+		seen[p] = len(stack)
+		stack = append(stack, p)
+	}
+
+	return out
+}
+
+// removeCollinear removes vertices of a closed ring lying on the straight line through their neighbours
+func removeCollinear(r MultiPoint) MultiPoint {
+	out := make(MultiPoint, 0, len(r))
+
+	for _, p := range ringVertices(r) {
+		for len(out) >= 2 && orientation(out[len(out)-2], out[len(out)-1], p) == 0 {
+			out = out[:len(out)-1]
+		}
+		out = append(out, p)
+	}
+
+	for len(out) > 3 && orientation(out[len(out)-2], out[len(out)-1], out[0]) == 0 {
+		out = out[:len(out)-1]
+	}
+	for len(out) > 3 && orientation(out[len(out)-1], out[0], out[1]) == 0 {
+		out = out[1:]
+	}
+
+	return closeRing(out)
+}
+
+// unionAll returns the union of any number of multipolygons, merging them pairwise in a balanced tree so that the
+// intermediate results stay small
+func unionAll(parts []MultiPolygon) MultiPolygon {
+	switch len(parts) {
+	case 0:
+		return MultiPolygon{}
+	case 1:
+		return parts[0].rewind()
+	}
+
+	// sort by position so that neighbours are merged early
+	parts = append([]MultiPolygon{}, parts...)
+	sort.SliceStable(parts, func(i, j int) bool {
+		return parts[i].Bounds().Center().X < parts[j].Bounds().Center().X
+	})
+
+	var merge func(parts []MultiPolygon) MultiPolygon
+	merge = func(parts []MultiPolygon) MultiPolygon {
+		if len(parts) == 1 {
+			return parts[0]
+		}
+
+		m := len(parts) / 2
+
+		return overlay(merge(parts[:m]), merge(parts[m:]), overlayUnion)
+	}
+
+	return merge(parts)
+}
+
+// Intersection returns the area covered by both polygons
+func (p Polygon) Intersection(o Polygon) MultiPolygon {
+	return overlay(MultiPolygon{p}, MultiPolygon{o}, overlayIntersection)
+}
+
+// Union returns the area covered by either polygon
+func (p Polygon) Union(o Polygon) MultiPolygon {
+	return overlay(MultiPolygon{p}, MultiPolygon{o}, overlayUnion)
+}
+
+// Difference returns the area of the polygon not covered by the other
+func (p Polygon) Difference(o Polygon) MultiPolygon {
+	return overlay(MultiPolygon{p}, MultiPolygon{o}, overlayDifference)
+}
+
+// SymmetricDifference returns the area covered by exactly one of the polygons
+func (p Polygon) SymmetricDifference(o Polygon) MultiPolygon {
+	return overlay(MultiPolygon{p}, MultiPolygon{o}, overlaySymDifference)
+}
+
+// Intersection returns the area covered by both multipolygons
+func (mp MultiPolygon) Intersection(o MultiPolygon) MultiPolygon {
+	return overlay(mp, o, overlayIntersection)
+}
+
+// Union returns the area covered by either multipolygon
+func (mp MultiPolygon) Union(o MultiPolygon) MultiPolygon {
+	return overlay(mp, o, overlayUnion)
+}
+
+// Difference returns the area of the multipolygon not covered by the other
+func (mp MultiPolygon) Difference(o MultiPolygon) MultiPolygon {
+	return overlay(mp, o, overlayDifference)
+}
+
+// SymmetricDifference returns the area covered by exactly one of the multipolygons
+func (mp MultiPolygon) SymmetricDifference(o MultiPolygon) MultiPolygon {
+	return overlay(mp, o, overlaySymDifference)
+}
+
+// polygons returns the coordinates of a Polygon or MultiPolygon feature as a multipolygon
+func (f *Feature) polygons() (MultiPolygon, error) {
+	switch f.Type {
+	case "Polygon":
+		return MultiPolygon{f.Coordinates.(Polygon)}, nil
+	case "MultiPolygon":
+		return f.Coordinates.(MultiPolygon), nil
+	}
+
+	return nil, GeoTypeError{Type: f.Type}
+}
+
+// polygonalFeature returns a feature holding a multipolygon, as a Polygon when it has a single part
+func polygonalFeature(mp MultiPolygon, properties map[string]any) Feature {
+	if len(mp) == 1 {
+		return Feature{Type: "Polygon", Properties: properties, Coordinates: mp[0]}
+	}
+
+	return Feature{Type: "MultiPolygon", Properties: properties, Coordinates: mp}
+}
+
+func (f *Feature) overlay(o *Feature, op overlayOp) (Feature, error) {
+	a, err := f.polygons()
+	if err != nil {
+		return Feature{}, err
+	}
+
+	b, err := o.polygons()
+	if err != nil {
+		return Feature{}, err
+	}
+
+	return polygonalFeature(overlay(a, b, op), f.Properties), nil
+}
+
+// Intersection returns a feature covering the area of both Polygon or MultiPolygon features, with the properties of
+// f. The result is a Polygon when it has a single part, and an empty MultiPolygon when the features
+// do not overlap.
+func (f *Feature) Intersection(o *Feature) (Feature, error) {
+	return f.overlay(o, overlayIntersection)
+}
+
+// Union returns a feature covering the area of either Polygon or MultiPolygon feature, with the properties of
+// f
+func (f *Feature) Union(o *Feature) (Feature, error) {
+	return f.overlay(o, overlayUnion)
+}
+
+// Difference returns a feature covering the area of f not covered by o, with the properties of f
+func (f *Feature) Difference(o *Feature) (Feature, error) {
+	return f.overlay(o, overlayDifference)
+}
+
+// SymmetricDifference returns a feature covering the area of exactly one of the features, with the properties of
+// f
+func (f *Feature) SymmetricDifference(o *Feature) (Feature, error) {
+	return f.overlay(o, overlaySymDifference)
+}
@@ -0,0 +1,197 @@
+package gegography
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestOverlay(t *testing.T) {
+	square := "POLYGON ((0 0, 10 0, 10 10, 0 10, 0 0))"
+
+	tests := []struct {
+		name  string
+		a, b  string
+		op    overlayOp
+		area  float64
+		parts int
+		holes int
+	}{
+		{"overlapping intersection", square, "POLYGON ((5 5, 15 5, 15 15, 5 15, 5 5))", overlayIntersection, 25, 1, 0},
+		{"overlapping union", square, "POLYGON ((5 5, 15 5, 15 15, 5 15, 5 5))", overlayUnion, 175, 1, 0},
+		{"overlapping difference", square, "POLYGON ((5 5, 15 5, 15 15, 5 15, 5 5))", overlayDifference, 75, 1, 0},
+		{"overlapping symmetric difference", square, "POLYGON ((5 5, 15 5, 15 15, 5 15, 5 5))", overlaySymDifference, 150, 2, 0},
+		{"clockwise input", square, "POLYGON ((5 5, 5 15, 15 15, 15 5, 5 5))", overlayIntersection, 25, 1, 0},
+		{"shared edge union", square, "POLYGON ((10 0, 20 0, 20 10, 10 10, 10 0))", overlayUnion, 200, 1, 0},
+		{"shared edge intersection", square, "POLYGON ((10 0, 20 0, 20 10, 10 10, 10 0))", overlayIntersection, 0, 0, 0},
+		{"partly shared edge union", square, "POLYGON ((10 2, 20 2, 20 8, 10 8, 10 2))", overlayUnion, 160, 1, 0},
+		{"corner touching union", square, "POLYGON ((10 10, 20 10, 20 20, 10 20, 10 10))", overlayUnion, 200, 2, 0},
+		{"identical intersection", square, square, overlayIntersection, 100, 1, 0},
+		{"identical union", square, square, overlayUnion, 100, 1, 0},
+		{"identical difference", square, square, overlayDifference, 0, 0, 0},
+		{"identical symmetric difference", square, square, overlaySymDifference, 0, 0, 0},
+		{"disjoint intersection", square, "POLYGON ((20 0, 30 0, 30 10, 20 10, 20 0))", overlayIntersection, 0, 0, 0},
+		{"disjoint union", square, "POLYGON ((20 0, 30 0, 30 10, 20 10, 20 0))", overlayUnion, 200, 2, 0},
+		{"contained difference makes a hole", square, "POLYGON ((2 2, 8 2, 8 8, 2 8, 2 2))", overlayDifference, 64, 1, 1},
+		{"hole touching the boundary", square, "POLYGON ((0 2, 8 2, 8 8, 0 8, 0 2))", overlayDifference, 52, 1, 0},
+		{"hole touching at a vertex", square, "POLYGON ((0 5, 5 2, 8 5, 5 8, 0 5))", overlayDifference, 76, 1, 1},
+		{
+			"intersection with a hole",
+			"POLYGON ((0 0, 10 0, 10 10, 0 10, 0 0), (3 3, 7 3, 7 7, 3 7, 3 3))",
+			"POLYGON ((5 -5, 15 -5, 15 15, 5 15, 5 -5))",
+			overlayIntersection, 42, 1, 0,
+		},
+		{
+			"union filling a hole",
+			"POLYGON ((0 0, 10 0, 10 10, 0 10, 0 0), (3 3, 7 3, 7 7, 3 7, 3 3))",
+			"POLYGON ((2 2, 8 2, 8 8, 2 8, 2 2))",
+			overlayUnion, 100, 1, 0,
+		},
+		{
+			"union inside a hole",
+			"POLYGON ((0 0, 10 0, 10 10, 0 10, 0 0), (2 2, 8 2, 8 8, 2 8, 2 2))",
+			"POLYGON ((4 4, 6 4, 6 6, 4 6, 4 4))",
+			overlayUnion, 68, 2, 1,
+		},
+		{
+			"multipolygon intersection",
+			"MULTIPOLYGON (((0 0, 4 0, 4 4, 0 4, 0 0)), ((6 0, 10 0, 10 4, 6 4, 6 0)))",
+			"POLYGON ((2 -1, 8 -1, 8 2, 2 2, 2 -1))",
+			overlayIntersection, 8, 2, 0,
+		},
+		{
+			"union closing a ring",
+			"POLYGON ((0 0, 10 0, 10 2, 2 2, 2 8, 10 8, 10 10, 0 10, 0 0))",
+			"POLYGON ((8 0, 10 0, 10 10, 8 10, 8 0))",
+			overlayUnion, 64, 1, 1,
+		},
+	}
+
+	for _, tt := range tests {
+		fa, fb := mustParseWKT(t, tt.a), mustParseWKT(t, tt.b)
+		a, _ := fa.polygons()
+		b, _ := fb.polygons()
+
+		got := overlay(a, b, tt.op)
+
+		if math.Abs(got.Area()-tt.area) > 1e-9 {
+			t.Errorf("overlay() %s, want area %v got %v", tt.name, tt.area, got.Area())
+		}
+
+		holes := 0
+		for _, p := range got {
+			holes += len(p) - 1
+		}
+
+		if len(got) != tt.parts || holes != tt.holes {
+			t.Errorf("overlay() %s, want %d parts with %d holes got %d with %d: %v", tt.name, tt.parts, tt.holes, len(got), holes, got)
+		}
+
+		if err := got.Validate(); err != nil {
+			t.Errorf("overlay() %s, want a valid result got %v", tt.name, err)
+		}
+	}
+}
+
+// randomStar returns a star shaped polygon, which is simple but usually far from convex
+func randomStar(r *rand.Rand, n int) Polygon {
+	c := Point{X: r.Float64() * 10, Y: r.Float64() * 10}
+	ring := make(MultiPoint, 0, n+1)
+
+	for x := range n {
+		a := 2 * math.Pi * float64(x) / float64(n)
+		d := 2 + r.Float64()*6
+		ring = append(ring, Point{X: c.X + d*math.Cos(a), Y: c.Y + d*math.Sin(a)})
+	}
+
+	return Polygon{closeRing(ring)}
+}
+
+func TestOverlayAreas(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+
+	for x := range 200 {
+		a, b := randomStar(r, 5+r.Intn(20)), randomStar(r, 5+r.Intn(20))
+
+		i := a.Intersection(b).Area()
+		u := a.Union(b).Area()
+		d := a.Difference(b).Area()
+		s := a.SymmetricDifference(b).Area()
+
+		tol := 1e-9 * (a.Area() + b.Area())
+
+		if math.Abs(u-(a.Area()+b.Area()-i)) > tol {
+			t.Errorf("%d: Union(), want area %v got %v", x, a.Area()+b.Area()-i, u)
+		}
+		if math.Abs(d-(a.Area()-i)) > tol {
+			t.Errorf("%d: Difference(), want area %v got %v", x, a.Area()-i, d)
+		}
+		if math.Abs(s-(u-i)) > tol {
+			t.Errorf("%d: SymmetricDifference(), want area %v got %v", x, u-i, s)
+		}
+
+		for _, mp := range []MultiPolygon{a.Intersection(b), a.Union(b), a.Difference(b)} {
+			if err := mp.Validate(); err != nil {
+				t.Errorf("%d: overlay of %v and %v, want a valid result got %v", x, a, b, err)
+			}
+		}
+	}
+}
+
+func TestFeatureOverlay(t *testing.T) {
+	zone := mustParseWKT(t, "POLYGON ((0 0, 10 0, 10 10, 0 10, 0 0))")
+	zone.Properties["name"] = "zone"
+	municipality := mustParseWKT(t, "MULTIPOLYGON (((5 5, 15 5, 15 15, 5 15, 5 5)), ((-5 -5, 2 -5, 2 2, -5 2, -5 -5)))")
+
+	got, err := zone.Intersection(&municipality)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got.Type != "MultiPolygon" || math.Abs(got.Area()-29) > 1e-9 || got.Properties["name"] != "zone" {
+		t.Errorf("Intersection(municipality), want a MultiPolygon named zone with area 29 got %s %v with area %v", got.Type, got.Properties, got.Area())
+	}
+
+	got, err = zone.Difference(&municipality)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got.Type != "Polygon" || math.Abs(got.Area()-71) > 1e-9 {
+		t.Errorf("Difference(municipality), want a Polygon with area 71 got %s with area %v", got.Type, got.Area())
+	}
+
+	line := mustParseWKT(t, "LINESTRING (0 0, 1 1)")
+	if _, err := zone.Union(&line); err == nil {
+		t.Error("Union(LINESTRING (0 0, 1 1)), want an error for a LineString")
+	}
+}
+
+func TestPointLocatorOverlappingParts(t *testing.T) {
+	// the parts of an invalid multipolygon overlap between x = 5 and x = 10
+	mp := MultiPolygon{
+		mustParseWKT(t, "POLYGON ((0 0, 10 0, 10 10, 0 10, 0 0))").Coordinates.(Polygon),
+		mustParseWKT(t, "POLYGON ((5 0, 15 0, 15 10, 5 10, 5 0))").Coordinates.(Polygon),
+	}
+
+	tests := []struct {
+		p    Point
+		want location
+	}{
+		{Point{X: 2, Y: 5}, locInterior},
+		{Point{X: 7, Y: 5}, locInterior},
+		{Point{X: 10, Y: 5}, locInterior},
+		{Point{X: 5, Y: 5}, locInterior},
+		{Point{X: 15, Y: 5}, locBoundary},
+		{Point{X: 7, Y: 0}, locBoundary},
+		{Point{X: 20, Y: 5}, locExterior},
+	}
+
+	l := newPointLocator(mp)
+	for _, tt := range tests {
+		if got := l.locate(tt.p); got != tt.want {
+			t.Errorf("locate(%v) = %v, want %v", tt.p, got, tt.want)
+		}
+	}
+
+}
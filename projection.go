@@ -0,0 +1,470 @@
+package gegography
+
+import (
+	"fmt"
+	"math"
+)
+
+// Projection converts between geographic coordinates (longitude/latitude in degrees, X being the longitude)
+// and projected coordinates (in metres) on a given ellipsoid
+type Projection interface {
+	Forward(p Point) (Point, error)
+	Inverse(p Point) (Point, error)
+}
+
+// ProjectionError describes a point which cannot be converted by a projection
+type ProjectionError struct {
+	Projection string
+	Point      Point
+}
+
+func (p ProjectionError) Error() string {
+	return fmt.Sprintf("%s: point %s cannot be projected.", p.Projection, p.Point.toWKT())
+}
+
+func checkGeographic(name string, p Point) error {
+	if !p.isFinite() || p.Y < -90 || p.Y > 90 {
+		return ProjectionError{Projection: name, Point: p}
+	}
+
+	return nil
+}
+
+// WebMercator is the spherical Mercator projection used by web maps (EPSG:3857), which projects ellipsoidal
+// coordinates as if they were on a sphere with the radius of the ellipsoid's semi-major axis
+type WebMercator struct {
+	Ellipsoid Ellipsoid
+}
+
+// webMercatorMaxLatitude is the latitude at which Web Mercator maps are cut to make the world square
+const webMercatorMaxLatitude = 85.05112877980659
+
+// Forward projects a longitude/latitude point to Web Mercator. Latitudes beyond ±85.0511° are clamped.
+func (w WebMercator) Forward(p Point) (Point, error) {
+	if err := checkGeographic("Web Mercator", p); err != nil {
+		return Point{}, err
+	}
+
+	lat := math.Max(-webMercatorMaxLatitude, math.Min(webMercatorMaxLatitude, p.Y))
+
+	return Point{
+		X: w.Ellipsoid.A * toRadians(p.X),
+		Y: w.Ellipsoid.A * math.Log(math.Tan(math.Pi/4+toRadians(lat)/2)),
+	}, nil
+}
+
+// Inverse converts a Web Mercator point to longitude/latitude
+func (w WebMercator) Inverse(p Point) (Point, error) {
+	if !p.isFinite() {
+		return Point{}, ProjectionError{Projection: "Web Mercator", Point: p}
+	}
+
+	return Point{
+		X: toDegrees(p.X / w.Ellipsoid.A),
+		Y: toDegrees(math.Pi/2 - 2*math.Atan(math.Exp(-p.Y/w.Ellipsoid.A))),
+	}, nil
+}
+
+// TransverseMercator is the ellipsoidal Transverse Mercator (Gauss-Krüger) projection, computed with Krüger's
+// series to the fourth order of the third flattening, which is accurate to well below a millimetre within the
+// usual width of a zone. Angles are in degrees.
+type TransverseMercator struct {
+	Ellipsoid        Ellipsoid
+	LatitudeOfOrigin float64
+	CentralMeridian  float64
+	ScaleFactor      float64
+	FalseEasting     float64
+	FalseNorthing    float64
+}
+
+// UTM returns the Transverse Mercator projection of a UTM zone on the given ellipsoid
+func UTM(e Ellipsoid, zone int, north bool) TransverseMercator {
+	tm := TransverseMercator{
+		Ellipsoid:       e,
+		CentralMeridian: float64(zone)*6 - 183,
+		ScaleFactor:     0.9996,
+		FalseEasting:    500000,
+	}
+
+	if !north {
+		tm.FalseNorthing = 10000000
+	}
+
+	return tm
+}
+
+// UTMZone returns the UTM zone number containing a longitude
+func UTMZone(longitude float64) int {
+	longitude += wrapShift(longitude)
+	zone := int(math.Floor((longitude+180)/6)) + 1
+
+	return min(zone, 60)
+}
+
+type krugerSeries struct {
+	aHat  float64
+	e2    float64
+	beta  [4]float64
+	delta [4]float64
+}
+
+func (tm TransverseMercator) series() krugerSeries {
+	f := tm.Ellipsoid.F
+	n := f / (2 - f)
+	n2 := n * n
+	n3 := n2 * n
+	n4 := n3 * n
+
+	return krugerSeries{
+		aHat: tm.Ellipsoid.A / (1 + n) * (1 + n2/4 + n4/64),
+		e2:   tm.Ellipsoid.E2(),
+		beta: [4]float64{
+			n/2 - 2*n2/3 + 5*n3/16 + 41*n4/180,
+			13*n2/48 - 3*n3/5 + 557*n4/1440,
+			61*n3/240 - 103*n4/140,
+			49561 * n4 / 161280,
+		},
+		delta: [4]float64{
+			n/2 - 2*n2/3 + 37*n3/96 - n4/360,
+			n2/48 + n3/15 - 437*n4/1440,
+			17*n3/480 - 37*n4/840,
+			4397 * n4 / 161280,
+		},
+	}
+}
+
+// conformalLatitude returns the conformal latitude of phi (both in radians)
+func (k krugerSeries) conformalLatitude(phi float64) float64 {
+	e2 := k.e2
+	e4 := e2 * e2
+	e6 := e4 * e2
+	e8 := e6 * e2
+	s2 := math.Sin(phi) * math.Sin(phi)
+
+	A := e2
+	B := (5*e4 - e6) / 6
+	C := (104*e6 - 45*e8) / 120
+	D := 1237 * e8 / 1260
+
+	return phi - math.Sin(phi)*math.Cos(phi)*(A+s2*(B+s2*(C+s2*D)))
+}
+
+// geodeticLatitude returns the geodetic latitude of the conformal latitude chi (both in radians)
+func (k krugerSeries) geodeticLatitude(chi float64) float64 {
+	e2 := k.e2
+	e4 := e2 * e2
+	e6 := e4 * e2
+	e8 := e6 * e2
+	s2 := math.Sin(chi) * math.Sin(chi)
+
+	A := e2 + e4 + e6 + e8
+	B := -(7*e4 + 17*e6 + 30*e8) / 6
+	C := (224*e6 + 889*e8) / 120
+	D := -4279 * e8 / 1260
+
+	return chi + math.Sin(chi)*math.Cos(chi)*(A+s2*(B+s2*(C+s2*D)))
+}
+
+// meridianArc returns the scaled distance along the central meridian from the equator to latitude phi (radians)
+func (k krugerSeries) meridianArc(phi float64) float64 {
+	xi := k.conformalLatitude(phi)
+	m := xi
+
+	for j := range 4 {
+		m += k.beta[j] * math.Sin(2*float64(j+1)*xi)
+	}
+
+	return k.aHat * m
+}
+
+// Forward projects a longitude/latitude point to Transverse Mercator
+func (tm TransverseMercator) Forward(p Point) (Point, error) {
+	if err := checkGeographic("Transverse Mercator", p); err != nil {
+		return Point{}, err
+	}
+
+	k := tm.series()
+	dLon := toRadians(p.X - tm.CentralMeridian)
+	dLon -= 2 * math.Pi * math.Round(dLon/(2*math.Pi))
+	chi := k.conformalLatitude(toRadians(p.Y))
+
+	xi := math.Atan2(math.Tan(chi), math.Cos(dLon))
+	eta := math.Atanh(math.Cos(chi) * math.Sin(dLon))
+
+	if math.IsInf(eta, 0) || math.IsNaN(eta) {
+		return Point{}, ProjectionError{Projection: "Transverse Mercator", Point: p}
+	}
+
+	x := xi
+	y := eta
+
+	for j := range 4 {
+		t := 2 * float64(j+1)
+		x += k.beta[j] * math.Sin(t*xi) * math.Cosh(t*eta)
+		y += k.beta[j] * math.Cos(t*xi) * math.Sinh(t*eta)
+	}
+
+	m0 := k.meridianArc(toRadians(tm.LatitudeOfOrigin))
+
+	return Point{
+		X: tm.ScaleFactor*k.aHat*y + tm.FalseEasting,
+		Y: tm.ScaleFactor*(k.aHat*x-m0) + tm.FalseNorthing,
+	}, nil
+}
+
+// Inverse converts a Transverse Mercator point to longitude/latitude
+func (tm TransverseMercator) Inverse(p Point) (Point, error) {
+	if !p.isFinite() {
+		return Point{}, ProjectionError{Projection: "Transverse Mercator", Point: p}
+	}
+
+	k := tm.series()
+	m0 := k.meridianArc(toRadians(tm.LatitudeOfOrigin))
+
+	xi := ((p.Y-tm.FalseNorthing)/tm.ScaleFactor + m0) / k.aHat
+	eta := (p.X - tm.FalseEasting) / (tm.ScaleFactor * k.aHat)
+
+	xiP := xi
+	etaP := eta
+
+	for j := range 4 {
+		t := 2 * float64(j+1)
+		xiP -= k.delta[j] * math.Sin(t*xi) * math.Cosh(t*eta)
+		etaP -= k.delta[j] * math.Cos(t*xi) * math.Sinh(t*eta)
+	}
+
+	chi := math.Asin(math.Sin(xiP) / math.Cosh(etaP))
+	dLon := math.Atan2(math.Sinh(etaP), math.Cos(xiP))
+
+	return Point{
+		X: tm.CentralMeridian + toDegrees(dLon),
+		Y: toDegrees(k.geodeticLatitude(chi)),
+	}, nil
+}
+
+// isometricT returns the function t used by the Lambert Conformal Conic projection for latitude phi (radians)
+func isometricT(e Ellipsoid, phi float64) float64 {
+	ec := math.Sqrt(e.E2())
+	es := ec * math.Sin(phi)
+
+	return math.Tan(math.Pi/4-phi/2) / math.Pow((1-es)/(1+es), ec/2)
+}
+
+// parallelRadius returns the radius of the parallel at latitude phi (radians) divided by the semi-major axis
+func parallelRadius(e Ellipsoid, phi float64) float64 {
+	s := math.Sin(phi)
+	return math.Cos(phi) / math.Sqrt(1-e.E2()*s*s)
+}
+
+// LambertConformalConic is the ellipsoidal Lambert Conformal Conic projection. If StandardParallel1 and
+// StandardParallel2 are equal it is the one standard parallel variant, in which case ScaleFactor applies at
+// that parallel. A ScaleFactor of 0 is treated as 1. Angles are in degrees.
+type LambertConformalConic struct {
+	Ellipsoid         Ellipsoid
+	LatitudeOfOrigin  float64
+	CentralMeridian   float64
+	StandardParallel1 float64
+	StandardParallel2 float64
+	ScaleFactor       float64
+	FalseEasting      float64
+	FalseNorthing     float64
+}
+
+// constants returns the cone constant n, the scaled mapping radius factor aF and the radius at the origin
+func (l LambertConformalConic) constants() (n, aF, rho0 float64) {
+	phi1 := toRadians(l.StandardParallel1)
+	phi2 := toRadians(l.StandardParallel2)
+
+	m1 := parallelRadius(l.Ellipsoid, phi1)
+	t1 := isometricT(l.Ellipsoid, phi1)
+
+	if phi1 == phi2 {
+		n = math.Sin(phi1)
+	} else {
+		m2 := parallelRadius(l.Ellipsoid, phi2)
+		t2 := isometricT(l.Ellipsoid, phi2)
+		n = (math.Log(m1) - math.Log(m2)) / (math.Log(t1) - math.Log(t2))
+	}
+
+	k0 := l.ScaleFactor
+	if k0 == 0 {
+		k0 = 1
+	}
+
+	aF = l.Ellipsoid.A * k0 * m1 / (n * math.Pow(t1, n))
+	rho0 = aF * math.Pow(isometricT(l.Ellipsoid, toRadians(l.LatitudeOfOrigin)), n)
+
+	return n, aF, rho0
+}
+
+// Forward projects a longitude/latitude point to Lambert Conformal Conic
+func (l LambertConformalConic) Forward(p Point) (Point, error) {
+	if err := checkGeographic("Lambert Conformal Conic", p); err != nil {
+		return Point{}, err
+	}
+
+	n, aF, rho0 := l.constants()
+	phi := toRadians(p.Y)
+
+	var rho float64
+	if math.Abs(phi) < math.Pi/2 {
+		rho = aF * math.Pow(isometricT(l.Ellipsoid, phi), n)
+	} else if phi*n <= 0 {
+		return Point{}, ProjectionError{Projection: "Lambert Conformal Conic", Point: p}
+	}
+
+	dLon := toRadians(p.X - l.CentralMeridian)
+	dLon -= 2 * math.Pi * math.Round(dLon/(2*math.Pi))
+	theta := n * dLon
+
+	return Point{
+		X: l.FalseEasting + rho*math.Sin(theta),
+		Y: l.FalseNorthing + rho0 - rho*math.Cos(theta),
+	}, nil
+}
+
+// Inverse converts a Lambert Conformal Conic point to longitude/latitude
+func (l LambertConformalConic) Inverse(p Point) (Point, error) {
+	if !p.isFinite() {
+		return Point{}, ProjectionError{Projection: "Lambert Conformal Conic", Point: p}
+	}
+
+	n, aF, rho0 := l.constants()
+	dx := p.X - l.FalseEasting
+	dy := rho0 - (p.Y - l.FalseNorthing)
+
+	rho := math.Copysign(math.Hypot(dx, dy), n)
+	theta := math.Atan2(dx, dy)
+	if n < 0 {
+		theta = math.Atan2(-dx, -dy)
+	}
+
+	if rho == 0 {
+		return Point{X: l.CentralMeridian, Y: math.Copysign(90, n)}, nil
+	}
+
+	t := math.Pow(rho/aF, 1/n)
+	ec := math.Sqrt(l.Ellipsoid.E2())
+	phi := math.Pi/2 - 2*math.Atan(t)
+
+	for range 15 {
+		es := ec * math.Sin(phi)
+		next := math.Pi/2 - 2*math.Atan(t*math.Pow((1-es)/(1+es), ec/2))
+
+		if math.Abs(next-phi) < 1e-14 {
+			phi = next
+			break
+		}
+		phi = next
+	}
+
+	return Point{X: l.CentralMeridian + toDegrees(theta/n), Y: toDegrees(phi)}, nil
+}
+
+// AlbersEqualArea is the ellipsoidal Albers Equal Area Conic projection. Angles are in degrees.
+type AlbersEqualArea struct {
+	Ellipsoid         Ellipsoid
+	LatitudeOfOrigin  float64
+	CentralMeridian   float64
+	StandardParallel1 float64
+	StandardParallel2 float64
+	FalseEasting      float64
+	FalseNorthing     float64
+}
+
+func (al AlbersEqualArea) constants() (n, C, rho0 float64) {
+	e := al.Ellipsoid
+	phi1 := toRadians(al.StandardParallel1)
+	phi2 := toRadians(al.StandardParallel2)
+
+	m1 := parallelRadius(e, phi1)
+	q1 := al.q(phi1)
+
+	if phi1 == phi2 {
+		n = math.Sin(phi1)
+	} else {
+		m2 := parallelRadius(e, phi2)
+		q2 := al.q(phi2)
+		n = (m1*m1 - m2*m2) / (q2 - q1)
+	}
+
+	C = m1*m1 + n*q1
+	rho0 = e.A * math.Sqrt(C-n*al.q(toRadians(al.LatitudeOfOrigin))) / n
+
+	return n, C, rho0
+}
+
+func (al AlbersEqualArea) q(phi float64) float64 {
+	if al.Ellipsoid.F == 0 {
+		return 2 * math.Sin(phi)
+	}
+
+	return al.Ellipsoid.q(math.Sin(phi))
+}
+
+// Forward projects a longitude/latitude point to Albers Equal Area
+func (al AlbersEqualArea) Forward(p Point) (Point, error) {
+	if err := checkGeographic("Albers Equal Area", p); err != nil {
+		return Point{}, err
+	}
+
+	n, C, rho0 := al.constants()
+	rho := al.Ellipsoid.A * math.Sqrt(C-n*al.q(toRadians(p.Y))) / n
+
+	dLon := toRadians(p.X - al.CentralMeridian)
+	dLon -= 2 * math.Pi * math.Round(dLon/(2*math.Pi))
+	theta := n * dLon
+
+	return Point{
+		X: al.FalseEasting + rho*math.Sin(theta),
+		Y: al.FalseNorthing + rho0 - rho*math.Cos(theta),
+	}, nil
+}
+
+// Inverse converts an Albers Equal Area point to longitude/latitude
+func (al AlbersEqualArea) Inverse(p Point) (Point, error) {
+	if !p.isFinite() {
+		return Point{}, ProjectionError{Projection: "Albers Equal Area", Point: p}
+	}
+
+	e := al.Ellipsoid
+	n, C, rho0 := al.constants()
+	dx := p.X - al.FalseEasting
+	dy := rho0 - (p.Y - al.FalseNorthing)
+
+	rho := math.Hypot(dx, dy)
+	theta := math.Atan2(dx, dy)
+	if n < 0 {
+		theta = math.Atan2(-dx, -dy)
+	}
+
+	q := (C - rho*rho*n*n/(e.A*e.A)) / n
+
+	var phi float64
+	if e.F == 0 {
+		phi = math.Asin(math.Max(-1, math.Min(1, q/2)))
+	} else {
+		e2 := e.E2()
+		ec := math.Sqrt(e2)
+		phi = math.Asin(math.Max(-1, math.Min(1, q/2)))
+
+		for range 25 {
+			s := math.Sin(phi)
+			es := ec * s
+			den := 1 - e2*s*s
+			next := phi + den*den/(2*math.Cos(phi))*(q/(1-e2)-s/den+math.Log((1-es)/(1+es))/(2*ec))
+
+			if math.IsNaN(next) {
+				break
+			}
+
+			if math.Abs(next-phi) < 1e-14 {
+				phi = next
+				break
+			}
+			phi = next
+		}
+	}
+
+	return Point{X: al.CentralMeridian + toDegrees(theta/n), Y: toDegrees(phi)}, nil
+}
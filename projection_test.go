@@ -0,0 +1,148 @@
+package gegography
+
+import (
+	"math"
+	"testing"
+)
+
+func closeTo(a, b Point, tolerance float64) bool {
+	return math.Abs(a.X-b.X) <= tolerance && math.Abs(a.Y-b.Y) <= tolerance
+}
+
+func TestProjections(t *testing.T) {
+	clarke1866 := Ellipsoid{Name: "Clarke 1866", A: 6378206.4, F: 1 / 294.9786982}
+	usFoot := 1200.0 / 3937
+
+	epsg := func(code int) Projection {
+		d, err := LookupEPSG(code)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return d.Projection
+	}
+
+	tests := []struct {
+		name       string
+		projection Projection
+		geographic Point
+		projected  Point
+		tolerance  float64
+	}{
+		{"Web Mercator", WebMercator{Ellipsoid: WGS84Ellipsoid}, Point{X: 180, Y: 0}, Point{X: 20037508.342789244, Y: 0}, 0.01},
+		// the meridian arc length from the equator to 45°N on WGS84 is 4984944.378 m
+		{"UTM", UTM(WGS84Ellipsoid, 31, true), Point{X: 3, Y: 45}, Point{X: 500000, Y: 0.9996 * 4984944.378}, 0.01},
+		// worked example from Snyder, Map Projections: A Working Manual, given to 0.1 m
+		{"Transverse Mercator", TransverseMercator{
+			Ellipsoid:       clarke1866,
+			CentralMeridian: -75,
+			ScaleFactor:     0.9996,
+		}, Point{X: -73.5, Y: 40.5}, Point{X: 127106.5, Y: 4484124.4}, 0.05},
+		// SWEREF99 TM and SWEREF99 18 00 away from their central meridians, from Karney's sixth order series
+		{"SWEREF99 TM", epsg(3006), Point{X: 24, Y: 66}, Point{X: 907351.981, Y: 7349217.668}, 0.01},
+		{"SWEREF99 TM", epsg(3006), Point{X: 11.5, Y: 57.7}, Point{X: 291446.034, Y: 6400697.911}, 0.01},
+		{"SWEREF99 18 00", epsg(3011), Point{X: 19, Y: 59.5}, Point{X: 206638.443, Y: 6598794.695}, 0.01},
+		// EPSG Guidance Note 7-2 example for Lambert Conic Conformal (2SP), converted from US survey feet
+		{"Lambert Conformal Conic", LambertConformalConic{
+			Ellipsoid:         clarke1866,
+			LatitudeOfOrigin:  dms(27, 50, 0),
+			CentralMeridian:   -99,
+			StandardParallel1: dms(28, 23, 0),
+			StandardParallel2: dms(30, 17, 0),
+			FalseEasting:      2000000 * usFoot,
+		}, Point{X: -96, Y: 28.5}, Point{X: 2963503.91 * usFoot, Y: 254759.80 * usFoot}, 0.01},
+		// Snyder's worked example, given to 0.1 m
+		{"Lambert Conformal Conic", LambertConformalConic{
+			Ellipsoid:         clarke1866,
+			LatitudeOfOrigin:  23,
+			CentralMeridian:   -96,
+			StandardParallel1: 33,
+			StandardParallel2: 45,
+		}, Point{X: -75, Y: 35}, Point{X: 1894410.9, Y: 1564649.5}, 0.05},
+		{"Albers Equal Area", AlbersEqualArea{
+			Ellipsoid:         GRS80Ellipsoid,
+			LatitudeOfOrigin:  23,
+			CentralMeridian:   -96,
+			StandardParallel1: 29.5,
+			StandardParallel2: 45.5,
+			FalseEasting:      1000,
+		}, Point{X: -96, Y: 23}, Point{X: 1000, Y: 0}, 0.01},
+		// Snyder's worked example, given to 0.1 m
+		{"Albers Equal Area", AlbersEqualArea{
+			Ellipsoid:         clarke1866,
+			LatitudeOfOrigin:  23,
+			CentralMeridian:   -96,
+			StandardParallel1: 29.5,
+			StandardParallel2: 45.5,
+		}, Point{X: -75, Y: 35}, Point{X: 1885472.7, Y: 1535925.0}, 0.05},
+	}
+
+	for _, test := range tests {
+		p, err := test.projection.Forward(test.geographic)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !closeTo(p, test.projected, test.tolerance) {
+			t.Errorf("%s Forward(%v), want %v got %v", test.name, test.geographic, test.projected, p)
+		}
+
+		for _, g := range []Point{test.geographic, {X: test.geographic.X + 2.5, Y: test.geographic.Y + 7.25}} {
+			p, err = test.projection.Forward(g)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			back, err := test.projection.Inverse(p)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !closeTo(back, g, 1e-9) {
+				t.Errorf("%s Inverse(Forward(%v)), want round trip got %v", test.name, g, back)
+			}
+		}
+	}
+}
+
+func TestFeatureCollectionTransform(t *testing.T) {
+	fc, err := LoadGeoJSON([]byte(`{"type": "FeatureCollection", "features": [
+		{"type": "Feature", "geometry": {"type": "Point", "coordinates": [15, 0]}},
+		{"type": "Feature", "geometry": {"type": "LineString", "coordinates": [[24, 66], [11.5, 57.7]]}}
+	]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wgs84 := CRS{Type: "name", Properties: CRSProperties{Name: "urn:ogc:def:crs:OGC:1.3:CRS84"}}
+	sweref := CRS{Type: "name", Properties: CRSProperties{Name: "EPSG:3006"}}
+
+	if err = fc.Transform(wgs84, sweref); err != nil {
+		t.Fatal(err)
+	}
+
+	if p := fc.Features[0].Coordinates.(Point); !closeTo(p, Point{X: 500000, Y: 0}, 1e-6) {
+		t.Errorf("Transform(CRS84, EPSG:3006), want 500000 0 got %v", p)
+	}
+
+	want := MultiPoint{{X: 907351.981, Y: 7349217.668}, {X: 291446.034, Y: 6400697.911}}
+	line := fc.Features[1].Coordinates.(MultiPoint)
+	for x := range want {
+		if !closeTo(line[x], want[x], 0.01) {
+			t.Errorf("Transform(CRS84, EPSG:3006), want line point %d at %v got %v", x, want[x], line[x])
+		}
+	}
+
+	if fc.CoordinateReferenceSystem.Properties.Name != "EPSG:3006" {
+		t.Errorf("Transform(CRS84, EPSG:3006), want collection CRS set to EPSG:3006 got %v", fc.CoordinateReferenceSystem)
+	}
+
+	if err = fc.Transform(sweref, wgs84); err != nil {
+		t.Fatal(err)
+	}
+
+	line = fc.Features[1].Coordinates.(MultiPoint)
+	if !closeTo(line[0], Point{X: 24, Y: 66}, 1e-9) || !closeTo(line[1], Point{X: 11.5, Y: 57.7}, 1e-9) {
+		t.Errorf("Transform(EPSG:3006, CRS84), want the original line got %v", line)
+	}
+}
@@ -0,0 +1,458 @@
+package gegography
+
+import "strings"
+
+// IntersectionMatrix is a DE-9IM matrix describing how the interiors, boundaries and exteriors of two geometries
+// intersect. Rows are the interior, boundary and exterior of the first geometry and columns those of the second.
+// Entries hold the dimension of the intersection (0 for points, 1 for lines and 2 for areas), or -1 when it is
+// empty.
+type IntersectionMatrix [3][3]int
+
+// matrixIndex returns the row or column of a location in an IntersectionMatrix
+func matrixIndex(l location) int {
+	switch l {
+	case locInterior:
+		return 0
+	case locBoundary:
+		return 1
+	}
+
+	return 2
+}
+
+func (m *IntersectionMatrix) set(a, b location, dim int) {
+	i, j := matrixIndex(a), matrixIndex(b)
+	if m[i][j] < dim {
+		m[i][j] = dim
+	}
+}
+
+// String returns the matrix in the usual nine character form, such as "212101212", with F for empty intersections
+func (m IntersectionMatrix) String() string {
+	var sb strings.Builder
+
+	for i := range m {
+		for j := range m[i] {
+			if m[i][j] < 0 {
+				sb.WriteByte('F')
+			} else {
+				sb.WriteByte(byte('0' + m[i][j]))
+			}
+		}
+	}
+
+	return sb.String()
+}
+
+// Matches reports whether the matrix matches a nine character DE-9IM pattern, where T matches any non-empty
+// intersection, F an empty one, 0, 1 and 2 an intersection of that dimension and * anything
+func (m IntersectionMatrix) Matches(pattern string) bool {
+	if len(pattern) != 9 {
+		return false
+	}
+
+	for x := range 9 {
+		v := m[x/3][x%3]
+
+		switch pattern[x] {
+		case '*':
+		case 'T', 't':
+			if v < 0 {
+				return false
+			}
+		case 'F', 'f':
+			if v >= 0 {
+				return false
+			}
+		case '0', '1', '2':
+			if v != int(pattern[x]-'0') {
+				return false
+			}
+		default:
+			return false
+		}
+	}
+
+	return true
+}
+
+// relateGeometry is a geometry prepared for computing its relationship with another one. Polygon rings are oriented
+// with counter-clockwise shells and clockwise holes, so that the interior is always on their left.
+type relateGeometry struct {
+	dim     int
+	points  []Point
+	lines   []MultiPoint
+	polys   MultiPolygon
+	locator *pointLocator
+}
+
+// areaGeometry prepares polygons for relating and overlaying them
+func areaGeometry(polys MultiPolygon) relateGeometry {
+	polys = polys.rewind()
+
+	return relateGeometry{dim: 2, polys: polys, locator: newPointLocator(polys)}
+}
+
+func (f *Feature) relateGeometry() (relateGeometry, error) {
+	switch f.Type {
+	case "Point":
+		return relateGeometry{dim: 0, points: []Point{f.Coordinates.(Point)}}, nil
+	case "MultiPoint":
+		return relateGeometry{dim: 0, points: f.Coordinates.(MultiPoint)}, nil
+	case "LineString":
+		return relateGeometry{dim: 1, lines: []MultiPoint{f.Coordinates.(MultiPoint)}}, nil
+	case "MultiLineString":
+		return relateGeometry{dim: 1, lines: f.Coordinates.(Polygon)}, nil
+	case "Polygon":
+		return areaGeometry(MultiPolygon{f.Coordinates.(Polygon)}), nil
+	case "MultiPolygon":
+		return areaGeometry(f.Coordinates.(MultiPolygon)), nil
+	}
+
+	return relateGeometry{}, GeoTypeError{Type: f.Type}
+}
+
+// segments returns the segments of the lines and polygon rings of the geometry
+func (g relateGeometry) segments(geom int) []nodingSegment {
+	segs := make([]nodingSegment, 0)
+
+	add := func(pts []Point, area bool) {
+		for x := 1; x < len(pts); x++ {
+			if !pts[x-1].equals(pts[x]) {
+				segs = append(segs, nodingSegment{a: pts[x-1], b: pts[x], geom: geom, area: area})
+			}
+		}
+	}
+
+	for _, l := range g.lines {
+		add(l, false)
+	}
+
+	for _, p := range g.polys {
+		for _, r := range p {
+			add(closeRing(r), true)
+		}
+	}
+
+	return segs
+}
+
+// allPoints returns every coordinate of the geometry
+func (g relateGeometry) allPoints() []Point {
+	pts := append([]Point{}, g.points...)
+
+	for _, l := range g.lines {
+		pts = append(pts, l...)
+	}
+
+	for _, p := range g.polys {
+		for _, r := range p {
+			pts = append(pts, r...)
+		}
+	}
+
+	return pts
+}
+
+// relateNodes holds the nodes of the noded graph which belong to a geometry
+type relateNodes struct {
+	points   map[Point]bool
+	boundary map[Point]bool
+	onLines  map[Point]bool
+}
+
+func (g relateGeometry) nodes(geom int, edges []*nodedEdge, snap *snapper) relateNodes {
+	n := relateNodes{points: make(map[Point]bool), boundary: make(map[Point]bool), onLines: make(map[Point]bool)}
+
+	for _, p := range g.points {
+		n.points[snap.snap(p)] = true
+	}
+
+	// the boundary of lines are the endpoints which occur an odd number of times (the "mod 2" rule)
+	for _, l := range g.lines {
+		if len(l) < 2 {
+			continue
+		}
+
+		for _, p := range []Point{l[0], l[len(l)-1]} {
+			p = snap.snap(p)
+			n.boundary[p] = !n.boundary[p]
+		}
+	}
+
+	for _, e := range edges {
+		if owned, _, _ := e.owned(geom); owned {
+			n.onLines[e.a] = true
+			n.onLines[e.b] = true
+		}
+	}
+
+	return n
+}
+
+// locateNode returns the location of a node of the graph relative to the geometry
+func (g relateGeometry) locateNode(p Point, n relateNodes) location {
+	switch g.dim {
+	case 0:
+		if n.points[p] {
+			return locInterior
+		}
+	case 1:
+		if n.boundary[p] {
+			return locBoundary
+		}
+		if n.onLines[p] {
+			return locInterior
+		}
+	case 2:
+		if n.onLines[p] {
+			return locBoundary
+		}
+
+		return g.locator.locate(p)
+	}
+
+	return locExterior
+}
+
+// locateEdge returns the location of the interior of an edge relative to the geometry, along with the location of
+// the areas on its left and right
+func (g relateGeometry) locateEdge(e *nodedEdge, geom int) (edge, left, right location) {
+	owned, forward, backward := e.owned(geom)
+
+	switch {
+	case g.dim == 1 && owned:
+		return locInterior, locExterior, locExterior
+	case g.dim == 2 && owned:
+		left, right = locExterior, locExterior
+		if forward {
+			left = locInterior
+		}
+		if backward {
+			right = locInterior
+		}
+
+		return locBoundary, left, right
+	case g.dim == 2:
+		loc := g.locator.locate(e.midpoint())
+		if loc == locBoundary {
+			// the edge only nearly coincides with the boundary, and cannot tell which side it is on
+			return locBoundary, locExterior, locExterior
+		}
+
+		return loc, loc, loc
+	}
+
+	return locExterior, locExterior, locExterior
+}
+
+// transpose returns the matrix with the roles of the two geometries swapped
+func (m IntersectionMatrix) transpose() IntersectionMatrix {
+	var t IntersectionMatrix
+	for i := range m {
+		for j := range m[i] {
+			t[i][j] = m[j][i]
+		}
+	}
+
+	return t
+}
+
+// relatePointArea computes the DE-9IM matrix of a single point and an area directly from the location of the point,
+// which is much faster than noding the polygon rings
+func relatePointArea(p Point, area relateGeometry) IntersectionMatrix {
+	m := IntersectionMatrix{{-1, -1, -1}, {-1, -1, -1}, {2, 1, 2}}
+	m.set(locInterior, area.locator.locate(p), 0)
+
+	return m
+}
+
+// relate computes the DE-9IM matrix of two geometries by noding their linework together and classifying every
+// node, edge and face of the resulting graph
+func relate(a, b relateGeometry) IntersectionMatrix {
+	switch {
+	case len(a.points) == 1 && len(b.polys) > 0:
+		return relatePointArea(a.points[0], b)
+	case len(b.points) == 1 && len(a.polys) > 0:
+		return relatePointArea(b.points[0], a).transpose()
+	}
+
+	var m IntersectionMatrix
+	for i := range m {
+		for j := range m[i] {
+			m[i][j] = -1
+		}
+	}
+
+	m.set(locExterior, locExterior, 2)
+
+	segs := append(a.segments(0), b.segments(1)...)
+	points := append(append([]Point{}, a.points...), b.points...)
+	tol := nodingTolerance(append(a.allPoints(), b.allPoints()...))
+
+	edges, snap := nodeSegments(segs, points, tol)
+
+	nodesA := a.nodes(0, edges, snap)
+	nodesB := b.nodes(1, edges, snap)
+
+	visited := make(map[Point]bool)
+	relateNode := func(p Point) {
+		if !visited[p] {
+			visited[p] = true
+			m.set(a.locateNode(p, nodesA), b.locateNode(p, nodesB), 0)
+		}
+	}
+
+	for _, p := range points {
+		relateNode(snap.snap(p))
+	}
+
+	for _, e := range edges {
+		relateNode(e.a)
+		relateNode(e.b)
+
+		ea, la, ra := a.locateEdge(e, 0)
+		eb, lb, rb := b.locateEdge(e, 1)
+
+		m.set(ea, eb, 1)
+		m.set(la, lb, 2)
+		m.set(ra, rb, 2)
+	}
+
+	return m
+}
+
+// Relate returns the DE-9IM matrix describing the spatial relationship between two features
+func (f *Feature) Relate(o *Feature) (IntersectionMatrix, error) {
+	a, err := f.relateGeometry()
+	if err != nil {
+		return IntersectionMatrix{}, err
+	}
+
+	b, err := o.relateGeometry()
+	if err != nil {
+		return IntersectionMatrix{}, err
+	}
+
+	return relate(a, b), nil
+}
+
+// relatePredicate evaluates a predicate on the DE-9IM matrix and the dimensions of two features
+func (f *Feature) relatePredicate(o *Feature, fn func(m IntersectionMatrix, da, db int) bool) (bool, error) {
+	a, err := f.relateGeometry()
+	if err != nil {
+		return false, err
+	}
+
+	b, err := o.relateGeometry()
+	if err != nil {
+		return false, err
+	}
+
+	return fn(relate(a, b), a.dim, b.dim), nil
+}
+
+// The predicates below test a DE-9IM matrix and the dimensions of the two geometries it relates
+
+func equalsMatrix(m IntersectionMatrix, _, _ int) bool {
+	return m.Matches("T*F**FFF*")
+}
+
+func disjointMatrix(m IntersectionMatrix, _, _ int) bool {
+	return m.Matches("FF*FF****")
+}
+
+func intersectsMatrix(m IntersectionMatrix, da, db int) bool {
+	return !disjointMatrix(m, da, db)
+}
+
+func touchesMatrix(m IntersectionMatrix, _, _ int) bool {
+	return m.Matches("FT*******") || m.Matches("F**T*****") || m.Matches("F***T****")
+}
+
+func crossesMatrix(m IntersectionMatrix, da, db int) bool {
+	switch {
+	case da < db:
+		return m.Matches("T*T******")
+	case da > db:
+		return m.Matches("T*****T**")
+	case da == 1:
+		return m.Matches("0********")
+	}
+
+	return false
+}
+
+func withinMatrix(m IntersectionMatrix, _, _ int) bool {
+	return m.Matches("T*F**F***")
+}
+
+func containsMatrix(m IntersectionMatrix, _, _ int) bool {
+	return m.Matches("T*****FF*")
+}
+
+func overlapsMatrix(m IntersectionMatrix, da, db int) bool {
+	switch {
+	case da != db:
+		return false
+	case da == 1:
+		return m.Matches("1*T***T**")
+	}
+
+	return m.Matches("T*T***T**")
+}
+
+// Equals reports whether two features are spatially equal, covering the same points regardless of the order of
+// their coordinates
+func (f *Feature) Equals(o *Feature) (bool, error) {
+	return f.relatePredicate(o, equalsMatrix)
+}
+
+// Disjoint reports whether two features have no point in common
+func (f *Feature) Disjoint(o *Feature) (bool, error) {
+	return f.relatePredicate(o, disjointMatrix)
+}
+
+// Intersects reports whether two features have at least one point in common
+func (f *Feature) Intersects(o *Feature) (bool, error) {
+	return f.relatePredicate(o, intersectsMatrix)
+}
+
+// Touches reports whether two features have at least one point in common, but their interiors do not intersect
+func (f *Feature) Touches(o *Feature) (bool, error) {
+	return f.relatePredicate(o, touchesMatrix)
+}
+
+// Crosses reports whether two features have some but not all interior points in common, with the intersection
+// having a lower dimension than the larger of the two, such as a line passing through a polygon
+func (f *Feature) Crosses(o *Feature) (bool, error) {
+	return f.relatePredicate(o, crossesMatrix)
+}
+
+// Within reports whether the feature lies inside the other, with no point outside it and at least one interior
+// point in common
+func (f *Feature) Within(o *Feature) (bool, error) {
+	return f.relatePredicate(o, withinMatrix)
+}
+
+// Contains reports whether the other feature lies inside the feature, the reverse of Within. A point on the
+// boundary of a polygon is not contained by it.
+func (f *Feature) Contains(o *Feature) (bool, error) {
+	return f.relatePredicate(o, containsMatrix)
+}
+
+// Overlaps reports whether two features of the same dimension have some but not all points in common, with the
+// intersection having the same dimension as the features
+func (f *Feature) Overlaps(o *Feature) (bool, error) {
+	return f.relatePredicate(o, overlapsMatrix)
+}
+
+// ContainsPoint reports whether a point lies in the interior of the polygon
+func (p Polygon) ContainsPoint(pt Point) bool {
+	return locatePointInPolygon(pt, p) == locInterior
+}
+
+// ContainsPoint reports whether a point lies in the interior of the multipolygon
+func (mp MultiPolygon) ContainsPoint(pt Point) bool {
+	return locatePointInMultiPolygon(pt, mp) == locInterior
+}
@@ -0,0 +1,109 @@
+package gegography
+
+import "testing"
+
+func TestRelate(t *testing.T) {
+	square := "POLYGON ((0 0, 10 0, 10 10, 0 10, 0 0))"
+
+	tests := []struct {
+		a, b string
+		want string
+	}{
+		{square, "POINT (5 5)", "0F2FF1FF2"},
+		{square, "POINT (10 5)", "FF20F1FF2"},
+		{square, "POLYGON ((5 5, 15 5, 15 15, 5 15, 5 5))", "212101212"},
+		{square, "POLYGON ((10 0, 20 0, 20 10, 10 10, 10 0))", "FF2F11212"},
+		{square, "POLYGON ((10 10, 0 10, 0 0, 10 0, 10 10))", "2FFF1FFF2"},
+		{square, "POLYGON ((2 2, 8 2, 8 8, 2 8, 2 2))", "212FF1FF2"},
+		{"LINESTRING (-5 5, 15 5)", square, "101FF0212"},
+		{"LINESTRING (10 5, 20 5)", square, "FF1F00212"},
+		{"LINESTRING (0 0, 10 0)", square, "F1FF0F212"},
+		{"LINESTRING (0 0, 10 10)", "LINESTRING (0 10, 10 0)", "0F1FF0102"},
+		{"LINESTRING (0 0, 5 0, 10 0)", "LINESTRING (10 0, 0 0)", "1FFF0FFF2"},
+		{"POLYGON ((0 0, 10 0, 10 10, 0 10, 0 0), (2 2, 2 8, 8 8, 8 2, 2 2))", "POINT (5 5)", "FF2FF10F2"},
+		{"MULTIPOINT (0 0, 1 1)", "MULTIPOINT (1 1, 2 2)", "0F0FFF0F2"},
+	}
+
+	for _, test := range tests {
+		a := mustParseWKT(t, test.a)
+		b := mustParseWKT(t, test.b)
+
+		m, err := a.Relate(&b)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if m.String() != test.want {
+			t.Errorf("Relate(%s, %s), want %s got %s", test.a, test.b, test.want, m)
+		}
+	}
+}
+
+func TestPredicates(t *testing.T) {
+	type predicate func(f, o *Feature) (bool, error)
+
+	contains := (*Feature).Contains
+	within := (*Feature).Within
+	intersects := (*Feature).Intersects
+	disjoint := (*Feature).Disjoint
+	touches := (*Feature).Touches
+	crosses := (*Feature).Crosses
+	overlaps := (*Feature).Overlaps
+	equals := (*Feature).Equals
+
+	parcel := "POLYGON ((0 0, 10 0, 10 10, 0 10, 0 0))"
+
+	tests := []struct {
+		name string
+		fn   predicate
+		a, b string
+		want bool
+	}{
+		{"contains point", contains, parcel, "POINT (3 4)", true},
+		{"contains boundary point", contains, parcel, "POINT (0 4)", false},
+		{"contains line inside", contains, parcel, "LINESTRING (1 1, 9 9)", true},
+		{"contains line along boundary", contains, parcel, "LINESTRING (0 0, 10 0)", false},
+		{"within", within, "POINT (3 4)", parcel, true},
+		{"within polygon", within, "POLYGON ((0 0, 5 0, 5 5, 0 0))", parcel, true},
+		{"intersects touching", intersects, parcel, "POINT (10 10)", true},
+		{"intersects far", intersects, parcel, "POINT (20 20)", false},
+		{"disjoint", disjoint, parcel, "LINESTRING (11 0, 11 10)", true},
+		{"touches adjacent", touches, parcel, "POLYGON ((10 0, 20 0, 20 10, 10 10, 10 0))", true},
+		{"touches corner", touches, parcel, "POLYGON ((10 10, 20 10, 20 20, 10 20, 10 10))", true},
+		{"touches overlapping", touches, parcel, "POLYGON ((5 5, 15 5, 15 15, 5 15, 5 5))", false},
+		{"touches line ends", touches, "LINESTRING (0 0, 5 5)", "LINESTRING (5 5, 10 0)", true},
+		{"crosses lines", crosses, "LINESTRING (0 0, 10 10)", "LINESTRING (0 10, 10 0)", true},
+		{"crosses line polygon", crosses, "LINESTRING (-5 5, 5 5)", parcel, true},
+		{"crosses line inside", crosses, "LINESTRING (1 5, 5 5)", parcel, false},
+		{"crosses points polygon", crosses, "MULTIPOINT (5 5, 20 20)", parcel, true},
+		{"overlaps", overlaps, parcel, "POLYGON ((5 5, 15 5, 15 15, 5 15, 5 5))", true},
+		{"overlaps contained", overlaps, parcel, "POLYGON ((2 2, 8 2, 8 8, 2 8, 2 2))", false},
+		{"overlaps lines", overlaps, "LINESTRING (0 0, 10 0)", "LINESTRING (5 0, 15 0)", true},
+		{"equals", equals, parcel, "POLYGON ((10 10, 0 10, 0 0, 10 0, 10 10))", true},
+		{"equals extra vertex", equals, parcel, "POLYGON ((0 0, 5 0, 10 0, 10 10, 0 10, 0 0))", true},
+		{"equals different", equals, parcel, "POLYGON ((0 0, 10 0, 10 11, 0 10, 0 0))", false},
+		{"equals lines reversed", equals, "LINESTRING (0 0, 10 0)", "LINESTRING (10 0, 5 0, 0 0)", true},
+		{"multipolygon contains", contains, "MULTIPOLYGON (((0 0, 1 0, 1 1, 0 0)), ((5 5, 6 5, 6 6, 5 6, 5 5)))", "POINT (5.5 5.5)", true},
+	}
+
+	for _, test := range tests {
+		a := mustParseWKT(t, test.a)
+		b := mustParseWKT(t, test.b)
+
+		got, err := test.fn(&a, &b)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if got != test.want {
+			m, _ := a.Relate(&b)
+			t.Errorf("%s: want %v got %v (%s)", test.name, test.want, got, m)
+		}
+	}
+
+	gc := Feature{Type: "GeometryCollection"}
+	a := mustParseWKT(t, parcel)
+	if _, err := a.Contains(&gc); err == nil {
+		t.Error("Contains, expected an error for an unsupported type")
+	}
+}
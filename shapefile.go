@@ -5,6 +5,7 @@ import (
 	"encoding/binary"
 	"fmt"
 	"io"
+	"math"
 	"os"
 	"strconv"
 	"strings"
@@ -115,6 +116,53 @@ func parseShpPolyLine(in []byte) (Polygon, error) {
 	return p, nil
 }
 
+// parseShpPolyLineM parses a PolyLineM record, returning the M values of the points of each part along with the
+// lines. The measures are optional in the format, and are nil when missing. Values below shpMin mean no data and
+// are returned as NaN.
+func parseShpPolyLineM(in []byte) (Polygon, [][]float64, error) {
+	p, err := parseShpPolyLine(in)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var nparts, npoints int32
+	if err = parseValue(in[32:36], binary.LittleEndian, &nparts); err != nil {
+		return nil, nil, err
+	}
+	if err = parseValue(in[36:40], binary.LittleEndian, &npoints); err != nil {
+		return nil, nil, err
+	}
+
+	//the measures follow the points and the range of the measures
+	start := 40 + 4*int(nparts) + 16*int(npoints) + 16
+	if len(in) < start+8*int(npoints) {
+		return p, nil, nil
+	}
+
+	measures := make([][]float64, len(p))
+	x := 0
+	for y := range p {
+		measures[y] = make([]float64, len(p[y]))
+
+		for z := range p[y] {
+			var m float64
+			s := start + 8*x
+			if err = parseValue(in[s:s+8], binary.LittleEndian, &m); err != nil {
+				return nil, nil, err
+			}
+
+			if m < shpMin {
+				m = math.NaN()
+			}
+
+			measures[y][z] = m
+			x++
+		}
+	}
+
+	return p, measures, nil
+}
+
 func parseValue(in []byte, order binary.ByteOrder, out any) error {
 	buf := bytes.NewReader(in)
 	return binary.Read(buf, order, out)
@@ -205,8 +253,9 @@ func shpGeographyReader(r io.Reader, result chan shpGeography) {
 			c, err = parseShpPolyLine(content[4:])
 			features = append(features, Feature{Type: "MultiLineString", Coordinates: c})
 		case 23: //PolyLineM
-			c, err = parseShpPolyLine(content[4:])
-			features = append(features, Feature{Type: "MultiLineString", Coordinates: c})
+			var m [][]float64
+			c, m, err = parseShpPolyLineM(content[4:])
+			features = append(features, Feature{Type: "MultiLineString", Coordinates: c, Measures: m})
 		case 5: //Polygon
 			c, err = parseShpPolyLine(content[4:])
 			features = append(features, Feature{Type: "Polygon", Coordinates: c})
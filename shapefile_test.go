@@ -3,7 +3,10 @@ package gegography
 import (
 	"archive/zip"
 	"bytes"
+	"encoding/binary"
 	"io"
+	"math"
+	"slices"
 	"testing"
 )
 
@@ -77,3 +80,79 @@ func TestReadZippedShapefile(t *testing.T) {
 		t.Error("ReadShapefileData(shapefileReader, databaseReader), only feature should have property 'TestField' with value 'Hello!'")
 	}
 }
+
+// polyLineMShapefile returns a shapefile holding a single PolyLineM record with the given parts
+func polyLineMShapefile(parts [][]Point, measures [][]float64) []byte {
+	var content bytes.Buffer
+	le := func(v any) { binary.Write(&content, binary.LittleEndian, v) }
+
+	npoints := 0
+	for _, part := range parts {
+		npoints += len(part)
+	}
+
+	le(int32(23))
+	le([4]float64{})
+	le(int32(len(parts)))
+	le(int32(npoints))
+
+	start := 0
+	for _, part := range parts {
+		le(int32(start))
+		start += len(part)
+	}
+
+	for _, part := range parts {
+		for _, p := range part {
+			le([2]float64{p.X, p.Y})
+		}
+	}
+
+	le([2]float64{})
+	for _, part := range measures {
+		le(part)
+	}
+
+	var out bytes.Buffer
+	out.Write(make([]byte, 24))
+	binary.Write(&out, binary.BigEndian, int32((100+8+content.Len())/2))
+	out.Write(make([]byte, 72))
+	binary.Write(&out, binary.BigEndian, [2]int32{1, int32(content.Len() / 2)})
+	out.Write(content.Bytes())
+
+	return out.Bytes()
+}
+
+func TestReadPolyLineM(t *testing.T) {
+	parts := [][]Point{{{X: 0, Y: 0}, {X: 10, Y: 0}}, {{X: 0, Y: 5}, {X: 0, Y: 10}, {X: 5, Y: 10}}}
+	measures := [][]float64{{0, 10}, {20, -1e39, 30}}
+
+	result := make(chan shpGeography, 1)
+	shpGeographyReader(bytes.NewReader(polyLineMShapefile(parts, measures)), result)
+	g := <-result
+	if g.Error != nil {
+		t.Fatal(g.Error)
+	}
+
+	if len(g.Features) != 1 {
+		t.Fatalf("shpGeographyReader(polyLineM), want 1 feature got %d", len(g.Features))
+	}
+
+	f := g.Features[0]
+	if f.Type != "MultiLineString" || len(f.Measures) != 2 {
+		t.Fatalf("shpGeographyReader(polyLineM), want a MultiLineString with 2 measured lines got %s with %v", f.Type, f.Measures)
+	}
+
+	if !slices.Equal(f.Measures[0], measures[0]) || f.Measures[1][0] != 20 || !math.IsNaN(f.Measures[1][1]) || f.Measures[1][2] != 30 {
+		t.Errorf("shpGeographyReader(polyLineM), want measures [[0 10] [20 NaN 30]] got %v", f.Measures)
+	}
+
+	lines, err := f.MeasuredLines()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if p, ok := lines[0].InterpolatePoint(5); !ok || !p.equals(Point{X: 5, Y: 0}) {
+		t.Errorf("InterpolatePoint(5), want {5 0} got %v (%v)", p, ok)
+	}
+}
@@ -0,0 +1,736 @@
+package gegography
+
+import (
+	"container/heap"
+	"math"
+	"sort"
+)
+
+// SimplifyMethod is an algorithm used to remove vertices from lines and rings
+type SimplifyMethod int
+
+const (
+	// DouglasPeucker removes vertices closer than the tolerance to the simplified line, keeping the overall shape
+	// with very few vertices
+	DouglasPeucker SimplifyMethod = iota
+	// VisvalingamWhyatt repeatedly removes the vertex forming the smallest triangle with its neighbours, until every
+	// remaining triangle has an area of at least the tolerance squared. It tends to give smoother, more natural
+	// looking lines than Douglas-Peucker.
+	VisvalingamWhyatt
+)
+
+// SimplifyOptions configures Simplify. The zero value simplifies with Douglas-Peucker, without preserving
+// topology.
+type SimplifyOptions struct {
+	Method SimplifyMethod
+	// PreserveTopology keeps rings valid and the borders shared by neighbouring polygons, and by polygons of the
+	// same collection, identical. Lines and rings are simplified between the points where they meet, and parts
+	// are simplified less where simplifying them would make them cross each other, collapse or move across one
+	// another.
+	PreserveTopology bool
+}
+
+// douglasPeucker simplifies an open line, keeping its end points
+func douglasPeucker(pts []Point, tol float64) MultiPoint {
+	n := len(pts)
+	if n < 3 {
+		return append(MultiPoint{}, pts...)
+	}
+
+	keep := make([]bool, n)
+	keep[0], keep[n-1] = true, true
+
+	stack := [][2]int{{0, n - 1}}
+	for len(stack) > 0 {
+		s := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		best, bestDistance := -1, tol
+		for x := s[0] + 1; x < s[1]; x++ {
+			if d := segmentDistance(pts[x], pts[s[0]], pts[s[1]]); d > bestDistance {
+				best, bestDistance = x, d
+			}
+		}
+
+		if best >= 0 {
+			keep[best] = true
+			stack = append(stack, [2]int{s[0], best}, [2]int{best, s[1]})
+		}
+	}
+
+	out := make(MultiPoint, 0)
+	for x := range pts {
+		if keep[x] {
+			out = append(out, pts[x])
+		}
+	}
+
+	return out
+}
+
+type vwVertex struct {
+	area    float64
+	index   int
+	version int
+}
+
+type vwHeap []vwVertex
+
+func (h vwHeap) Len() int           { return len(h) }
+func (h vwHeap) Less(i, j int) bool { return h[i].area < h[j].area }
+func (h vwHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *vwHeap) Push(x any)        { *h = append(*h, x.(vwVertex)) }
+func (h *vwHeap) Pop() any {
+	old := *h
+	v := old[len(old)-1]
+	*h = old[:len(old)-1]
+
+	return v
+}
+
+// visvalingam simplifies an open line by removing vertices whose effective area is below minArea, keeping its end
+// points
+func visvalingam(pts []Point, minArea float64) MultiPoint {
+	n := len(pts)
+	if n < 3 {
+		return append(MultiPoint{}, pts...)
+	}
+
+	prev := make([]int, n)
+	next := make([]int, n)
+	version := make([]int, n)
+	removed := make([]bool, n)
+
+	for x := range n {
+		prev[x], next[x] = x-1, x+1
+	}
+
+	area := func(x int) float64 {
+		return math.Abs(cross(pts[prev[x]], pts[x], pts[next[x]])) / 2
+	}
+
+	h := make(vwHeap, 0, n)
+	for x := 1; x < n-1; x++ {
+		h = append(h, vwVertex{area: area(x), index: x})
+	}
+	heap.Init(&h)
+
+	for h.Len() > 0 {
+		v := heap.Pop(&h).(vwVertex)
+		if v.version != version[v.index] {
+			continue
+		}
+		if v.area >= minArea {
+			break
+		}
+
+		x := v.index
+		removed[x] = true
+		next[prev[x]], prev[next[x]] = next[x], prev[x]
+
+		// the effective area of a neighbour never drops below that of a vertex removed before it
+		for _, y := range []int{prev[x], next[x]} {
+			if y > 0 && y < n-1 {
+				version[y]++
+				heap.Push(&h, vwVertex{area: math.Max(area(y), v.area), index: y, version: version[y]})
+			}
+		}
+	}
+
+	out := make(MultiPoint, 0)
+	for x := range pts {
+		if !removed[x] {
+			out = append(out, pts[x])
+		}
+	}
+
+	return out
+}
+
+// simplifyLine simplifies an open line with the given method, keeping its end points
+func simplifyLine(pts []Point, tol float64, method SimplifyMethod) MultiPoint {
+	if method == VisvalingamWhyatt {
+		return visvalingam(pts, tol*tol)
+	}
+
+	return douglasPeucker(pts, tol)
+}
+
+// simplifyClosed simplifies a ring given without its closing point, keeping its first point. The ring is split at
+// the point farthest from the first, so that both halves are simplified as open lines. The result is closed.
+func simplifyClosed(r []Point, tol float64, method SimplifyMethod) MultiPoint {
+	if len(r) < 3 {
+		return closeRing(append(MultiPoint{}, r...))
+	}
+
+	far, farDistance := 0, 0.0
+	for x := range r {
+		if d := distance(r[0], r[x]); d > farDistance {
+			far, farDistance = x, d
+		}
+	}
+
+	a := simplifyLine(r[:far+1], tol, method)
+	b := simplifyLine(append(append(MultiPoint{}, r[far:]...), r[0]), tol, method)
+
+	return append(a, b[1:]...)
+}
+
+// simplifyRing simplifies a ring on its own, returning nil if it collapses
+func simplifyRing(r MultiPoint, tol float64, method SimplifyMethod) MultiPoint {
+	out := simplifyClosed(ringVertices(r), tol, method)
+
+	if v := ringVertices(out); len(v) < 3 || isDegenerateRing(v) {
+		return nil
+	}
+
+	return out
+}
+
+// topoArc is a part of one or more lines or rings running between two points where they meet. Arcs shared by
+// several rings are simplified once, so that the rings stay identical along it.
+type topoArc struct {
+	pts MultiPoint
+	out MultiPoint
+	tol float64
+}
+
+func (a *topoArc) simplify(method SimplifyMethod) {
+	switch {
+	case a.tol == 0:
+		a.out = a.pts
+	case a.pts[0].equals(a.pts[len(a.pts)-1]):
+		a.out = simplifyClosed(a.pts[:len(a.pts)-1], a.tol, method)
+	default:
+		a.out = simplifyLine(a.pts, a.tol, method)
+	}
+}
+
+type topoRef struct {
+	arc      *topoArc
+	reversed bool
+}
+
+// topoChain is a line, or a ring without its closing point, made up of arcs
+type topoChain struct {
+	pts    MultiPoint
+	closed bool
+	refs   []topoRef
+	bounds BBox
+}
+
+// points returns the simplified chain, closed if it is a ring
+func (c *topoChain) points() MultiPoint {
+	if len(c.refs) == 0 {
+		if c.closed {
+			return closeRing(append(MultiPoint{}, c.pts...))
+		}
+
+		return append(MultiPoint{}, c.pts...)
+	}
+
+	out := make(MultiPoint, 0)
+
+	for _, r := range c.refs {
+		pts := r.arc.out
+		if r.reversed {
+			pts = reverseRing(pts)
+		}
+		if len(out) > 0 {
+			pts = pts[1:]
+		}
+
+		out = append(out, pts...)
+	}
+
+	return out
+}
+
+// topoBuilder collects the lines and rings of geometries to be simplified together while preserving topology
+type topoBuilder struct {
+	chains []*topoChain
+}
+
+func (b *topoBuilder) add(pts []Point, closed bool) *topoChain {
+	c := &topoChain{pts: dedupePoints(pts), closed: closed}
+	if closed {
+		c.pts = ringVertices(c.pts)
+	}
+
+	c.bounds = c.pts.Bounds()
+	b.chains = append(b.chains, c)
+
+	return c
+}
+
+// addLines adds lines, returning a function giving the simplified lines once simplify has been called
+func (b *topoBuilder) addLines(lines []MultiPoint) func() []MultiPoint {
+	chains := make([]*topoChain, 0, len(lines))
+	for _, l := range lines {
+		chains = append(chains, b.add(l, false))
+	}
+
+	return func() []MultiPoint {
+		out := make([]MultiPoint, 0, len(chains))
+		for _, c := range chains {
+			out = append(out, c.points())
+		}
+
+		return out
+	}
+}
+
+// addPolygons adds the rings of polygons, returning a function giving the simplified polygons once simplify has
+// been called
+func (b *topoBuilder) addPolygons(mp MultiPolygon) func() MultiPolygon {
+	chains := make([][]*topoChain, 0, len(mp))
+	for _, p := range mp {
+		rings := make([]*topoChain, 0, len(p))
+		for _, r := range p {
+			rings = append(rings, b.add(r, true))
+		}
+		chains = append(chains, rings)
+	}
+
+	return func() MultiPolygon {
+		out := make(MultiPolygon, 0, len(chains))
+		for _, rings := range chains {
+			p := make(Polygon, 0, len(rings))
+			for _, c := range rings {
+				if r := c.points(); len(ringVertices(r)) >= 3 {
+					p = append(p, r)
+				} else if len(p) == 0 {
+					break
+				}
+			}
+
+			if len(p) > 0 {
+				out = append(out, p)
+			}
+		}
+
+		return out
+	}
+}
+
+// splitArcs splits every chain into arcs at the points where it meets other chains: the end points of lines and
+// the points with more than two distinct neighbours. Identical arcs, in either direction, are shared.
+func (b *topoBuilder) splitArcs() []*topoArc {
+	seen := make(map[[2]Point]bool)
+	degree := make(map[Point]int)
+	nodes := make(map[Point]bool)
+
+	for _, c := range b.chains {
+		n := len(c.pts)
+		if !c.closed && n > 0 {
+			nodes[c.pts[0]] = true
+			nodes[c.pts[n-1]] = true
+			n--
+		}
+
+		for x := range n {
+			p, q := c.pts[x], c.pts[(x+1)%len(c.pts)]
+			if lessPoint(q, p) {
+				p, q = q, p
+			}
+
+			if !seen[[2]Point{p, q}] {
+				seen[[2]Point{p, q}] = true
+				degree[p]++
+				degree[q]++
+			}
+		}
+	}
+
+	for p, d := range degree {
+		if d != 2 {
+			nodes[p] = true
+		}
+	}
+
+	arcs := make([]*topoArc, 0)
+	index := make(map[[3]Point]*topoArc)
+
+	addArc := func(c *topoChain, pts MultiPoint) {
+		n := len(pts)
+		forward := [3]Point{pts[0], pts[1], pts[n-1]}
+		backward := [3]Point{pts[n-1], pts[n-2], pts[0]}
+
+		reversed := false
+		for x := range 3 {
+			if !forward[x].equals(backward[x]) {
+				reversed = lessPoint(backward[x], forward[x])
+				break
+			}
+		}
+
+		key := forward
+		if reversed {
+			key = backward
+			pts = reverseRing(pts)
+		}
+
+		a, ok := index[key]
+		if !ok {
+			a = &topoArc{pts: pts}
+			index[key] = a
+			arcs = append(arcs, a)
+		}
+
+		c.refs = append(c.refs, topoRef{arc: a, reversed: reversed})
+	}
+
+	for _, c := range b.chains {
+		pts := c.pts
+		if len(pts) < 2 {
+			continue
+		}
+
+		if c.closed {
+			// start the ring at a node, or at its smallest point so that identical rings start at the same point
+			start := -1
+			for x := range pts {
+				if nodes[pts[x]] {
+					start = x
+					break
+				}
+			}
+
+			if start < 0 {
+				start = 0
+				for x := range pts {
+					if lessPoint(pts[x], pts[start]) {
+						start = x
+					}
+				}
+				nodes[pts[start]] = true
+			}
+
+			pts = append(append(append(MultiPoint{}, pts[start:]...), pts[:start]...), pts[start])
+		}
+
+		from := 0
+		for x := 1; x < len(pts); x++ {
+			if nodes[pts[x]] || x == len(pts)-1 {
+				addArc(c, pts[from:x+1])
+				from = x
+			}
+		}
+	}
+
+	return arcs
+}
+
+// arcSegment is a segment of an arc, for finding arcs which cross each other
+type arcSegment struct {
+	a, b Point
+	arc  *topoArc
+}
+
+// crossingArcs calls fn for every pair of segments which intersect other than by sharing an end point
+func crossingArcs(segs []arcSegment, fn func(a, b *topoArc)) {
+	sort.Slice(segs, func(i, j int) bool { return math.Min(segs[i].a.X, segs[i].b.X) < math.Min(segs[j].a.X, segs[j].b.X) })
+
+	for i := range segs {
+		s := segs[i]
+		maxX := math.Max(s.a.X, s.b.X)
+
+		for j := i + 1; j < len(segs) && math.Min(segs[j].a.X, segs[j].b.X) <= maxX; j++ {
+			o := segs[j]
+
+			in := intersectSegments(s.a, s.b, o.a, o.b)
+			switch in.Kind {
+			case noIntersection:
+				continue
+			case pointIntersection:
+				p := in.Points[0]
+				if (p.equals(s.a) || p.equals(s.b)) && (p.equals(o.a) || p.equals(o.b)) {
+					continue
+				}
+			}
+
+			fn(s.arc, o.arc)
+		}
+	}
+}
+
+func arcSegments(arcs []*topoArc, simplified bool) []arcSegment {
+	segs := make([]arcSegment, 0)
+
+	for _, a := range arcs {
+		pts := a.pts
+		if simplified {
+			pts = a.out
+		}
+
+		for x := 1; x < len(pts); x++ {
+			segs = append(segs, arcSegment{a: pts[x-1], b: pts[x], arc: a})
+		}
+	}
+
+	return segs
+}
+
+// simplify simplifies every chain, reducing the tolerance of arcs until no arcs cross each other where they did
+// not before, no ring collapses and no ring moves across a point of another ring
+func (b *topoBuilder) simplify(tol float64, method SimplifyMethod) {
+	arcs := b.splitArcs()
+	for _, a := range arcs {
+		a.tol = tol
+		a.simplify(method)
+	}
+
+	// arcs which already crossed each other before simplification, found the first time they are needed
+	var crossedBefore map[[2]*topoArc]bool
+
+	crossesOriginally := func(a, o *topoArc) bool {
+		if crossedBefore == nil {
+			crossedBefore = make(map[[2]*topoArc]bool)
+
+			crossingArcs(arcSegments(arcs, false), func(a, o *topoArc) {
+				crossedBefore[[2]*topoArc{a, o}] = true
+				crossedBefore[[2]*topoArc{o, a}] = true
+			})
+		}
+
+		return crossedBefore[[2]*topoArc{a, o}]
+	}
+
+	rings := make([]*topoChain, 0)
+	for _, c := range b.chains {
+		if c.closed && len(c.refs) > 0 {
+			rings = append(rings, c)
+		}
+	}
+
+	for {
+		conflicts := make(map[*topoArc]bool)
+		markChain := func(c *topoChain) {
+			for _, r := range c.refs {
+				conflicts[r.arc] = true
+			}
+		}
+
+		crossingArcs(arcSegments(arcs, true), func(a, o *topoArc) {
+			if crossesOriginally(a, o) {
+				return
+			}
+
+			conflicts[a] = true
+			conflicts[o] = true
+		})
+
+		simplified := make(map[*topoChain]MultiPoint, len(rings))
+		for _, c := range rings {
+			simplified[c] = c.points()
+
+			if v := ringVertices(simplified[c]); len(v) < 3 || isDegenerateRing(v) {
+				markChain(c)
+			}
+		}
+
+		for _, c := range rings {
+			for _, o := range b.chains {
+				if o == c || len(o.refs) == 0 || !c.bounds.Intersects(o.bounds) {
+					continue
+				}
+
+				// the first point of a chain is always kept, so it should stay on the same side of the ring
+				p := o.refs[0].arc.out[0]
+				if o.refs[0].reversed {
+					p = o.refs[0].arc.out[len(o.refs[0].arc.out)-1]
+				}
+
+				before := locatePointInRing(p, closeRing(c.pts))
+				if before != locBoundary && before != locatePointInRing(p, simplified[c]) {
+					markChain(c)
+				}
+			}
+		}
+
+		changed := false
+		for a := range conflicts {
+			if a.tol == 0 {
+				continue
+			}
+
+			if a.tol /= 2; a.tol < tol/1024 {
+				a.tol = 0
+			}
+
+			a.simplify(method)
+			changed = true
+		}
+
+		if !changed {
+			return
+		}
+	}
+}
+
+// Simplify returns a simplified copy of a line. The end points are always kept.
+func (mp MultiPoint) Simplify(tolerance float64, opts SimplifyOptions) MultiPoint {
+	if !opts.PreserveTopology {
+		return simplifyLine(dedupePoints(mp), tolerance, opts.Method)
+	}
+
+	b := &topoBuilder{}
+	lines := b.addLines([]MultiPoint{mp})
+	b.simplify(tolerance, opts.Method)
+
+	return lines()[0]
+}
+
+// Simplify returns a simplified copy of the line. The end points are always kept.
+func (ls LineString) Simplify(tolerance float64, opts SimplifyOptions) LineString {
+	return LineString(MultiPoint(ls).Simplify(tolerance, opts))
+}
+
+// Simplify returns a simplified copy of the polygon. Without opts.PreserveTopology, holes collapsing into lines
+// are removed, a collapsing shell gives an empty polygon and the result may be invalid.
+func (p Polygon) Simplify(tolerance float64, opts SimplifyOptions) Polygon {
+	if opts.PreserveTopology {
+		if out := (MultiPolygon{p}).Simplify(tolerance, opts); len(out) > 0 {
+			return out[0]
+		}
+
+		return Polygon{}
+	}
+
+	out := make(Polygon, 0, len(p))
+
+	for x := range p {
+		r := simplifyRing(p[x], tolerance, opts.Method)
+
+		switch {
+		case r == nil && x == 0:
+			return Polygon{}
+		case r != nil:
+			out = append(out, r)
+		}
+	}
+
+	return out
+}
+
+// Simplify returns a simplified copy of the polygons, leaving out polygons which collapse
+func (mp MultiPolygon) Simplify(tolerance float64, opts SimplifyOptions) MultiPolygon {
+	if opts.PreserveTopology {
+		b := &topoBuilder{}
+		polygons := b.addPolygons(mp)
+		b.simplify(tolerance, opts.Method)
+
+		return polygons()
+	}
+
+	out := make(MultiPolygon, 0, len(mp))
+
+	for x := range mp {
+		if p := mp[x].Simplify(tolerance, opts); len(p) > 0 {
+			out = append(out, p)
+		}
+	}
+
+	return out
+}
+
+// addTopology adds the lines or rings of the feature to a topoBuilder, returning a function which replaces its
+// coordinates with the simplified ones
+func (f *Feature) addTopology(b *topoBuilder) (func(), error) {
+	switch f.Type {
+	case "Point", "MultiPoint":
+		return func() {}, nil
+	case "LineString":
+		lines := b.addLines([]MultiPoint{f.Coordinates.(MultiPoint)})
+		return func() { f.Coordinates = lines()[0] }, nil
+	case "MultiLineString":
+		lines := b.addLines(f.Coordinates.(Polygon))
+		return func() { f.Coordinates = Polygon(lines()) }, nil
+	case "Polygon":
+		polygons := b.addPolygons(MultiPolygon{f.Coordinates.(Polygon)})
+		return func() {
+			if mp := polygons(); len(mp) > 0 {
+				f.Coordinates = mp[0]
+			} else {
+				f.Coordinates = Polygon{}
+			}
+		}, nil
+	case "MultiPolygon":
+		polygons := b.addPolygons(f.Coordinates.(MultiPolygon))
+		return func() { f.Coordinates = polygons() }, nil
+	}
+
+	return nil, GeoTypeError{Type: f.Type}
+}
+
+// Simplify simplifies the lines and polygons of the feature in place. Points are left unchanged.
+func (f *Feature) Simplify(tolerance float64, opts SimplifyOptions) error {
+	if opts.PreserveTopology {
+		b := &topoBuilder{}
+
+		apply, err := f.addTopology(b)
+		if err != nil {
+			return err
+		}
+
+		b.simplify(tolerance, opts.Method)
+		apply()
+
+		return nil
+	}
+
+	switch f.Type {
+	case "Point", "MultiPoint":
+	case "LineString":
+		f.Coordinates = f.Coordinates.(MultiPoint).Simplify(tolerance, opts)
+	case "MultiLineString":
+		lines := make(Polygon, 0)
+		for _, l := range f.Coordinates.(Polygon) {
+			lines = append(lines, l.Simplify(tolerance, opts))
+		}
+		f.Coordinates = lines
+	case "Polygon":
+		f.Coordinates = f.Coordinates.(Polygon).Simplify(tolerance, opts)
+	case "MultiPolygon":
+		f.Coordinates = f.Coordinates.(MultiPolygon).Simplify(tolerance, opts)
+	default:
+		return GeoTypeError{Type: f.Type}
+	}
+
+	return nil
+}
+
+// Simplify simplifies the lines and polygons of every feature in place. With opts.PreserveTopology, the features
+// are simplified together, so that borders shared by neighbouring features stay identical and features do not
+// come to overlap.
+func (fc *FeatureCollection) Simplify(tolerance float64, opts SimplifyOptions) error {
+	if !opts.PreserveTopology {
+		for x := range fc.Features {
+			if err := fc.Features[x].Simplify(tolerance, opts); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	b := &topoBuilder{}
+	apply := make([]func(), 0, len(fc.Features))
+
+	for x := range fc.Features {
+		fn, err := fc.Features[x].addTopology(b)
+		if err != nil {
+			return err
+		}
+
+		apply = append(apply, fn)
+	}
+
+	b.simplify(tolerance, opts.Method)
+
+	for _, fn := range apply {
+		fn()
+	}
+
+	return nil
+}
@@ -0,0 +1,135 @@
+package gegography
+
+import (
+	"math"
+	"slices"
+	"testing"
+)
+
+func TestSimplifyLine(t *testing.T) {
+	line := MultiPoint{{0, 0}, {1, 0.1}, {2, -0.1}, {3, 5}, {4, 6}, {5, 7}, {6, 8.1}, {7, 9}, {8, 9}, {9, 9}}
+
+	tests := []struct {
+		name      string
+		tolerance float64
+		method    SimplifyMethod
+		want      MultiPoint
+	}{
+		{"douglas-peucker", 1, DouglasPeucker, MultiPoint{{0, 0}, {2, -0.1}, {3, 5}, {7, 9}, {9, 9}}},
+		{"douglas-peucker collinear", 0, DouglasPeucker, MultiPoint{{0, 0}, {1, 0.1}, {2, -0.1}, {3, 5}, {5, 7}, {6, 8.1}, {7, 9}, {9, 9}}},
+		{"douglas-peucker large tolerance", 100, DouglasPeucker, MultiPoint{{0, 0}, {9, 9}}},
+		{"visvalingam", 1, VisvalingamWhyatt, MultiPoint{{0, 0}, {2, -0.1}, {3, 5}, {7, 9}, {9, 9}}},
+		{"visvalingam large tolerance", 100, VisvalingamWhyatt, MultiPoint{{0, 0}, {9, 9}}},
+	}
+
+	for _, tt := range tests {
+		got := line.Simplify(tt.tolerance, SimplifyOptions{Method: tt.method})
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("Simplify(%v) %s, want %v got %v", tt.tolerance, tt.name, tt.want, got)
+		}
+	}
+}
+
+func TestSimplifyPolygon(t *testing.T) {
+	noisy := "POLYGON ((0 0, 5 0.1, 10 0, 10.1 5, 10 10, 5 9.9, 0 10, -0.1 5, 0 0), (4 4, 4.1 4.1, 4 4.2, 4 4))"
+
+	p := mustParseWKT(t, noisy).Coordinates.(Polygon)
+
+	got := p.Simplify(0.5, SimplifyOptions{})
+	if len(got) != 1 || len(got[0]) != 5 || got.Area() != 100 {
+		t.Errorf("Simplify(0.5), want the square without the hole got %v", got)
+	}
+
+	got = p.Simplify(1, SimplifyOptions{Method: VisvalingamWhyatt})
+	if len(got) != 1 || len(got[0]) != 5 || got.Area() != 100 {
+		t.Errorf("Simplify(1) visvalingam, want the square without the hole got %v", got)
+	}
+
+	// with preserved topology the collapsing hole is kept, and the shell keeps its first point
+	got = p.Simplify(0.5, SimplifyOptions{PreserveTopology: true})
+	if len(got) != 2 || len(got[0]) != 6 || len(got[1]) != 4 {
+		t.Errorf("Simplify(0.5) preserving topology, want a 6 point shell and the hole got %v", got)
+	}
+
+	triangle := Polygon{{{0, 0}, {1, 0}, {0, 0.1}, {0, 0}}}
+	if got := triangle.Simplify(1, SimplifyOptions{}); len(got) != 0 {
+		t.Errorf("Simplify(1) of a thin triangle, want an empty polygon got %v", got)
+	}
+	if got := triangle.Simplify(1, SimplifyOptions{PreserveTopology: true}); len(got) != 1 || got.Area() != triangle.Area() {
+		t.Errorf("Simplify(1) of a thin triangle preserving topology, want the triangle got %v", got)
+	}
+}
+
+func TestSimplifyPreserveTopology(t *testing.T) {
+	// simplifying the bump away would leave the stream outside the park
+	fc := &FeatureCollection{Features: []Feature{
+		mustParseWKT(t, "POLYGON ((0 0, 10 0, 10 10, 7 10.9, 5 11, 3 10.9, 0 10, 0 0))"),
+		mustParseWKT(t, "LINESTRING (4 10.3, 6 10.3)"),
+	}}
+
+	simplified := &FeatureCollection{Features: slices.Clone(fc.Features)}
+	if err := simplified.Simplify(1.5, SimplifyOptions{}); err != nil {
+		t.Fatal(err)
+	}
+
+	if within, _ := simplified.Features[1].Within(&simplified.Features[0]); within {
+		t.Error("Simplify(1.5), want the line to leave the polygon without preserving topology")
+	}
+
+	simplified = &FeatureCollection{Features: slices.Clone(fc.Features)}
+	if err := simplified.Simplify(1.5, SimplifyOptions{PreserveTopology: true}); err != nil {
+		t.Fatal(err)
+	}
+
+	if within, _ := simplified.Features[1].Within(&simplified.Features[0]); !within {
+		t.Errorf("Simplify(1.5) preserving topology, want the line within the polygon got %v", simplified.Features[0].Coordinates)
+	}
+
+	// simplifying the peak away would make the lines cross
+	lines := Polygon{{{0, 0}, {5, 2}, {10, 0}}, {{5, 1}, {5, -1}}}
+	f := Feature{Type: "MultiLineString", Coordinates: lines}
+
+	if err := f.Simplify(5, SimplifyOptions{PreserveTopology: true}); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := f.Coordinates.(Polygon); len(got[0]) != 3 {
+		t.Errorf("Simplify(5) preserving topology, want the peak kept got %v", got)
+	}
+}
+
+func TestSimplifySharedBorders(t *testing.T) {
+	border := MultiPoint{}
+	for x := range 101 {
+		border = append(border, Point{X: 5 + math.Sin(float64(x)/3)*0.8 + math.Sin(float64(x)*1.7)*0.05, Y: float64(x) / 10})
+	}
+
+	west := append(MultiPoint{{0, 10}, {0, 0}}, border...)
+	east := append(MultiPoint{{10, 0}, {10, 10}}, reverseRing(border)...)
+
+	fc := &FeatureCollection{Features: []Feature{
+		{Type: "Polygon", Properties: map[string]any{}, Coordinates: Polygon{closeRing(west)}},
+		{Type: "MultiPolygon", Properties: map[string]any{}, Coordinates: MultiPolygon{{closeRing(east)}}},
+	}}
+
+	for _, method := range []SimplifyMethod{DouglasPeucker, VisvalingamWhyatt} {
+		out := &FeatureCollection{Features: slices.Clone(fc.Features)}
+		if err := out.Simplify(0.3, SimplifyOptions{Method: method, PreserveTopology: true}); err != nil {
+			t.Fatal(err)
+		}
+
+		a := out.Features[0].Coordinates.(Polygon)
+		b := out.Features[1].Coordinates.(MultiPolygon)
+
+		if len(a[0]) >= len(west) || len(b[0][0]) >= len(east) {
+			t.Errorf("%d: Simplify(0.3), want fewer than %d and %d points got %d and %d", method, len(west), len(east), len(a[0]), len(b[0][0]))
+		}
+
+		if i := (MultiPolygon{a}).Intersection(b).Area(); i > 1e-9 {
+			t.Errorf("%d: Simplify(0.3), want polygons without overlap got an overlap of %v", method, i)
+		}
+		if u := (MultiPolygon{a}).Union(b).Area(); math.Abs(u-100) > 1e-9 {
+			t.Errorf("%d: Simplify(0.3), want polygons covering 100 got %v", method, u)
+		}
+	}
+}
@@ -0,0 +1,493 @@
+package gegography
+
+import (
+	"container/heap"
+	"math"
+	"sort"
+	"sync"
+)
+
+// rtreeMaxEntries is the largest number of entries or children of an R-tree node, and rtreeMinEntries the
+// smallest number left in a node when it is split
+const (
+	rtreeMaxEntries = 16
+	rtreeMinEntries = 6
+)
+
+// rtreeEntry is an indexed item
+type rtreeEntry struct {
+	id     int
+	bounds BBox
+}
+
+// rtreeNode is a node of an R-tree. Leaves hold entries and other nodes hold children.
+type rtreeNode struct {
+	bounds   BBox
+	leaf     bool
+	entries  []rtreeEntry
+	children []*rtreeNode
+}
+
+func (n *rtreeNode) size() int {
+	if n.leaf {
+		return len(n.entries)
+	}
+
+	return len(n.children)
+}
+
+func (n *rtreeNode) itemBounds(x int) BBox {
+	if n.leaf {
+		return n.entries[x].bounds
+	}
+
+	return n.children[x].bounds
+}
+
+func (n *rtreeNode) updateBounds() {
+	n.bounds = EmptyBBox()
+
+	for x := range n.size() {
+		n.bounds = n.bounds.Extend(n.itemBounds(x))
+	}
+}
+
+// SpatialIndex is an R-tree indexing the bounding boxes of items identified by integers, usually the positions of
+// features in a FeatureCollection. It answers bounding box and nearest neighbour queries without examining every
+// item. A SpatialIndex is safe for concurrent use; searches may run in parallel, while Insert and Delete wait for
+// them to finish.
+type SpatialIndex struct {
+	mu     sync.RWMutex
+	root   *rtreeNode
+	bounds map[int]BBox
+}
+
+// NewSpatialIndex returns an index of the features of a collection, identified by their position in
+// fc.Features. The tree is bulk loaded with the Sort-Tile-Recursive algorithm, which packs the nodes fully and
+// gives little overlap between them. Features with empty geometries are not indexed. A nil collection gives an
+// empty index.
+func NewSpatialIndex(fc *FeatureCollection) *SpatialIndex {
+	si := &SpatialIndex{bounds: make(map[int]BBox)}
+	entries := make([]rtreeEntry, 0)
+
+	if fc != nil {
+		for x := range fc.Features {
+			if b := fc.Features[x].Bounds(); !b.IsEmpty() {
+				entries = append(entries, rtreeEntry{id: x, bounds: b})
+				si.bounds[x] = b
+			}
+		}
+	}
+
+	si.root = bulkLoad(entries)
+
+	return si
+}
+
+// strTiles sorts n items into tiles for Sort-Tile-Recursive packing: sorted by the X of their centres into
+// vertical slices, and each slice by the Y of their centres, so that consecutive runs of rtreeMaxEntries items are
+// close together
+func strTiles(n int, bounds func(int) BBox) []int {
+	order := make([]int, n)
+	for x := range order {
+		order[x] = x
+	}
+
+	sort.Slice(order, func(i, j int) bool { return bounds(order[i]).Center().X < bounds(order[j]).Center().X })
+
+	nodes := (n + rtreeMaxEntries - 1) / rtreeMaxEntries
+	slice := rtreeMaxEntries * int(math.Ceil(math.Sqrt(float64(nodes))))
+
+	for start := 0; start < n; start += slice {
+		s := order[start:min(start+slice, n)]
+		sort.Slice(s, func(i, j int) bool { return bounds(s[i]).Center().Y < bounds(s[j]).Center().Y })
+	}
+
+	return order
+}
+
+func bulkLoad(entries []rtreeEntry) *rtreeNode {
+	order := strTiles(len(entries), func(x int) BBox { return entries[x].bounds })
+
+	level := make([]*rtreeNode, 0)
+	for start := 0; start < len(order); start += rtreeMaxEntries {
+		n := &rtreeNode{leaf: true}
+		for _, x := range order[start:min(start+rtreeMaxEntries, len(order))] {
+			n.entries = append(n.entries, entries[x])
+		}
+
+		n.updateBounds()
+		level = append(level, n)
+	}
+
+	for len(level) > 1 {
+		order := strTiles(len(level), func(x int) BBox { return level[x].bounds })
+
+		next := make([]*rtreeNode, 0)
+		for start := 0; start < len(order); start += rtreeMaxEntries {
+			n := &rtreeNode{}
+			for _, x := range order[start:min(start+rtreeMaxEntries, len(order))] {
+				n.children = append(n.children, level[x])
+			}
+
+			n.updateBounds()
+			next = append(next, n)
+		}
+
+		level = next
+	}
+
+	if len(level) == 0 {
+		return &rtreeNode{leaf: true, bounds: EmptyBBox()}
+	}
+
+	return level[0]
+}
+
+// Len returns the number of indexed items
+func (si *SpatialIndex) Len() int {
+	si.mu.RLock()
+	defer si.mu.RUnlock()
+
+	return len(si.bounds)
+}
+
+// Search returns the ids of the items whose bounding box intersects b, in no particular order
+func (si *SpatialIndex) Search(b BBox) []int {
+	si.mu.RLock()
+	defer si.mu.RUnlock()
+
+	out := make([]int, 0)
+	if !si.root.bounds.Intersects(b) {
+		return out
+	}
+
+	stack := []*rtreeNode{si.root}
+	for len(stack) > 0 {
+		n := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		if n.leaf {
+			for _, e := range n.entries {
+				if e.bounds.Intersects(b) {
+					out = append(out, e.id)
+				}
+			}
+
+			continue
+		}
+
+		for _, c := range n.children {
+			if c.bounds.Intersects(b) {
+				stack = append(stack, c)
+			}
+		}
+	}
+
+	return out
+}
+
+// nearestItem is a node or entry waiting to be visited by a nearest neighbour search
+type nearestItem struct {
+	distance float64
+	node     *rtreeNode
+	id       int
+	exact    bool
+}
+
+type nearestQueue []nearestItem
+
+func (q nearestQueue) Len() int           { return len(q) }
+func (q nearestQueue) Less(i, j int) bool { return q[i].distance < q[j].distance }
+func (q nearestQueue) Swap(i, j int)      { q[i], q[j] = q[j], q[i] }
+func (q *nearestQueue) Push(x any)        { *q = append(*q, x.(nearestItem)) }
+func (q *nearestQueue) Pop() any {
+	old := *q
+	item := old[len(old)-1]
+	*q = old[:len(old)-1]
+
+	return item
+}
+
+// nearest returns the ids of the k items nearest to p, closest first, visiting nodes in order of the distance to
+// their bounding box. The distance to an item is the distance to its bounding box, refined by exact if it is not
+// nil; exact must never return less than the distance to the bounding box.
+func (si *SpatialIndex) nearest(p Point, k int, exact func(id int) float64) []int {
+	return si.nearestBy(k, func(b BBox) float64 { return b.Distance(p) }, exact)
+}
+
+// nearestBy is nearest with the distance to a bounding box given by boxDistance, which must never exceed the
+// distance to anything inside the box
+func (si *SpatialIndex) nearestBy(k int, boxDistance func(BBox) float64, exact func(id int) float64) []int {
+	si.mu.RLock()
+	defer si.mu.RUnlock()
+
+	out := make([]int, 0, max(k, 0))
+	q := nearestQueue{{distance: boxDistance(si.root.bounds), node: si.root}}
+
+	for q.Len() > 0 && len(out) < k {
+		item := heap.Pop(&q).(nearestItem)
+
+		switch {
+		case item.node == nil && (item.exact || exact == nil):
+			out = append(out, item.id)
+		case item.node == nil:
+			heap.Push(&q, nearestItem{distance: exact(item.id), id: item.id, exact: true})
+		case item.node.leaf:
+			for _, e := range item.node.entries {
+				heap.Push(&q, nearestItem{distance: boxDistance(e.bounds), id: e.id})
+			}
+		default:
+			for _, c := range item.node.children {
+				heap.Push(&q, nearestItem{distance: boxDistance(c.bounds), node: c})
+			}
+		}
+	}
+
+	return out
+}
+
+// Nearest returns the ids of the k items whose bounding boxes are nearest to p, closest first. For points, this is
+// the distance to the point itself.
+func (si *SpatialIndex) Nearest(p Point, k int) []int {
+	return si.nearest(p, k, nil)
+}
+
+// Insert adds an item to the index, replacing any item with the same id. Items with an empty bounding box are not
+// indexed.
+func (si *SpatialIndex) Insert(id int, b BBox) {
+	si.mu.Lock()
+	defer si.mu.Unlock()
+
+	if _, ok := si.bounds[id]; ok {
+		si.delete(id)
+	}
+
+	if b.IsEmpty() {
+		return
+	}
+
+	si.bounds[id] = b
+	si.insertEntry(rtreeEntry{id: id, bounds: b})
+}
+
+// insertEntry adds an entry to the tree, growing it by a level when the root is split
+func (si *SpatialIndex) insertEntry(e rtreeEntry) {
+	if sibling := si.insert(si.root, e); sibling != nil {
+		si.root = &rtreeNode{children: []*rtreeNode{si.root, sibling}}
+		si.root.updateBounds()
+	}
+}
+
+// insert adds an entry below node n, returning the new sibling of n if it had to be split
+func (si *SpatialIndex) insert(n *rtreeNode, e rtreeEntry) *rtreeNode {
+	n.bounds = n.bounds.Extend(e.bounds)
+
+	if n.leaf {
+		n.entries = append(n.entries, e)
+	} else {
+		// descend into the child needing the least enlargement, then the smallest one
+		best, bestGrowth, bestArea := 0, math.Inf(1), math.Inf(1)
+		for x, c := range n.children {
+			area := bboxArea(c.bounds)
+			growth := bboxArea(c.bounds.Extend(e.bounds)) - area
+
+			if growth < bestGrowth || growth == bestGrowth && area < bestArea {
+				best, bestGrowth, bestArea = x, growth, area
+			}
+		}
+
+		if sibling := si.insert(n.children[best], e); sibling != nil {
+			n.children = append(n.children, sibling)
+		}
+	}
+
+	if n.size() <= rtreeMaxEntries {
+		return nil
+	}
+
+	return n.split()
+}
+
+func bboxArea(b BBox) float64 {
+	return b.Width() * b.Height()
+}
+
+func bboxMargin(b BBox) float64 {
+	return b.Width() + b.Height()
+}
+
+// split moves part of the items of an overfull node to a new sibling, as in the R*-tree: the items are sorted
+// along the axis giving the smallest total perimeter of the two halves, and divided where the halves overlap the
+// least
+func (n *rtreeNode) split() *rtreeNode {
+	size := n.size()
+
+	byAxis := [2][]int{}
+	for axis := range 2 {
+		order := make([]int, size)
+		for x := range order {
+			order[x] = x
+		}
+
+		sort.Slice(order, func(i, j int) bool {
+			a, b := n.itemBounds(order[i]), n.itemBounds(order[j])
+			if axis == 0 {
+				return a.MinX < b.MinX || a.MinX == b.MinX && a.MaxX < b.MaxX
+			}
+
+			return a.MinY < b.MinY || a.MinY == b.MinY && a.MaxY < b.MaxY
+		})
+
+		byAxis[axis] = order
+	}
+
+	halves := func(order []int, k int) (BBox, BBox) {
+		left, right := EmptyBBox(), EmptyBBox()
+		for x, i := range order {
+			if x < k {
+				left = left.Extend(n.itemBounds(i))
+			} else {
+				right = right.Extend(n.itemBounds(i))
+			}
+		}
+
+		return left, right
+	}
+
+	axis, bestMargin := 0, math.Inf(1)
+	for a := range 2 {
+		margin := 0.0
+		for k := rtreeMinEntries; k <= size-rtreeMinEntries; k++ {
+			l, r := halves(byAxis[a], k)
+			margin += bboxMargin(l) + bboxMargin(r)
+		}
+
+		if margin < bestMargin {
+			axis, bestMargin = a, margin
+		}
+	}
+
+	split, bestOverlap, bestArea := rtreeMinEntries, math.Inf(1), math.Inf(1)
+	for k := rtreeMinEntries; k <= size-rtreeMinEntries; k++ {
+		l, r := halves(byAxis[axis], k)
+		overlap, area := bboxArea(l.Intersection(r)), bboxArea(l)+bboxArea(r)
+
+		if overlap < bestOverlap || overlap == bestOverlap && area < bestArea {
+			split, bestOverlap, bestArea = k, overlap, area
+		}
+	}
+
+	sibling := &rtreeNode{leaf: n.leaf}
+	order := byAxis[axis]
+
+	if n.leaf {
+		entries := n.entries
+		n.entries = make([]rtreeEntry, 0, rtreeMaxEntries+1)
+		for x, i := range order {
+			if x < split {
+				n.entries = append(n.entries, entries[i])
+			} else {
+				sibling.entries = append(sibling.entries, entries[i])
+			}
+		}
+	} else {
+		children := n.children
+		n.children = make([]*rtreeNode, 0, rtreeMaxEntries+1)
+		for x, i := range order {
+			if x < split {
+				n.children = append(n.children, children[i])
+			} else {
+				sibling.children = append(sibling.children, children[i])
+			}
+		}
+	}
+
+	n.updateBounds()
+	sibling.updateBounds()
+
+	return sibling
+}
+
+// Delete removes an item from the index, reporting whether it was indexed
+func (si *SpatialIndex) Delete(id int) bool {
+	si.mu.Lock()
+	defer si.mu.Unlock()
+
+	return si.delete(id)
+}
+
+func (si *SpatialIndex) delete(id int) bool {
+	b, ok := si.bounds[id]
+	if !ok {
+		return false
+	}
+
+	delete(si.bounds, id)
+
+	orphans := make([]rtreeEntry, 0)
+	si.remove(si.root, id, b, &orphans)
+
+	// shorten the tree while the root has a single child
+	for !si.root.leaf && len(si.root.children) == 1 {
+		si.root = si.root.children[0]
+	}
+	if !si.root.leaf && len(si.root.children) == 0 {
+		si.root = &rtreeNode{leaf: true, bounds: EmptyBBox()}
+	}
+
+	for _, e := range orphans {
+		si.insertEntry(e)
+	}
+
+	return true
+}
+
+// remove removes the entry with the given id and bounds below node n and reports whether it was found. As in
+// Guttman's condense step, nodes left with fewer than rtreeMinEntries items are removed from the tree, and the
+// entries below them are added to orphans to be inserted again, so that deleting keeps the nodes well filled.
+func (si *SpatialIndex) remove(n *rtreeNode, id int, b BBox, orphans *[]rtreeEntry) bool {
+	if !n.bounds.ContainsBBox(b) {
+		return false
+	}
+
+	if n.leaf {
+		for x := range n.entries {
+			if n.entries[x].id == id {
+				n.entries = append(n.entries[:x], n.entries[x+1:]...)
+				n.updateBounds()
+
+				return true
+			}
+		}
+
+		return false
+	}
+
+	for x, c := range n.children {
+		if si.remove(c, id, b, orphans) {
+			if c.size() < rtreeMinEntries {
+				n.children = append(n.children[:x], n.children[x+1:]...)
+				*orphans = c.appendEntries(*orphans)
+			}
+			n.updateBounds()
+
+			return true
+		}
+	}
+
+	return false
+}
+
+// appendEntries appends the entries of every leaf below n
+func (n *rtreeNode) appendEntries(entries []rtreeEntry) []rtreeEntry {
+	if n.leaf {
+		return append(entries, n.entries...)
+	}
+
+	for _, c := range n.children {
+		entries = c.appendEntries(entries)
+	}
+
+	return entries
+}
@@ -0,0 +1,295 @@
+package gegography
+
+import (
+	"math/rand"
+	"slices"
+	"sync"
+	"testing"
+)
+
+func randomBoxes(r *rand.Rand, n int) []BBox {
+	boxes := make([]BBox, n)
+	for x := range boxes {
+		p := Point{X: r.Float64() * 1000, Y: r.Float64() * 1000}
+		boxes[x] = BBox{MinX: p.X, MinY: p.Y, MaxX: p.X + r.Float64()*20, MaxY: p.Y + r.Float64()*20}
+	}
+
+	return boxes
+}
+
+func boxCollection(boxes []BBox) *FeatureCollection {
+	fc := &FeatureCollection{}
+	for _, b := range boxes {
+		fc.Features = append(fc.Features, Feature{Type: "Polygon", Properties: map[string]any{}, Coordinates: b.ToPolygon()})
+	}
+
+	return fc
+}
+
+// linearSearch returns the positions of the boxes intersecting q, sorted
+func linearSearch(boxes map[int]BBox, q BBox) []int {
+	out := make([]int, 0)
+	for id, b := range boxes {
+		if b.Intersects(q) {
+			out = append(out, id)
+		}
+	}
+
+	slices.Sort(out)
+
+	return out
+}
+
+func checkSearch(t *testing.T, si *SpatialIndex, boxes map[int]BBox, r *rand.Rand) {
+	t.Helper()
+
+	for range 50 {
+		q := randomBoxes(r, 1)[0].Buffer(r.Float64() * 100)
+
+		got := si.Search(q)
+		slices.Sort(got)
+
+		if want := linearSearch(boxes, q); !slices.Equal(got, want) {
+			t.Fatalf("Search(%v), want %v got %v", q, want, got)
+		}
+	}
+}
+
+func TestSpatialIndexSearch(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+
+	for _, n := range []int{0, 1, 15, 16, 17, 300, 5000} {
+		boxes := randomBoxes(r, n)
+		si := NewSpatialIndex(boxCollection(boxes))
+
+		if si.Len() != n {
+			t.Errorf("Len(), want %d got %d", n, si.Len())
+		}
+
+		byID := make(map[int]BBox)
+		for x, b := range boxes {
+			byID[x] = b
+		}
+
+		checkSearch(t, si, byID, r)
+	}
+
+	if NewSpatialIndex(nil).Len() != 0 {
+		t.Error("NewSpatialIndex(nil), want an empty index")
+	}
+}
+
+func TestSpatialIndexInsertDelete(t *testing.T) {
+	r := rand.New(rand.NewSource(2))
+
+	boxes := randomBoxes(r, 500)
+	si := NewSpatialIndex(boxCollection(boxes[:100]))
+
+	byID := make(map[int]BBox)
+	for x, b := range boxes[:100] {
+		byID[x] = b
+	}
+
+	for x := 100; x < len(boxes); x++ {
+		si.Insert(x, boxes[x])
+		byID[x] = boxes[x]
+	}
+
+	checkSearch(t, si, byID, r)
+
+	for x := 0; x < len(boxes); x += 2 {
+		if !si.Delete(x) {
+			t.Fatalf("Delete(%d), want true got false", x)
+		}
+		delete(byID, x)
+	}
+
+	if si.Delete(0) {
+		t.Error("Delete(0) of a deleted item, want false got true")
+	}
+
+	checkSearch(t, si, byID, r)
+
+	// replacing an item moves it
+	si.Insert(1, BBox{MinX: -50, MinY: -50, MaxX: -40, MaxY: -40})
+	byID[1] = BBox{MinX: -50, MinY: -50, MaxX: -40, MaxY: -40}
+
+	if si.Len() != len(byID) {
+		t.Errorf("Len(), want %d got %d", len(byID), si.Len())
+	}
+
+	checkSearch(t, si, byID, r)
+
+	for id := range byID {
+		si.Delete(id)
+	}
+
+	if si.Len() != 0 || len(si.Search(BBox{MinX: -1000, MinY: -1000, MaxX: 2000, MaxY: 2000})) != 0 {
+		t.Error("Delete() of every item, want an empty index")
+	}
+
+	si.Insert(7, boxes[7])
+	if got := si.Search(boxes[7]); !slices.Equal(got, []int{7}) {
+		t.Errorf("Search(%v) after reinserting, want [7] got %v", boxes[7], got)
+	}
+}
+
+// checkNodes reports nodes below the root holding fewer than rtreeMinEntries or more than rtreeMaxEntries items,
+// bounds not matching their items and leaves at different depths. It returns the depth of the leaves below n.
+func checkNodes(t *testing.T, n *rtreeNode, root bool) int {
+	t.Helper()
+
+	if size := n.size(); !root && (size < rtreeMinEntries || size > rtreeMaxEntries) {
+		t.Errorf("node size, want between %d and %d items got %d", rtreeMinEntries, rtreeMaxEntries, size)
+	}
+
+	want := EmptyBBox()
+	for x := range n.size() {
+		want = want.Extend(n.itemBounds(x))
+	}
+
+	if n.bounds != want {
+		t.Errorf("node bounds, want %v got %v", want, n.bounds)
+	}
+
+	if n.leaf {
+		return 0
+	}
+
+	depth := -1
+	for _, c := range n.children {
+		d := checkNodes(t, c, false)
+		if depth >= 0 && d != depth {
+			t.Errorf("leaf depth, want all at depth %d got %d", depth, d)
+		}
+		depth = d
+	}
+
+	return depth + 1
+}
+
+func TestSpatialIndexDeleteCondenses(t *testing.T) {
+	r := rand.New(rand.NewSource(4))
+
+	boxes := randomBoxes(r, 5000)
+	si := NewSpatialIndex(boxCollection(boxes))
+
+	byID := make(map[int]BBox)
+	for x, b := range boxes {
+		byID[x] = b
+	}
+
+	leaves := func() int {
+		n := 0
+		var walk func(*rtreeNode)
+		walk = func(node *rtreeNode) {
+			if node.leaf {
+				n++
+			}
+			for _, c := range node.children {
+				walk(c)
+			}
+		}
+		walk(si.root)
+
+		return n
+	}
+
+	// delete nine in ten items in random order
+	for _, x := range r.Perm(len(boxes))[:4500] {
+		if !si.Delete(x) {
+			t.Fatalf("Delete(%d), want true got false", x)
+		}
+		delete(byID, x)
+	}
+
+	checkNodes(t, si.root, true)
+	checkSearch(t, si, byID, r)
+
+	if n := leaves(); n > len(byID)/rtreeMinEntries {
+		t.Errorf("leaves after deleting, want at most %d for %d items got %d", len(byID)/rtreeMinEntries, len(byID), n)
+	}
+
+	for x := range 500 {
+		si.Insert(10000+x, boxes[x])
+		byID[10000+x] = boxes[x]
+	}
+
+	checkNodes(t, si.root, true)
+	checkSearch(t, si, byID, r)
+}
+
+func TestSpatialIndexNearest(t *testing.T) {
+	r := rand.New(rand.NewSource(3))
+
+	fc := &FeatureCollection{}
+	pts := make([]Point, 2000)
+	for x := range pts {
+		pts[x] = Point{X: r.Float64() * 100, Y: r.Float64() * 100}
+		fc.Features = append(fc.Features, Feature{Type: "Point", Properties: map[string]any{}, Coordinates: pts[x]})
+	}
+
+	si := NewSpatialIndex(fc)
+
+	for range 50 {
+		p := Point{X: r.Float64()*120 - 10, Y: r.Float64()*120 - 10}
+
+		want := make([]int, len(pts))
+		for x := range want {
+			want[x] = x
+		}
+		slices.SortFunc(want, func(a, b int) int {
+			da, db := distance(p, pts[a]), distance(p, pts[b])
+			switch {
+			case da < db:
+				return -1
+			case da > db:
+				return 1
+			}
+
+			return 0
+		})
+
+		got := si.Nearest(p, 10)
+		if len(got) != 10 {
+			t.Fatalf("Nearest(%v, 10), want 10 items got %d", p, len(got))
+		}
+
+		for x := range got {
+			if distance(p, pts[got[x]]) != distance(p, pts[want[x]]) {
+				t.Fatalf("Nearest(%v, 10)[%d], want %d got %d", p, x, want[x], got[x])
+			}
+		}
+	}
+
+	if got := si.Nearest(Point{}, 0); len(got) != 0 {
+		t.Errorf("Nearest({0 0}, 0), want no items got %v", got)
+	}
+	if got := si.Nearest(Point{}, 5000); len(got) != len(pts) {
+		t.Errorf("Nearest({0 0}, 5000), want %d items got %d", len(pts), len(got))
+	}
+}
+
+func TestSpatialIndexConcurrentSearch(t *testing.T) {
+	r := rand.New(rand.NewSource(4))
+	boxes := randomBoxes(r, 1000)
+	si := NewSpatialIndex(boxCollection(boxes))
+
+	var wg sync.WaitGroup
+	for x := range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			si.Search(boxes[x])
+			si.Nearest(boxes[x].Center(), 5)
+		}()
+	}
+
+	si.Insert(len(boxes), boxes[0])
+	wg.Wait()
+
+	if si.Len() != len(boxes)+1 {
+		t.Errorf("Len(), want %d got %d", len(boxes)+1, si.Len())
+	}
+}
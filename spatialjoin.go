@@ -0,0 +1,313 @@
+package gegography
+
+import (
+	"math"
+	"slices"
+)
+
+// JoinPredicate selects the right features which a spatial join matches with each left feature
+type JoinPredicate int
+
+const (
+	// JoinIntersects matches right features having at least one point in common with the left feature
+	JoinIntersects JoinPredicate = iota
+	// JoinContains matches right features lying inside the left feature
+	JoinContains
+	// JoinWithin matches right features inside which the left feature lies
+	JoinWithin
+	// JoinTouches matches right features touching the left feature without their interiors intersecting
+	JoinTouches
+	// JoinCrosses matches right features crossing the left feature
+	JoinCrosses
+	// JoinOverlaps matches right features overlapping the left feature
+	JoinOverlaps
+	// JoinNearest matches the right feature nearest to the left feature, or all of them if several are equally near
+	JoinNearest
+)
+
+// matrixPredicate returns the DE-9IM test of the predicate, with the left feature as the first geometry
+func (jp JoinPredicate) matrixPredicate() (func(m IntersectionMatrix, da, db int) bool, error) {
+	switch jp {
+	case JoinIntersects:
+		return intersectsMatrix, nil
+	case JoinContains:
+		return containsMatrix, nil
+	case JoinWithin:
+		return withinMatrix, nil
+	case JoinTouches:
+		return touchesMatrix, nil
+	case JoinCrosses:
+		return crossesMatrix, nil
+	case JoinOverlaps:
+		return overlapsMatrix, nil
+	}
+
+	return nil, GeoFormatError{Msg: "unsupported spatial join predicate"}
+}
+
+// JoinMode selects how the matches of a left feature are written to the output of a spatial join
+type JoinMode int
+
+const (
+	// JoinOneToOne gives one output feature for every left feature, carrying the properties of its first match,
+	// which is the nearest one for JoinNearest and otherwise the first in the right collection
+	JoinOneToOne JoinMode = iota
+	// JoinOneToMany gives one output feature for every pair of a left feature and a match
+	JoinOneToMany
+)
+
+// JoinOptions controls spatial joins
+type JoinOptions struct {
+	Mode JoinMode
+	// Prefix is prepended to the names of the properties copied from right features. Without a prefix, properties
+	// of the left feature are kept when a right property has the same name.
+	Prefix string
+	// DropUnmatched leaves left features without any match out of the output, instead of keeping them with only
+	// their own properties
+	DropUnmatched bool
+	// Aggregates are computed over all matches of each left feature and added to its output feature in
+	// JoinOneToOne mode. They are not computed in JoinOneToMany mode.
+	Aggregates []PropertyAggregate
+	// MaxDistance limits JoinNearest to right features within that distance, when it is positive
+	MaxDistance float64
+	// DistanceProperty, when set, names a property holding the distance to the match for JoinNearest
+	DistanceProperty string
+}
+
+// joinMatch is a right feature matched with a left feature
+type joinMatch struct {
+	index    int
+	distance float64
+}
+
+// joinProperties returns the properties of a left feature combined with those of a match
+func joinProperties(left *Feature, right *Feature, opts JoinOptions) map[string]any {
+	props := make(map[string]any, len(left.Properties))
+	for k, v := range left.Properties {
+		props[k] = v
+	}
+
+	if right == nil {
+		return props
+	}
+
+	for k, v := range right.Properties {
+		name := opts.Prefix + k
+		if _, ok := props[name]; ok && opts.Prefix == "" {
+			continue
+		}
+
+		props[name] = v
+	}
+
+	return props
+}
+
+// SpatialJoin returns the features of the collection with properties attached from the features of right which
+// match them by the predicate. The output keeps the name and coordinate reference system of the collection, and
+// its features share their geometries with those of the collection.
+func (fc *FeatureCollection) SpatialJoin(right *FeatureCollection, predicate JoinPredicate, opts JoinOptions) (FeatureCollection, error) {
+	out := fc.emptyCopy()
+
+	if err := validateAggregates(opts.Aggregates); err != nil {
+		return FeatureCollection{}, err
+	}
+
+	var matchFn func(m IntersectionMatrix, da, db int) bool
+	if predicate != JoinNearest {
+		fn, err := predicate.matrixPredicate()
+		if err != nil {
+			return FeatureCollection{}, err
+		}
+
+		matchFn = fn
+	}
+
+	si := NewSpatialIndex(right)
+
+	// right geometries are prepared once, when first needed
+	prepared := make(map[int]*facetIndex)
+	rightGeometry := func(x int) (*facetIndex, error) {
+		if fi, ok := prepared[x]; ok {
+			return fi, nil
+		}
+
+		g, err := right.Features[x].relateGeometry()
+		if err != nil {
+			return nil, err
+		}
+
+		fi := &facetIndex{geom: g}
+		if predicate == JoinNearest {
+			fi = newFacetIndex(g)
+		}
+
+		prepared[x] = fi
+
+		return fi, nil
+	}
+
+	for x := range fc.Features {
+		left := &fc.Features[x]
+
+		var matches []joinMatch
+		var err error
+
+		if predicate == JoinNearest {
+			matches, err = joinNearest(left, si, rightGeometry, opts.MaxDistance)
+		} else {
+			matches, err = joinRelated(left, si, rightGeometry, matchFn)
+		}
+
+		if err != nil {
+			return FeatureCollection{}, err
+		}
+
+		if len(matches) == 0 {
+			if !opts.DropUnmatched {
+				f := Feature{Type: left.Type, Properties: joinProperties(left, nil, opts), Coordinates: left.Coordinates}
+				if opts.Mode == JoinOneToOne {
+					for _, a := range opts.Aggregates {
+						f.Properties[a.name(opts.Prefix)] = a.aggregate(nil)
+					}
+				}
+
+				out.AddFeature(f)
+			}
+
+			continue
+		}
+
+		if opts.Mode == JoinOneToMany {
+			for _, m := range matches {
+				out.AddFeature(joinedFeature(left, &right.Features[m.index], m, predicate, opts))
+			}
+
+			continue
+		}
+
+		f := joinedFeature(left, &right.Features[matches[0].index], matches[0], predicate, opts)
+
+		if len(opts.Aggregates) > 0 {
+			matched := make([]*Feature, len(matches))
+			for i, m := range matches {
+				matched[i] = &right.Features[m.index]
+			}
+
+			for _, a := range opts.Aggregates {
+				f.Properties[a.name(opts.Prefix)] = a.aggregate(matched)
+			}
+		}
+
+		out.AddFeature(f)
+	}
+
+	return out, nil
+}
+
+func joinedFeature(left, right *Feature, m joinMatch, predicate JoinPredicate, opts JoinOptions) Feature {
+	f := Feature{Type: left.Type, Properties: joinProperties(left, right, opts), Coordinates: left.Coordinates}
+
+	if predicate == JoinNearest && opts.DistanceProperty != "" {
+		f.Properties[opts.DistanceProperty] = m.distance
+	}
+
+	return f
+}
+
+// joinRelated returns the right features for which the predicate holds, in the order of the right collection
+func joinRelated(left *Feature, si *SpatialIndex, rightGeometry func(int) (*facetIndex, error),
+	fn func(m IntersectionMatrix, da, db int) bool) ([]joinMatch, error) {
+	a, err := left.relateGeometry()
+	if err != nil {
+		return nil, err
+	}
+
+	candidates := si.Search(left.Bounds())
+
+	slices.Sort(candidates)
+
+	matches := make([]joinMatch, 0)
+	for _, x := range candidates {
+		b, err := rightGeometry(x)
+		if err != nil {
+			return nil, err
+		}
+
+		if fn(relate(a, b.geom), a.dim, b.geom.dim) {
+			matches = append(matches, joinMatch{index: x})
+		}
+	}
+
+	return matches, nil
+}
+
+// joinNearest returns the right features nearest to the left feature, several if they are equally near, in the
+// order of the right collection. A first candidate is found by bounding box and any nearer feature must lie within
+// its distance of the left bounding box.
+func joinNearest(left *Feature, si *SpatialIndex, rightGeometry func(int) (*facetIndex, error),
+	maxDistance float64) ([]joinMatch, error) {
+	a, err := left.relateGeometry()
+	if err != nil {
+		return nil, err
+	}
+
+	b := left.Bounds()
+	if b.IsEmpty() {
+		return nil, nil
+	}
+
+	first := si.Nearest(b.Center(), 1)
+	if len(first) == 0 {
+		return nil, nil
+	}
+
+	measure := func(x int) (float64, error) {
+		fi, err := rightGeometry(x)
+		if err != nil {
+			return 0, err
+		}
+
+		d, _, _, ok := fi.closestPoints(a)
+		if !ok {
+			return math.Inf(1), nil
+		}
+
+		return d, nil
+	}
+
+	best, err := measure(first[0])
+	if err != nil {
+		return nil, err
+	}
+
+	radius := best
+	if maxDistance > 0 {
+		radius = math.Min(radius, maxDistance)
+	}
+
+	candidates := si.Search(b.Buffer(radius))
+	slices.Sort(candidates)
+
+	distances := make([]float64, len(candidates))
+	for i, x := range candidates {
+		if distances[i], err = measure(x); err != nil {
+			return nil, err
+		}
+
+		best = math.Min(best, distances[i])
+	}
+
+	if maxDistance > 0 && best > maxDistance {
+		return nil, nil
+	}
+
+	matches := make([]joinMatch, 0, 1)
+	for i, x := range candidates {
+		if distances[i] == best {
+			matches = append(matches, joinMatch{index: x, distance: best})
+		}
+	}
+
+	return matches, nil
+}
@@ -0,0 +1,165 @@
+package gegography
+
+import (
+	"math"
+	"slices"
+	"testing"
+)
+
+func TestSpatialJoin(t *testing.T) {
+	districts := wktCollection(t,
+		"POLYGON ((0 0, 10 0, 10 10, 0 10, 0 0))",
+		"POLYGON ((10 0, 20 0, 20 10, 10 10, 10 0))",
+	)
+	districts.Features[0].Properties = map[string]any{"name": "north", "population": 100.0}
+	districts.Features[1].Properties = map[string]any{"name": "south", "population": 250.0}
+
+	observations := wktCollection(t, "POINT (5 5)", "POINT (10 5)", "POINT (15 2)", "POINT (30 30)")
+	for x := range observations.Features {
+		observations.Features[x].Properties = map[string]any{"id": float64(x), "name": "observation"}
+	}
+
+	names := func(fc FeatureCollection, key string) []any {
+		out := make([]any, len(fc.Features))
+		for x, f := range fc.Features {
+			out[x] = f.Properties[key]
+		}
+
+		return out
+	}
+
+	tests := []struct {
+		name      string
+		predicate JoinPredicate
+		opts      JoinOptions
+		key       string
+		want      []any
+	}{
+		{"within one-to-one", JoinWithin, JoinOptions{Prefix: "district_"}, "district_name", []any{"north", nil, "south", nil}},
+		{"intersects one-to-one", JoinIntersects, JoinOptions{Prefix: "district_"}, "district_name", []any{"north", "north", "south", nil}},
+		{"intersects one-to-many", JoinIntersects, JoinOptions{Prefix: "district_", Mode: JoinOneToMany}, "district_name", []any{"north", "north", "south", "south", nil}},
+		{"drop unmatched", JoinWithin, JoinOptions{Prefix: "district_", DropUnmatched: true}, "id", []any{0.0, 2.0}},
+		{"left properties win without a prefix", JoinWithin, JoinOptions{}, "name", []any{"observation", "observation", "observation", "observation"}},
+		{"right properties without a prefix", JoinWithin, JoinOptions{}, "population", []any{100.0, nil, 250.0, nil}},
+		{"touches", JoinTouches, JoinOptions{Prefix: "d_", Mode: JoinOneToMany, DropUnmatched: true}, "d_name", []any{"north", "south"}},
+		{"nearest", JoinNearest, JoinOptions{Prefix: "d_"}, "d_name", []any{"north", "north", "south", "south"}},
+		{"nearest within a distance", JoinNearest, JoinOptions{Prefix: "d_", MaxDistance: 5}, "d_name", []any{"north", "north", "south", nil}},
+		{"nearest distance", JoinNearest, JoinOptions{Prefix: "d_", DistanceProperty: "distance"}, "distance", []any{0.0, 0.0, 0.0, math.Hypot(10, 20)}},
+	}
+
+	for _, tt := range tests {
+		got, err := observations.SpatialJoin(&districts, tt.predicate, tt.opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if values := names(got, tt.key); !slices.Equal(values, tt.want) {
+			t.Errorf("SpatialJoin(districts) %s, want %s %v got %v", tt.name, tt.key, tt.want, values)
+		}
+	}
+
+	for _, f := range observations.Features {
+		if len(f.Properties) != 2 {
+			t.Errorf("SpatialJoin(districts), want the left collection unchanged got %v", f.Properties)
+		}
+	}
+}
+
+func TestSpatialJoinAggregates(t *testing.T) {
+	districts := wktCollection(t,
+		"POLYGON ((0 0, 10 0, 10 10, 0 10, 0 0))",
+		"POLYGON ((10 0, 20 0, 20 10, 10 10, 10 0))",
+		"POLYGON ((40 0, 50 0, 50 10, 40 10, 40 0))",
+	)
+
+	wells := wktCollection(t, "POINT (1 1)", "POINT (2 2)", "POINT (3 3)", "POINT (15 5)", "POINT (30 5)")
+	for x, depth := range []any{10.0, 20.0, "unknown", 40.0, 50.0} {
+		wells.Features[x].Properties = map[string]any{"depth": depth}
+	}
+
+	got, err := districts.SpatialJoin(&wells, JoinContains, JoinOptions{
+		Prefix: "well_",
+		Aggregates: []PropertyAggregate{
+			{Func: AggregateCount},
+			{Property: "depth", Func: AggregateSum},
+			{Property: "depth", Func: AggregateMean},
+			{Property: "depth", Func: AggregateCount, Name: "depths"},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []map[string]any{
+		{"id": 0.0, "well_count": 3.0, "well_depth_sum": 30.0, "well_depth_mean": 15.0, "depths": 3.0, "well_depth": 10.0},
+		{"id": 1.0, "well_count": 1.0, "well_depth_sum": 40.0, "well_depth_mean": 40.0, "depths": 1.0, "well_depth": 40.0},
+		{"id": 2.0, "well_count": 0.0, "well_depth_sum": 0.0, "well_depth_mean": nil, "depths": 0.0},
+	}
+
+	if len(got.Features) != len(want) {
+		t.Fatalf("SpatialJoin(wells, JoinContains), want %d features got %d", len(want), len(got.Features))
+	}
+
+	for x := range want {
+		if len(got.Features[x].Properties) != len(want[x]) {
+			t.Errorf("SpatialJoin(wells, JoinContains), want feature %d with %v got %v", x, want[x], got.Features[x].Properties)
+		}
+
+		for k, v := range want[x] {
+			if got.Features[x].Properties[k] != v {
+				t.Errorf("SpatialJoin(wells, JoinContains), want feature %d %s %v got %v", x, k, v, got.Features[x].Properties[k])
+			}
+		}
+	}
+}
+
+func TestSpatialJoinGeoJSON(t *testing.T) {
+	districts, err := LoadGeoJSON([]byte(`{"type": "FeatureCollection", "features": [
+		{"type": "Feature", "properties": {"district": "A"}, "geometry": {"type": "MultiPolygon",
+			"coordinates": [[[[0, 0], [4, 0], [4, 4], [0, 4], [0, 0]]], [[[6, 0], [10, 0], [10, 4], [6, 4], [6, 0]]]]}},
+		{"type": "Feature", "properties": {"district": "B"}, "geometry": {"type": "Polygon",
+			"coordinates": [[[0, 5], [10, 5], [10, 9], [0, 9], [0, 5]]]}}
+	]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	points, err := LoadGeoJSON([]byte(`{"type": "FeatureCollection", "features": [
+		{"type": "Feature", "properties": {"n": 1}, "geometry": {"type": "Point", "coordinates": [8, 2]}},
+		{"type": "Feature", "properties": {"n": 2}, "geometry": {"type": "Point", "coordinates": [5, 7]}},
+		{"type": "Feature", "properties": {"n": 3}, "geometry": {"type": "Point", "coordinates": [5, 2]}}
+	]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := points.SpatialJoin(&districts, JoinWithin, JoinOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for x, want := range []any{"A", "B", nil} {
+		if got.Features[x].Properties["district"] != want {
+			t.Errorf("SpatialJoin(districts, JoinWithin), want point %d in district %v got %v", x, want, got.Features[x].Properties["district"])
+		}
+	}
+}
+
+func TestSpatialJoinErrors(t *testing.T) {
+	square := wktCollection(t, "POLYGON ((0 0, 10 0, 10 10, 0 10, 0 0))")
+
+	if _, err := square.SpatialJoin(&square, JoinPredicate(100), JoinOptions{}); err == nil {
+		t.Error("SpatialJoin(square, JoinPredicate(100)), want an error for an unknown predicate")
+	}
+
+	for _, a := range []PropertyAggregate{{Func: AggregateSum}, {Property: "id", Func: AggregateFunc(100)}} {
+		if _, err := square.SpatialJoin(&square, JoinIntersects, JoinOptions{Aggregates: []PropertyAggregate{a}}); err == nil {
+			t.Errorf("SpatialJoin(square, JoinIntersects), want an error for the aggregate %+v", a)
+		}
+	}
+
+	bad := &FeatureCollection{Features: []Feature{{Type: "Circle", Coordinates: Point{X: 1, Y: 1}}}}
+	if _, err := bad.SpatialJoin(&square, JoinIntersects, JoinOptions{}); err == nil {
+		t.Error("SpatialJoin(square) of a Circle, want an error for an unsupported geometry type")
+	}
+}
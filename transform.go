@@ -0,0 +1,74 @@
+package gegography
+
+// pointTransform is a function applied to every coordinate of a geometry
+type pointTransform func(Point) (Point, error)
+
+func (mp MultiPoint) transform(fn pointTransform) (MultiPoint, error) {
+	out := make(MultiPoint, 0, len(mp))
+
+	for x := range mp {
+		p, err := fn(mp[x])
+		if err != nil {
+			return nil, err
+		}
+
+		out = append(out, p)
+	}
+
+	return out, nil
+}
+
+func (p Polygon) transform(fn pointTransform) (Polygon, error) {
+	out := make(Polygon, 0, len(p))
+
+	for x := range p {
+		mp, err := p[x].transform(fn)
+		if err != nil {
+			return nil, err
+		}
+
+		out = append(out, mp)
+	}
+
+	return out, nil
+}
+
+func (mp MultiPolygon) transform(fn pointTransform) (MultiPolygon, error) {
+	out := make(MultiPolygon, 0, len(mp))
+
+	for x := range mp {
+		p, err := mp[x].transform(fn)
+		if err != nil {
+			return nil, err
+		}
+
+		out = append(out, p)
+	}
+
+	return out, nil
+}
+
+// transformed returns a copy of the feature with fn applied to every coordinate
+func (f *Feature) transformed(fn pointTransform) (Feature, error) {
+	var coordinates any
+	var err error
+
+	switch f.Type {
+	case "Point":
+		coordinates, err = fn(f.Coordinates.(Point))
+	case "MultiPoint", "LineString":
+		coordinates, err = f.Coordinates.(MultiPoint).transform(fn)
+	case "Polygon", "MultiLineString":
+		coordinates, err = f.Coordinates.(Polygon).transform(fn)
+	case "MultiPolygon":
+		coordinates, err = f.Coordinates.(MultiPolygon).transform(fn)
+	default:
+		return Feature{}, GeoTypeError{Type: f.Type}
+	}
+
+	if err != nil {
+		return Feature{}, err
+	}
+
+	return Feature{Type: f.Type, Properties: f.Properties, Coordinates: coordinates}, nil
+}
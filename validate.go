@@ -0,0 +1,441 @@
+package gegography
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ValidityReason describes the kind of problem that makes a geometry invalid
+type ValidityReason int
+
+// Reasons for a geometry to be invalid according to the OGC Simple Features rules
+const (
+	InvalidCoordinate ValidityReason = iota + 1
+	RepeatedPoint
+	TooFewPoints
+	RingNotClosed
+	SelfIntersection
+	HoleOutsideShell
+	NestedHoles
+	OverlappingShells
+)
+
+func (r ValidityReason) String() string {
+	switch r {
+	case InvalidCoordinate:
+		return "invalid coordinate"
+	case RepeatedPoint:
+		return "repeated point"
+	case TooFewPoints:
+		return "too few points"
+	case RingNotClosed:
+		return "ring not closed"
+	case SelfIntersection:
+		return "self-intersection"
+	case HoleOutsideShell:
+		return "hole outside shell"
+	case NestedHoles:
+		return "nested holes"
+	case OverlappingShells:
+		return "overlapping shells"
+	}
+
+	return "unknown problem"
+}
+
+// GeoValidityError describes a single problem making a geometry invalid. Part, Ring and Feature
+// are indices into the geometry (or collection) where the problem was found, or -1 if not applicable.
+type GeoValidityError struct {
+	Reason   ValidityReason
+	Location Point
+	Feature  int
+	Part     int
+	Ring     int
+}
+
+func (g GeoValidityError) Error() string {
+	where := make([]string, 0)
+
+	if g.Feature >= 0 {
+		where = append(where, fmt.Sprintf("feature %d", g.Feature))
+	}
+	if g.Part >= 0 {
+		where = append(where, fmt.Sprintf("part %d", g.Part))
+	}
+	if g.Ring >= 0 {
+		where = append(where, fmt.Sprintf("ring %d", g.Ring))
+	}
+
+	msg := fmt.Sprintf("%s at %s", g.Reason, g.Location.toWKT())
+	if len(where) > 0 {
+		msg = fmt.Sprintf("%s (%s)", msg, strings.Join(where, ", "))
+	}
+
+	return msg
+}
+
+// GeoValidityErrors is a list of problems making one or more geometries invalid
+type GeoValidityErrors []GeoValidityError
+
+func (g GeoValidityErrors) Error() string {
+	str := make([]string, 0)
+
+	for x := range g {
+		str = append(str, g[x].Error())
+	}
+
+	return fmt.Sprintf("invalid geometry: %s", strings.Join(str, "; "))
+}
+
+func (g GeoValidityErrors) orNil() error {
+	if len(g) == 0 {
+		return nil
+	}
+
+	return g
+}
+
+func newValidityError(reason ValidityReason, p Point) GeoValidityError {
+	return GeoValidityError{Reason: reason, Location: p, Feature: -1, Part: -1, Ring: -1}
+}
+
+func withPart(errs GeoValidityErrors, part int) GeoValidityErrors {
+	for x := range errs {
+		errs[x].Part = part
+	}
+
+	return errs
+}
+
+func validateCoordinates(pts []Point) GeoValidityErrors {
+	errs := make(GeoValidityErrors, 0)
+
+	for x := range pts {
+		if !pts[x].isFinite() {
+			errs = append(errs, newValidityError(InvalidCoordinate, pts[x]))
+		}
+	}
+
+	return errs
+}
+
+func validateRepeatedPoints(pts []Point) GeoValidityErrors {
+	errs := make(GeoValidityErrors, 0)
+
+	for x := 1; x < len(pts); x++ {
+		if pts[x].equals(pts[x-1]) {
+			errs = append(errs, newValidityError(RepeatedPoint, pts[x]))
+		}
+	}
+
+	return errs
+}
+
+// Validate reports whether the point has finite coordinates
+func (p Point) Validate() error {
+	return validateCoordinates([]Point{p}).orNil()
+}
+
+// Validate reports whether all points have finite coordinates
+func (mp MultiPoint) Validate() error {
+	return validateCoordinates(mp).orNil()
+}
+
+func (mp MultiPoint) validateLine() GeoValidityErrors {
+	errs := validateCoordinates(mp)
+	if len(errs) > 0 {
+		return errs
+	}
+
+	errs = append(errs, validateRepeatedPoints(mp)...)
+
+	if len(mp) < 2 || (len(mp) == 2 && mp[0].equals(mp[1])) {
+		var p Point
+		if len(mp) > 0 {
+			p = mp[0]
+		}
+		errs = append(errs, newValidityError(TooFewPoints, p))
+	}
+
+	return errs
+}
+
+// Validate reports whether the line has finite coordinates, no repeated points and at least two distinct points
+func (ls LineString) Validate() error {
+	return MultiPoint(ls).validateLine().orNil()
+}
+
+// validateRing checks the rules that apply to a single ring in isolation
+func validateRing(r MultiPoint) GeoValidityErrors {
+	errs := validateCoordinates(r)
+	if len(errs) > 0 {
+		return errs
+	}
+
+	if len(r) == 0 {
+		return GeoValidityErrors{newValidityError(TooFewPoints, Point{})}
+	}
+
+	if !isClosed(r) {
+		errs = append(errs, newValidityError(RingNotClosed, r[len(r)-1]))
+	}
+
+	errs = append(errs, validateRepeatedPoints(r)...)
+
+	ring := ringVertices(r)
+	if len(ring) < 3 {
+		return append(errs, newValidityError(TooFewPoints, r[0]))
+	}
+
+	return append(errs, ringSelfIntersections(ring)...)
+}
+
+// ringVertices returns the distinct consecutive vertices of a ring, without the closing point
+func ringVertices(r []Point) MultiPoint {
+	out := make(MultiPoint, 0, len(r))
+
+	for x := range r {
+		if len(out) > 0 && out[len(out)-1].equals(r[x]) {
+			continue
+		}
+		out = append(out, r[x])
+	}
+
+	for len(out) > 1 && out[0].equals(out[len(out)-1]) {
+		out = out[:len(out)-1]
+	}
+
+	return out
+}
+
+// ringSelfIntersections finds points where the edges of an (implicitly closed) ring touch or cross each other
+func ringSelfIntersections(ring MultiPoint) GeoValidityErrors {
+	errs := make(GeoValidityErrors, 0)
+	n := len(ring)
+
+	for i := range n {
+		a1 := ring[i]
+		a2 := ring[(i+1)%n]
+
+		for j := i + 1; j < n; j++ {
+			b1 := ring[j]
+			b2 := ring[(j+1)%n]
+
+			is := intersectSegments(a1, a2, b1, b2)
+			if is.Kind == noIntersection {
+				continue
+			}
+
+			adjacent := j == i+1 || (i == 0 && j == n-1)
+			if adjacent && is.Kind == pointIntersection {
+				continue
+			}
+
+			errs = append(errs, newValidityError(SelfIntersection, is.Points[0]))
+		}
+	}
+
+	return errs
+}
+
+// ringsIntersect finds points where two rings cross or share an edge. Rings that only touch at points are allowed.
+func ringsIntersect(a, b MultiPoint) (Point, bool) {
+	for i := range len(a) - 1 {
+		for j := range len(b) - 1 {
+			is := intersectSegments(a[i], a[i+1], b[j], b[j+1])
+
+			if is.Kind == collinearIntersection || is.Proper {
+				return is.Points[0], true
+			}
+		}
+	}
+
+	return Point{}, false
+}
+
+// ringInside reports whether ring a lies inside ring b, judged by the first vertex of a not on the boundary of b
+func ringInside(a, b MultiPoint) bool {
+	for x := range a {
+		switch locatePointInRing(a[x], b) {
+		case locInterior:
+			return true
+		case locExterior:
+			return false
+		}
+	}
+
+	return true
+}
+
+// Validate reports every problem making the polygon invalid according to the OGC Simple Features rules
+func (p Polygon) Validate() error {
+	return p.validate().orNil()
+}
+
+func (p Polygon) validate() GeoValidityErrors {
+	if len(p) == 0 {
+		// a polygon without a shell is reported like an empty ring, and is never taken to be valid
+		return GeoValidityErrors{newValidityError(TooFewPoints, Point{})}
+	}
+
+	errs := make(GeoValidityErrors, 0)
+	valid := make([]bool, len(p))
+
+	for x := range p {
+		re := validateRing(p[x])
+		for y := range re {
+			re[y].Ring = x
+		}
+
+		errs = append(errs, re...)
+		valid[x] = len(re) == 0
+	}
+
+	if !valid[0] {
+		return errs
+	}
+
+	for x := 1; x < len(p); x++ {
+		if !valid[x] {
+			continue
+		}
+
+		if pt, ok := ringsIntersect(p[0], p[x]); ok {
+			e := newValidityError(SelfIntersection, pt)
+			e.Ring = x
+			errs = append(errs, e)
+			continue
+		}
+
+		if !ringInside(p[x], p[0]) {
+			e := newValidityError(HoleOutsideShell, p[x][0])
+			e.Ring = x
+			errs = append(errs, e)
+		}
+	}
+
+	for x := 1; x < len(p); x++ {
+		for y := x + 1; y < len(p); y++ {
+			if !valid[x] || !valid[y] {
+				continue
+			}
+
+			if pt, ok := ringsIntersect(p[x], p[y]); ok {
+				e := newValidityError(SelfIntersection, pt)
+				e.Ring = y
+				errs = append(errs, e)
+			} else if ringInside(p[y], p[x]) || ringInside(p[x], p[y]) {
+				e := newValidityError(NestedHoles, p[y][0])
+				e.Ring = y
+				errs = append(errs, e)
+			}
+		}
+	}
+
+	return errs
+}
+
+// Validate reports every problem making the multipolygon invalid according to the OGC Simple Features rules
+func (mp MultiPolygon) Validate() error {
+	return mp.validate().orNil()
+}
+
+func (mp MultiPolygon) validate() GeoValidityErrors {
+	errs := make(GeoValidityErrors, 0)
+	valid := make([]bool, len(mp))
+
+	for x := range mp {
+		pe := withPart(mp[x].validate(), x)
+		errs = append(errs, pe...)
+		valid[x] = len(pe) == 0
+	}
+
+	for x := range mp {
+		for y := x + 1; y < len(mp); y++ {
+			if !valid[x] || !valid[y] {
+				continue
+			}
+
+			if pt, ok := polygonsOverlap(mp[x], mp[y]); ok {
+				e := newValidityError(OverlappingShells, pt)
+				e.Part = y
+				errs = append(errs, e)
+			}
+		}
+	}
+
+	return errs
+}
+
+// polygonsOverlap reports whether the interiors of two valid polygons intersect, or if their shells share an edge
+func polygonsOverlap(a, b Polygon) (Point, bool) {
+	if pt, ok := ringsIntersect(a[0], b[0]); ok {
+		return pt, true
+	}
+
+	for x := range a[0] {
+		if locatePointInPolygon(a[0][x], b) == locInterior {
+			return a[0][x], true
+		}
+	}
+
+	for x := range b[0] {
+		if locatePointInPolygon(b[0][x], a) == locInterior {
+			return b[0][x], true
+		}
+	}
+
+	return Point{}, false
+}
+
+// Validate reports every problem making the feature geometry invalid according to the OGC Simple Features rules
+func (f *Feature) Validate() error {
+	var errs GeoValidityErrors
+
+	switch f.Type {
+	case "Point":
+		errs = validateCoordinates([]Point{f.Coordinates.(Point)})
+	case "MultiPoint":
+		errs = validateCoordinates(f.Coordinates.(MultiPoint))
+	case "LineString":
+		errs = f.Coordinates.(MultiPoint).validateLine()
+	case "Polygon":
+		errs = f.Coordinates.(Polygon).validate()
+	case "MultiLineString":
+		lines := f.Coordinates.(Polygon)
+		for x := range lines {
+			errs = append(errs, withPart(lines[x].validateLine(), x)...)
+		}
+	case "MultiPolygon":
+		errs = f.Coordinates.(MultiPolygon).validate()
+	default:
+		return GeoTypeError{Type: f.Type}
+	}
+
+	return errs.orNil()
+}
+
+// Validate reports every problem making any feature geometry in the collection invalid
+func (fc *FeatureCollection) Validate() error {
+	errs := make(GeoValidityErrors, 0)
+
+	for x := range fc.Features {
+		err := fc.Features[x].Validate()
+		if err == nil {
+			continue
+		}
+
+		fe, ok := err.(GeoValidityErrors)
+		if !ok {
+			return err
+		}
+
+		for y := range fe {
+			fe[y].Feature = x
+		}
+
+		errs = append(errs, fe...)
+	}
+
+	return errs.orNil()
+}
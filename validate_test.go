@@ -0,0 +1,78 @@
+package gegography
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func hasReason(err error, reason ValidityReason) bool {
+	var errs GeoValidityErrors
+	if !errors.As(err, &errs) {
+		return false
+	}
+
+	for x := range errs {
+		if errs[x].Reason == reason {
+			return true
+		}
+	}
+
+	return false
+}
+
+func TestValidatePolygon(t *testing.T) {
+	tests := []struct {
+		wkt    string
+		reason ValidityReason
+	}{
+		{"POLYGON ((0 0, 10 0, 10 10, 0 10, 0 0), (2 2, 4 2, 4 4, 2 4, 2 2))", 0},
+		{"POLYGON ((0 0, 10 0, 10 10, 0 10))", RingNotClosed},
+		{"POLYGON ((0 0, 10 0, 0 0))", TooFewPoints},
+		{"POLYGON ((0 0, 10 10, 10 0, 0 10, 0 0))", SelfIntersection},
+		{"POLYGON ((0 0, 10 0, 10 0, 10 10, 0 10, 0 0))", RepeatedPoint},
+		{"POLYGON ((0 0, 10 0, 10 10, 0 10, 0 0), (20 20, 24 20, 24 24, 20 24, 20 20))", HoleOutsideShell},
+		{"POLYGON ((0 0, 10 0, 10 10, 0 10, 0 0), (1 1, 9 1, 9 9, 1 9, 1 1), (2 2, 4 2, 4 4, 2 4, 2 2))", NestedHoles},
+		{"MULTIPOLYGON (((0 0, 10 0, 10 10, 0 10, 0 0)), ((5 5, 15 5, 15 15, 5 15, 5 5)))", OverlappingShells},
+		{"MULTIPOLYGON (((0 0, 10 0, 10 10, 0 10, 0 0)), ((10 10, 15 10, 15 15, 10 15, 10 10)))", 0},
+	}
+
+	for _, test := range tests {
+		f, err := ParseWKT(test.wkt)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = f.Validate()
+
+		if test.reason == 0 && err != nil {
+			t.Errorf("Validate(%s), want valid got %v", test.wkt, err)
+		} else if test.reason != 0 && !hasReason(err, test.reason) {
+			t.Errorf("Validate(%s), want %s got %v", test.wkt, test.reason, err)
+		}
+	}
+}
+
+func TestValidateEmptyPart(t *testing.T) {
+	square := mustParseWKT(t, "POLYGON ((0 0, 10 0, 10 10, 0 10, 0 0))").Coordinates.(Polygon)
+
+	var errs GeoValidityErrors
+	if err := (MultiPolygon{square, Polygon{}}).Validate(); !errors.As(err, &errs) || len(errs) != 1 || errs[0].Part != 1 || errs[0].Reason != TooFewPoints {
+		t.Errorf("Validate() of a multipolygon with an empty part, want too few points in part 1 got %v", err)
+	}
+
+	if err := (Polygon{}).Validate(); !hasReason(err, TooFewPoints) {
+		t.Errorf("Validate() of an empty polygon, want too few points got %v", err)
+	}
+}
+
+func TestValidateCoordinates(t *testing.T) {
+	fc := NewFeatureCollection()
+	fc.AddFeature(Feature{Type: "Point", Coordinates: Point{X: 1, Y: 2}})
+	fc.AddFeature(Feature{Type: "Point", Coordinates: Point{X: math.NaN(), Y: 2}})
+
+	var errs GeoValidityErrors
+	if err := fc.Validate(); !errors.As(err, &errs) || len(errs) != 1 || errs[0].Feature != 1 || errs[0].Reason != InvalidCoordinate {
+		t.Errorf("FeatureCollection.Validate(), want one invalid coordinate in feature 1 got %v", err)
+	}
+}